@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/EscanBE/go-lib/logging"
 	libutils "github.com/EscanBE/go-lib/utils"
 	"github.com/bcdevtools/validator-health-check/config"
 	"github.com/bcdevtools/validator-health-check/constants"
@@ -19,7 +20,7 @@ var checkCmd = &cobra.Command{
 		usersConf, err := config.LoadUsersConfig(homeDir)
 		libutils.ExitIfErr(err, "unable to load users config")
 
-		chainsConf, err := config.LoadChainsConfig(homeDir)
+		chainsConf, err := config.LoadChainsConfig(homeDir, usersConf, logging.NewDefaultLogger())
 		libutils.ExitIfErr(err, "unable to load chains config")
 
 		// Output some options to console
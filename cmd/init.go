@@ -33,8 +33,23 @@ var initCmd = &cobra.Command{
 general:
   hot-reload: 5m
   health-check: 10m
+  tls-cert-expiry-alerts: ["14d", "3d", "1d"] # alert root users before TLS certificates of managed endpoints expire
+  gov-vote-escalation: ["48h", "12h", "2h"] # escalate reminders of proposals not voted by watched validators as voting end approaches
+  # heartbeat-url: "https://hc-ping.com/<uuid>" # dead-man's switch, pinged after each successful scheduling cycle, only by the leader if high-availability is enabled
+  telegram-parse-mode: HTML # HTML || MarkdownV2, alert templates can be overridden by files <alert-type>.tmpl in templates directory of home
 worker:
   health-check-count: 5
+ha:
+  enable: false # run multiple instances against the same config, only the leader sends alerts. Pauses and silences are not carried over on takeover
+  # instance-id: "" # default: hostname-pid
+  # backend: file
+  # lease-file: leader.lease # shared by all instances, relative to home directory unless absolute
+  # lease-duration: 30s
+telegram-webhook:
+  enable: false # receive Telegram updates via webhook instead of long polling, served as plain HTTP behind a TLS terminating reverse proxy
+  # listen-address: ":8080"
+  # public-url: "https://bot.example.com/telegram" # the ID of each bot is appended as the last path segment
+  # secret-token: "" # 1-256 characters A-Z, a-z, 0-9, _ and -, checked against header of each update
 logging:
   level: info # debug || info || error
   format: json
@@ -49,6 +64,15 @@ users:
       username: "UserName1"
       id: -1
       token: "token"
+      # min-severity: info # info || warning || critical || fatal
+    # min-severity-per-chain:
+    #   test: critical
+    # timezone: "UTC" # IANA time zone name, eg: Asia/Singapore
+    # quiet-hours: ["22:00-07:00"] # non-fatal alerts are held and delivered as a digest when the window ends
+    # report: # scheduled summary of watched validators
+    #   schedule: daily # daily || weekly
+    #   at: "09:00" # in timezone of the user
+    #   weekday: monday # weekly only
 `, constants.APP_NAME))
 
 		writeYamlFile("Chain", path.Join(homeDir, fmt.Sprintf("%stest.%s", constants.CHAIN_FILE_NAME_PREFIX, constants.CONFIG_TYPE)), // trailing style: 2 spaces
@@ -62,7 +86,18 @@ validators:
   valoper1:
     watchers: []
     health-check-rpc: ""
+    # min-inbound-peers: 0 # used with health-check-rpc, negative to disable
+    # min-outbound-peers: 2 # used with health-check-rpc, negative to disable
 health-check-rpc: []
+health-check-evm-rpc: [] # EVM JSON-RPC endpoints, for Ethermint-based chains
+health-check-endpoints: [] # list of {type: rpc || evm-rpc || grpc || rest, endpoint: "..."}
+# sdk-version: "v0.47" # optional, skip detecting Cosmos-SDK version
+# gov-version: "v1" # optional, skip detecting gov module version: v1 || v1beta1
+# streaming: true # optional, subscribe to NewBlock & Tx events via websocket, to health-check within one block, polling remains as fallback. Not supported on CometBFT v0.37+
+# snapshot-reuse-blocks: 1 # optional, reuse queried validators of up to this number of blocks behind, negative to reuse at the same height only
+# explorer: # optional, URL patterns to render links in alerts
+#   validator: "https://www.mintscan.io/cosmos/validators/{valoper}"
+#   tx: "https://www.mintscan.io/cosmos/tx/{tx}"
 `)
 
 		fmt.Println("Initialized successfully!")
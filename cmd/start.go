@@ -7,23 +7,29 @@ import (
 	libcons "github.com/EscanBE/go-lib/constants"
 	libutils "github.com/EscanBE/go-lib/utils"
 	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
 	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
 	tbotreg "github.com/bcdevtools/validator-health-check/registry/telegram_bot_registry"
 	usereg "github.com/bcdevtools/validator-health-check/registry/user_registry"
+	lesvc "github.com/bcdevtools/validator-health-check/services/leader_election_svc"
 	tcsvc "github.com/bcdevtools/validator-health-check/services/telegram_call_center_svc"
+	tcctypes "github.com/bcdevtools/validator-health-check/services/telegram_call_center_svc/types"
 	tpsvc "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc"
-	"github.com/bcdevtools/validator-health-check/utils"
+	tptemplates "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/templates"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	twsvc "github.com/bcdevtools/validator-health-check/services/telegram_webhook_svc"
 	"github.com/bcdevtools/validator-health-check/work/health_check_worker"
-	workertypes "github.com/bcdevtools/validator-health-check/work/health_check_worker/types"
 	"github.com/spf13/cobra"
 	"os"
 	"os/signal"
+	"path"
 	"sync"
 	"time"
 )
 
 var (
-	waitGroup sync.WaitGroup
+	waitGroup            sync.WaitGroup
+	healthCheckScheduler *health_check_worker.Scheduler
 )
 
 var startCmd = &cobra.Command{
@@ -60,12 +66,23 @@ var startCmd = &cobra.Command{
 			defer close(tbotreg.ChannelNewBot)
 
 			// Implements close connection, resources,... here to prevent resource leak
+			drainHealthChecks(ctx)
 			safeShutdownTelegram(ctx)
+			twsvc.StopTelegramWebhookService()
+			tpsvc.FlushOutboxWL()
+			lesvc.StopLeaderElectionService()
 		})
 
 		// Listen for and trap any OS signal to gracefully shutdown and exit
 		trapExitSignal(ctx)
 
+		// Start telegram webhook receiver, before any bot is created
+		if appCfg.TelegramWebhook.Enable {
+			logger.Info("starting telegram webhook service", "address", appCfg.TelegramWebhook.GetListenAddress())
+			err := twsvc.StartTelegramWebhookService(*ctx)
+			libutils.ExitIfErr(err, "failed to start telegram webhook service")
+		}
+
 		// Launch go routines
 		logger.Debug("launching go routine to inform startup")
 		go routineInformStartup(ctx)
@@ -75,22 +92,42 @@ var startCmd = &cobra.Command{
 
 		// Start telegram pusher service
 		logger.Debug("starting telegram pusher service")
-		tpsvc.StartTelegramPusherService(*ctx)
+		tpsvc.StartTelegramPusherService(*ctx, tpsvc.NewFileOutboxStore(path.Join(homeDir, constants.OUTBOX_FILE_NAME)))
+
+		// Start leader election, before any alert can be produced
+		if appCfg.HighAvailability.Enable {
+			logger.Info("starting leader election service", "instance", appCfg.HighAvailability.GetInstanceId())
+			leaseStore, err := lesvc.NewLeaseStore(appCfg.HighAvailability, homeDir)
+			libutils.ExitIfErr(err, "failed to create lease store")
+			lesvc.StartLeaderElectionService(*ctx, leaseStore, func(previousLeader lesvc.Lease) {
+				informLeaderTakeover(ctx, previousLeader)
+			})
+		}
 
 		// Start telegram call center service
 		logger.Debug("starting telegram call center service")
-		tcsvc.StartTelegramCallCenterService(*ctx)
+		tcsvc.StartTelegramCallCenterService(
+			*ctx,
+			tcctypes.NewFileAuditLog(path.Join(homeDir, constants.AUDIT_LOG_FILE_NAME), constants.AUDIT_LOG_MAX_SIZE, constants.AUDIT_LOG_MAX_BACKUPS),
+			path.Join(homeDir, constants.SUBSCRIPTIONS_FILE_NAME),
+		)
 
 		// Start health-check workers
-		for id := 1; id <= appCfg.WorkerConfig.HealthCheckCount; id++ {
-			workerWorkingCtx := &workertypes.HcwContext{
-				WorkerID: id,
-				AppCtx:   *ctx,
-			}
+		logger.Debug("starting health-check scheduler", "workers", appCfg.WorkerConfig.HealthCheckCount)
+		healthCheckScheduler = health_check_worker.NewScheduler(*ctx)
+		healthCheckScheduler.Start(appCfg.WorkerConfig.HealthCheckCount)
 
-			logger.Debug("starting health-check worker", "wid", workerWorkingCtx.WorkerID)
-			go health_check_worker.NewHcWorker(workerWorkingCtx).Start()
-		}
+		// Start watchdog of health-checks, pings heartbeat URL after each successful scheduling cycle
+		logger.Debug("starting health-check watchdog")
+		health_check_worker.StartWatchdog(*ctx, healthCheckScheduler)
+
+		// Start scheduled reports of watched validators, composed from the health-check results
+		logger.Debug("starting report scheduler")
+		health_check_worker.StartReportScheduler(*ctx)
+
+		// Start event streaming of chains having streaming enabled
+		logger.Debug("starting event streaming service")
+		health_check_worker.StartEventStreamingService(*ctx)
 
 		// end
 		waitGroup.Wait()
@@ -160,7 +197,7 @@ func routineHotReload(ctx *config.AppContext) {
 		// Reload chains config
 		if usersConf != nil {
 			func(usersConf *config.UsersConfig) {
-				chainsConf, err := config.LoadChainsConfig(homeDir)
+				chainsConf, err := config.LoadChainsConfig(homeDir, usersConf, logger)
 				if err != nil {
 					logger.Error("failed to hot-reload chains config, failed to load", "error", err.Error())
 					return
@@ -177,6 +214,12 @@ func routineHotReload(ctx *config.AppContext) {
 				}
 			}(usersConf)
 		}
+
+		// Reload message templates, invalid templates fall back to the default template
+		templatesDir := path.Join(homeDir, constants.TEMPLATES_DIR_NAME)
+		if err := tptemplates.LoadTemplatesWL(ctx.AppConfig.General.GetTelegramParseMode(), templatesDir); err != nil {
+			logger.Error("failed to hot-reload message templates", "dir", templatesDir, "error", err.Error())
+		}
 	}
 }
 
@@ -196,12 +239,68 @@ func routineInformStartup(ctx *config.AppContext) {
 		logger.Info("waiting for telegram bots to be initialized")
 	}
 
-	safeSendTelegramMessageToAll(ctx, "startup", "validator health-check bot is started", false)
+	safeSendTelegramMessageToAll(ctx, "startup", "validator health-check bot is started"+describeInstanceRole(), false)
+}
+
+// informLeaderTakeover informs root users that the heartbeat of the previous leader disappeared and this instance took over
+func informLeaderTakeover(ctx *config.AppContext, previousLeader lesvc.Lease) {
+	_, instanceId, _, _ := lesvc.GetStatusRL()
+	ctx.Logger.Error("heartbeat of leader disappeared, took over", "previous-leader", previousLeader.Holder, "last-heartbeat", previousLeader.RenewedAt, "instance", instanceId)
+
+	message := fmt.Sprintf(
+		"[HA] heartbeat of leader instance %s disappeared (last heartbeat %s, %s ago), instance %s took over as leader.\nPauses and silences set on the previous leader are not carried over, set them again if still needed.",
+		previousLeader.Holder,
+		previousLeader.RenewedAt.Format(time.RFC3339),
+		time.Since(previousLeader.RenewedAt).Truncate(time.Second),
+		instanceId,
+	)
+
+	for _, identity := range usereg.GetRootUsersIdentityRL() {
+		userRecord, found := usereg.GetUserRecordByIdentityRL(identity)
+		if !found || userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
+			continue
+		}
+
+		tpsvc.EnqueueMessageWL(tptypes.QueueMessage{
+			ReceiverID: userRecord.TelegramConfig.UserId,
+			Priority:   true,
+			Severity:   tptypes.SeverityFatal,
+			Message:    message,
+		})
+	}
+}
+
+// describeInstanceRole returns the role of this instance when high-availability is enabled, to be appended to messages
+func describeInstanceRole() string {
+	enabled, instanceId, leader, _ := lesvc.GetStatusRL()
+	if !enabled {
+		return ""
+	}
+
+	if leader {
+		return fmt.Sprintf(" (instance %s, leader)", instanceId)
+	}
+	return fmt.Sprintf(" (instance %s, standby)", instanceId)
+}
+
+// drainHealthChecks stops dispatching new health-checks and waits for the in-flight health-checks to finish,
+// so their findings can be delivered before shutting down Telegram.
+func drainHealthChecks(ctx *config.AppContext) {
+	if healthCheckScheduler == nil {
+		return
+	}
+
+	ctx.Logger.Info("draining in-flight health-checks", "timeout", constants.HEALTH_CHECK_DRAIN_TIMEOUT)
+	if healthCheckScheduler.Shutdown(constants.HEALTH_CHECK_DRAIN_TIMEOUT) {
+		ctx.Logger.Info("drained in-flight health-checks")
+	} else {
+		ctx.Logger.Error("timed out draining in-flight health-checks, cancelled")
+	}
 }
 
 func safeShutdownTelegram(ctx *config.AppContext) {
 	tbotreg.FlagShuttingDownWL()
-	safeSendTelegramMessageToAll(ctx, "shutdown", "validator health-check bot is shutting down", true)
+	safeSendTelegramMessageToAll(ctx, "shutdown", "validator health-check bot is shutting down"+describeInstanceRole(), true)
 }
 
 func safeSendTelegramMessageToAll(ctx *config.AppContext, action string, message string, stop bool) {
@@ -211,29 +310,29 @@ func safeSendTelegramMessageToAll(ctx *config.AppContext, action string, message
 		}
 	}()
 
+	// only the leader broadcasts, prevent duplicated messages from standby instances
+	leader := lesvc.IsLeaderRL()
+	if !leader {
+		ctx.Logger.Info("not the leader, skip sending telegram message to all users", "action", action)
+	}
+
 	for _, bot := range tbotreg.GetAllTelegramBotsRL().SortByPriority() {
 		if stop {
+			if !leader {
+				// lost leadership but not yet suspended, must not deregister the webhook of the new leader
+				bot.SuspendReceivingUpdates()
+			}
 			bot.StopReceivingUpdates()
 		}
 
-		chatIds := bot.GetAllChainIdsRL()
-		if len(chatIds) > 0 {
-			if bot.IsPriorityRL() {
-				_bot := bot.GetInnerTelegramBot()
-				for _, chatId := range chatIds {
-					_, err := utils.Retry[string](func() (string, error) {
-						_, err := _bot.SendMessage(message, chatId)
-						return "", err
-					})
-					if err != nil {
-						ctx.Logger.Error("failed to send telegram message to chat", "chat-id", chatId, "action", action, "priority", bot.IsPriorityRL(), "error", err.Error())
-					}
-				}
-			} else {
-				err := bot.GetInnerTelegramBot().SendMessageToMultipleChats(message, chatIds, nil)
-				if err != nil {
-					ctx.Logger.Error("failed to send telegram message to multiple chats", "chat-count", len(chatIds), "action", action, "priority", bot.IsPriorityRL(), "error", err.Error())
-				}
+		if !leader {
+			continue
+		}
+
+		for _, chatId := range bot.GetAllChainIdsRL() {
+			_, err := tpsvc.SendMessage(bot, chatId, message)
+			if err != nil {
+				ctx.Logger.Error("failed to send telegram message to chat", "chat-id", chatId, "action", action, "priority", bot.IsPriorityRL(), "error", err.Error())
 			}
 		}
 	}
@@ -245,7 +344,7 @@ func init() {
 
 // trapExitSignal traps the signal which being emitted when interrupting the application. Implement connection/resource close to prevent resource leaks
 func trapExitSignal(ctx *config.AppContext) {
-	var sigCh = make(chan os.Signal)
+	var sigCh = make(chan os.Signal, 1)
 
 	signal.Notify(sigCh, libcons.TrapExitSignals...)
 
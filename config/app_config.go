@@ -4,29 +4,60 @@ import (
 	"fmt"
 	logtypes "github.com/EscanBE/go-lib/logging/types"
 	"github.com/bcdevtools/validator-health-check/constants"
+	"github.com/bcdevtools/validator-health-check/utils"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"net/url"
 	"os"
 	"path"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 )
 
 // AppConfig is the structure representation of configuration from `config.yaml` file
 type AppConfig struct {
-	General      GeneralConfig          `mapstructure:"general"`
-	WorkerConfig WorkerConfig           `mapstructure:"worker"`
-	Logging      logtypes.LoggingConfig `mapstructure:"logging"`
+	General          GeneralConfig          `mapstructure:"general"`
+	WorkerConfig     WorkerConfig           `mapstructure:"worker"`
+	HighAvailability HighAvailabilityConfig `mapstructure:"ha"`
+	TelegramWebhook  TelegramWebhookConfig  `mapstructure:"telegram-webhook"`
+	Logging          logtypes.LoggingConfig `mapstructure:"logging"`
 }
 
 type GeneralConfig struct {
 	HotReloadInterval   time.Duration `mapstructure:"hot-reload"`
 	HealthCheckInterval time.Duration `mapstructure:"health-check"`
+	TlsCertExpiryAlerts []string      `mapstructure:"tls-cert-expiry-alerts,omitempty"` // lead times before expiry of TLS certificates, like 14d, 3d, 1d
+	GovVoteEscalation   []string      `mapstructure:"gov-vote-escalation,omitempty"`    // lead times before voting end of proposals not voted by watched validators, like 48h, 12h, 2h
+	HeartbeatUrl        string        `mapstructure:"heartbeat-url,omitempty"`          // pinged after each successful scheduling cycle, like healthchecks.io, only by the leader if high-availability is enabled
+	TelegramParseMode   string        `mapstructure:"telegram-parse-mode,omitempty"`    // HTML or MarkdownV2, default HTML
 }
 
 type WorkerConfig struct {
 	HealthCheckCount int `mapstructure:"health-check-count"`
 }
 
+// HighAvailabilityConfig is the configuration of running multiple instances against the same config,
+// only the instance holding the leader lease sends alerts.
+// Pauses and silences are kept in memory of the leader, they are not carried over to the new leader on takeover.
+type HighAvailabilityConfig struct {
+	Enable        bool          `mapstructure:"enable"`
+	InstanceId    string        `mapstructure:"instance-id,omitempty"`    // default: hostname-pid
+	Backend       string        `mapstructure:"backend,omitempty"`        // lease store backend, default: file
+	LeaseFile     string        `mapstructure:"lease-file,omitempty"`     // used with file backend, relative to home directory unless absolute
+	LeaseDuration time.Duration `mapstructure:"lease-duration,omitempty"` // leader must renew the lease within this duration
+}
+
+// TelegramWebhookConfig is the configuration of receiving Telegram updates via webhook instead of long polling.
+// The listener serves plain HTTP, it is expected to be behind a TLS terminating reverse proxy.
+type TelegramWebhookConfig struct {
+	Enable        bool   `mapstructure:"enable"`
+	ListenAddress string `mapstructure:"listen-address,omitempty"` // default: :8080
+	PublicUrl     string `mapstructure:"public-url,omitempty"`     // HTTPS URL reachable by Telegram, the ID of each bot is appended as the last path segment
+	SecretToken   string `mapstructure:"secret-token,omitempty"`   // sent by Telegram in header of each update, 1-256 characters A-Z, a-z, 0-9, _ and -
+}
+
 // LoadAppConfig load the configuration from `config.yaml` file within the specified application's home directory
 func LoadAppConfig(homeDir string) (*AppConfig, error) {
 	cfgFile := path.Join(homeDir, constants.CONFIG_FILE_NAME)
@@ -73,10 +104,49 @@ func (c AppConfig) PrintOptions() {
 	headerPrintln("- General:")
 	headerPrintf("  + Hot-reload: %s\n", c.General.HotReloadInterval)
 	headerPrintf("  + Health-check: %s\n", c.General.HealthCheckInterval)
+	headerPrintf("  + TLS certificate expiry alerts: %s\n", func() string {
+		var leadTimes []string
+		for _, leadTime := range c.General.GetTlsCertExpiryAlertLeadTimes() {
+			leadTimes = append(leadTimes, leadTime.String())
+		}
+		return strings.Join(leadTimes, ", ")
+	}())
+	headerPrintf("  + Gov vote escalation: %s\n", func() string {
+		var leadTimes []string
+		for _, leadTime := range c.General.GetGovVoteEscalationLeadTimes() {
+			leadTimes = append(leadTimes, leadTime.String())
+		}
+		return strings.Join(leadTimes, ", ")
+	}())
+	headerPrintf("  + Telegram parse mode: %s\n", c.General.GetTelegramParseMode())
+	if c.General.HeartbeatUrl == "" {
+		headerPrintln("  + Heartbeat URL: (not set)")
+	} else if heartbeatUrl, err := url.Parse(c.General.HeartbeatUrl); err == nil {
+		headerPrintf("  + Heartbeat URL: %s://%s/***\n", heartbeatUrl.Scheme, heartbeatUrl.Host) // path may contain secret
+	}
 
 	headerPrintln("- Worker's behavior:")
 	headerPrintf("  + Health-check count: %d\n", c.WorkerConfig.HealthCheckCount)
 
+	headerPrintln("- High-availability:")
+	headerPrintf("  + Enable: %t\n", c.HighAvailability.Enable)
+	if c.HighAvailability.Enable {
+		headerPrintf("  + Instance ID: %s\n", c.HighAvailability.GetInstanceId())
+		headerPrintf("  + Backend: %s\n", c.HighAvailability.GetBackend())
+		if c.HighAvailability.GetBackend() == constants.HA_BACKEND_FILE {
+			headerPrintf("  + Lease file: %s\n", c.HighAvailability.GetLeaseFile())
+		}
+		headerPrintf("  + Lease duration: %s\n", c.HighAvailability.GetLeaseDuration())
+	}
+
+	headerPrintln("- Telegram webhook:")
+	headerPrintf("  + Enable: %t\n", c.TelegramWebhook.Enable)
+	if c.TelegramWebhook.Enable {
+		headerPrintf("  + Listen address: %s\n", c.TelegramWebhook.GetListenAddress())
+		headerPrintf("  + Public URL: %s\n", c.TelegramWebhook.PublicUrl)
+		headerPrintln("  + Secret token: ***")
+	}
+
 	headerPrintln("- Logging:")
 	if len(c.Logging.Level) < 1 {
 		headerPrintf("  + Level: %s\n", logtypes.LOG_LEVEL_DEFAULT)
@@ -91,6 +161,114 @@ func (c AppConfig) PrintOptions() {
 	}
 }
 
+// GetTlsCertExpiryAlertLeadTimes returns the configured lead times before expiry of TLS certificates to alert, sorted descending.
+// Default lead times are used if not configured.
+func (c GeneralConfig) GetTlsCertExpiryAlertLeadTimes() []time.Duration {
+	var leadTimes []time.Duration
+	for _, leadTime := range c.TlsCertExpiryAlerts {
+		duration, err := utils.ParseDurationWithDays(leadTime)
+		if err != nil || duration <= 0 {
+			continue
+		}
+		leadTimes = append(leadTimes, duration)
+	}
+
+	if len(leadTimes) == 0 {
+		leadTimes = append(leadTimes, constants.DEFAULT_TLS_CERT_EXPIRY_ALERT_LEAD_TIMES...)
+	}
+
+	sort.Slice(leadTimes, func(i, j int) bool {
+		return leadTimes[i] > leadTimes[j]
+	})
+
+	return leadTimes
+}
+
+// GetGovVoteEscalationLeadTimes returns the configured lead times before voting end of proposals to escalate reminders, sorted descending.
+// Default lead times are used if not configured.
+func (c GeneralConfig) GetGovVoteEscalationLeadTimes() []time.Duration {
+	var leadTimes []time.Duration
+	for _, leadTime := range c.GovVoteEscalation {
+		duration, err := utils.ParseDurationWithDays(leadTime)
+		if err != nil || duration <= 0 {
+			continue
+		}
+		leadTimes = append(leadTimes, duration)
+	}
+
+	if len(leadTimes) == 0 {
+		leadTimes = append(leadTimes, constants.DEFAULT_GOV_VOTE_ESCALATION_LEAD_TIMES...)
+	}
+
+	sort.Slice(leadTimes, func(i, j int) bool {
+		return leadTimes[i] > leadTimes[j]
+	})
+
+	return leadTimes
+}
+
+// GetInstanceId returns the configured instance ID, or hostname-pid if not configured
+func (c HighAvailabilityConfig) GetInstanceId() string {
+	if c.InstanceId != "" {
+		return c.InstanceId
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// GetBackend returns the configured lease store backend, or the default backend if not configured
+func (c HighAvailabilityConfig) GetBackend() string {
+	if c.Backend != "" {
+		return c.Backend
+	}
+	return constants.DEFAULT_HA_BACKEND
+}
+
+// GetLeaseFile returns the configured lease file, or the default lease file if not configured
+func (c HighAvailabilityConfig) GetLeaseFile() string {
+	if c.LeaseFile != "" {
+		return c.LeaseFile
+	}
+	return constants.DEFAULT_HA_LEASE_FILE
+}
+
+// GetLeaseFilePath returns the path of the lease file, relative paths are resolved against the home directory
+func (c HighAvailabilityConfig) GetLeaseFilePath(homeDir string) string {
+	leaseFile := c.GetLeaseFile()
+	if path.IsAbs(leaseFile) {
+		return leaseFile
+	}
+	return path.Join(homeDir, leaseFile)
+}
+
+// GetLeaseDuration returns the configured lease duration, or the default lease duration if not configured
+func (c HighAvailabilityConfig) GetLeaseDuration() time.Duration {
+	if c.LeaseDuration > 0 {
+		return c.LeaseDuration
+	}
+	return constants.DEFAULT_HA_LEASE_DURATION
+}
+
+// GetListenAddress returns the configured listen address of the webhook receiver, or the default address if not configured
+func (c TelegramWebhookConfig) GetListenAddress() string {
+	if c.ListenAddress != "" {
+		return c.ListenAddress
+	}
+	return constants.DEFAULT_TELEGRAM_WEBHOOK_LISTEN_ADDRESS
+}
+
+// GetTelegramParseMode returns the configured parse mode of Telegram alerts, or HTML if not configured
+func (c GeneralConfig) GetTelegramParseMode() string {
+	if c.TelegramParseMode == "" {
+		return constants.PARSE_MODE_HTML
+	}
+	return c.TelegramParseMode
+}
+
 // headerPrintf prints text with prefix
 func headerPrintf(format string, a ...any) {
 	fmt.Printf("[HCFG]"+format, a...)
@@ -110,11 +288,72 @@ func (c AppConfig) Validate() error {
 		return fmt.Errorf("health-check interval must be at least 30 seconds")
 	}
 
+	for _, leadTime := range c.General.TlsCertExpiryAlerts {
+		duration, err := utils.ParseDurationWithDays(leadTime)
+		if err != nil {
+			return errors.Wrapf(err, "invalid TLS certificate expiry alert lead time %s", leadTime)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("TLS certificate expiry alert lead time must be positive, got %s", leadTime)
+		}
+	}
+
+	for _, leadTime := range c.General.GovVoteEscalation {
+		duration, err := utils.ParseDurationWithDays(leadTime)
+		if err != nil {
+			return errors.Wrapf(err, "invalid gov vote escalation lead time %s", leadTime)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("gov vote escalation lead time must be positive, got %s", leadTime)
+		}
+	}
+
+	if c.General.HeartbeatUrl != "" {
+		heartbeatUrl, err := url.ParseRequestURI(c.General.HeartbeatUrl)
+		if err != nil {
+			return errors.Wrap(err, "invalid heartbeat URL")
+		}
+		if heartbeatUrl.Scheme != "http" && heartbeatUrl.Scheme != "https" {
+			return fmt.Errorf("heartbeat URL must be http or https")
+		}
+	}
+
+	switch c.General.GetTelegramParseMode() {
+	case constants.PARSE_MODE_HTML, constants.PARSE_MODE_MARKDOWNV2:
+		// ok
+	default:
+		return fmt.Errorf("invalid telegram parse mode %s, must be %s or %s", c.General.TelegramParseMode, constants.PARSE_MODE_HTML, constants.PARSE_MODE_MARKDOWNV2)
+	}
+
 	// validate Worker section
 	if c.WorkerConfig.HealthCheckCount < constants.MINIMUM_WORKER_HEALTH_CHECK {
 		return fmt.Errorf("workers health-check must be at least %d", constants.MINIMUM_WORKER_HEALTH_CHECK)
 	}
 
+	// validate High-availability section
+	if c.HighAvailability.Enable {
+		if c.HighAvailability.GetBackend() != constants.HA_BACKEND_FILE {
+			return fmt.Errorf("unknown high-availability backend %s, supported: %s", c.HighAvailability.Backend, constants.HA_BACKEND_FILE)
+		}
+		if c.HighAvailability.GetLeaseDuration() < constants.MINIMUM_HA_LEASE_DURATION {
+			return fmt.Errorf("high-availability lease duration must be at least %s", constants.MINIMUM_HA_LEASE_DURATION)
+		}
+	}
+
+	// validate Telegram webhook section
+	if c.TelegramWebhook.Enable {
+		publicUrl, err := url.ParseRequestURI(c.TelegramWebhook.PublicUrl)
+		if err != nil {
+			return errors.Wrap(err, "invalid telegram webhook public URL")
+		}
+		if publicUrl.Scheme != "https" {
+			return fmt.Errorf("telegram webhook public URL must be https")
+		}
+		if !regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`).MatchString(c.TelegramWebhook.SecretToken) {
+			return fmt.Errorf("telegram webhook secret token must be 1-256 characters A-Z, a-z, 0-9, _ and -")
+		}
+	}
+
 	// validate Logging section
 	errLogCfg := c.Logging.Validate()
 	if errLogCfg != nil {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"github.com/EscanBE/go-lib/logging"
 	"github.com/bcdevtools/validator-health-check/constants"
 	"github.com/bcdevtools/validator-health-check/utils"
 	"github.com/pkg/errors"
@@ -14,25 +15,48 @@ import (
 )
 
 type ChainConfig struct {
-	ChainName      string                           `mapstructure:"chain-name"`
-	ChainId        string                           `mapstructure:"chain-id"`
-	Disable        bool                             `mapstructure:"disable,omitempty"`
-	Priority       bool                             `mapstructure:"priority,omitempty"`
-	RPCs           []string                         `mapstructure:"rpc"`
-	Validators     map[string]*ChainValidatorConfig `mapstructure:"validators"`
-	HealthCheckRPC []string                         `mapstructure:"health-check-rpc,omitempty"`
+	ChainName            string                           `mapstructure:"chain-name"`
+	ChainId              string                           `mapstructure:"chain-id"`
+	Disable              bool                             `mapstructure:"disable,omitempty"`
+	Priority             bool                             `mapstructure:"priority,omitempty"`
+	RPCs                 []string                         `mapstructure:"rpc"`
+	Validators           map[string]*ChainValidatorConfig `mapstructure:"validators"`
+	HealthCheckRPC       []string                         `mapstructure:"health-check-rpc,omitempty"`
+	HealthCheckEvmRPC    []string                         `mapstructure:"health-check-evm-rpc,omitempty"` // EVM JSON-RPC endpoints of Ethermint-based chains
+	HealthCheckEndpoints []ChainHealthCheckEndpointConfig `mapstructure:"health-check-endpoints,omitempty"`
+	SdkVersion           string                           `mapstructure:"sdk-version,omitempty"`           // if provided, skip detecting Cosmos-SDK version of the chain
+	GovVersion           string                           `mapstructure:"gov-version,omitempty"`           // if provided, skip detecting gov module version of the chain
+	Streaming            bool                             `mapstructure:"streaming,omitempty"`             // subscribe to NewBlock and Tx events via websocket, to health-check as soon as relevant events emitted
+	SnapshotReuseBlocks  int                              `mapstructure:"snapshot-reuse-blocks,omitempty"` // reuse query snapshot of up to this number of blocks behind, zero means default, negative means same height only
+	Explorer             ChainExplorerConfig              `mapstructure:"explorer,omitempty"`
+}
+
+// ChainExplorerConfig holds the URL patterns of the block explorer, used to render links in alerts
+type ChainExplorerConfig struct {
+	Validator string `mapstructure:"validator,omitempty"` // like https://www.mintscan.io/cosmos/validators/{valoper}
+	Tx        string `mapstructure:"tx,omitempty"`        // like https://www.mintscan.io/cosmos/tx/{tx}
 }
 
 type ChainsConfig []ChainConfig
 
+// ChainHealthCheckEndpointConfig is a managed endpoint with declared type: rpc, evm-rpc, grpc or rest
+type ChainHealthCheckEndpointConfig struct {
+	Type     string `mapstructure:"type"`
+	Endpoint string `mapstructure:"endpoint"`
+}
+
 type ChainValidatorConfig struct {
 	ValidatorOperatorAddress string   `mapstructure:"-"`
 	Watchers                 []string `mapstructure:"watchers"`
-	OptionalHealthCheckRPC   string   `mapstructure:"health-check-rpc,omitempty"` // if provided, do health-check directly to this endpoint
+	OptionalHealthCheckRPC   string   `mapstructure:"health-check-rpc,omitempty"`   // if provided, do health-check directly to this endpoint
+	MinInboundPeers          int      `mapstructure:"min-inbound-peers,omitempty"`  // used with health-check-rpc, zero means default, negative means disabled
+	MinOutboundPeers         int      `mapstructure:"min-outbound-peers,omitempty"` // used with health-check-rpc, zero means default, negative means disabled
 }
 
-// LoadChainsConfig load the configuration from `chain.*.yaml` file within the specified application's home directory
-func LoadChainsConfig(homeDir string) (ChainsConfig, error) {
+// LoadChainsConfig load the configuration from `chain.*.yaml` file within the specified application's home directory,
+// merged with the subscriptions approved via Telegram.
+// Subscriptions of users not found or having incomplete Telegram config are skipped with a warning.
+func LoadChainsConfig(homeDir string, usersConfig *UsersConfig, logger logging.Logger) (ChainsConfig, error) {
 	var chainsConfig ChainsConfig
 	err := filepath.Walk(homeDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -92,6 +116,14 @@ func LoadChainsConfig(homeDir string) (ChainsConfig, error) {
 		return nil, errors.Wrap(err, "unable to read chains conf files")
 	}
 
+	subscriptionsConfig, err := LoadSubscriptionsConfig(filepath.Join(homeDir, constants.SUBSCRIPTIONS_FILE_NAME))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read subscriptions file")
+	}
+	for _, skipped := range subscriptionsConfig.MergeInto(chainsConfig, usersConfig) {
+		logger.Error("skipped subscription of user not found or having incomplete telegram config", "identity", skipped.Identity, "chain", skipped.Chain, "valoper", skipped.Valoper)
+	}
+
 	if len(chainsConfig) == 0 {
 		return nil, fmt.Errorf("no chain config found")
 	}
@@ -109,6 +141,27 @@ func (c ChainsConfig) PrintOptions() {
 		headerPrintf("    > Priority: %t\n", chainConfig.Priority)
 		headerPrintf("    > RPCs: %d\n", len(chainConfig.RPCs))
 		headerPrintf("    > Managed RPCs: %d\n", len(chainConfig.HealthCheckRPC))
+		if len(chainConfig.HealthCheckEvmRPC) > 0 {
+			headerPrintf("    > Managed EVM RPCs: %d\n", len(chainConfig.HealthCheckEvmRPC))
+		}
+		if len(chainConfig.HealthCheckEndpoints) > 0 {
+			headerPrintf("    > Managed endpoints: %d\n", len(chainConfig.HealthCheckEndpoints))
+		}
+		if chainConfig.SdkVersion != "" {
+			headerPrintf("    > SDK version: %s\n", chainConfig.SdkVersion)
+		}
+		if chainConfig.GovVersion != "" {
+			headerPrintf("    > Gov version: %s\n", chainConfig.GovVersion)
+		}
+		if chainConfig.Streaming {
+			headerPrintf("    > Streaming: %t\n", chainConfig.Streaming)
+		}
+		if chainConfig.Explorer.Validator != "" {
+			headerPrintf("    > Explorer validator: %s\n", chainConfig.Explorer.Validator)
+		}
+		if chainConfig.Explorer.Tx != "" {
+			headerPrintf("    > Explorer tx: %s\n", chainConfig.Explorer.Tx)
+		}
 		headerPrintf("    > Validators (%d): %s\n", len(chainConfig.Validators), func() string {
 			var valopers []string
 			for valoper := range chainConfig.Validators {
@@ -173,13 +226,79 @@ func (c ChainConfig) Validate() error {
 		}
 	}
 
-	if len(c.RPCs) == 0 && len(c.HealthCheckRPC) == 0 {
+	for _, evmRpc := range c.HealthCheckEvmRPC {
+		if evmRpc == "" {
+			return fmt.Errorf("Health-check-EVM-RPCs contains empty string")
+		}
+	}
+
+	var countTypedRPCs int
+	for _, endpoint := range c.HealthCheckEndpoints {
+		if endpoint.Endpoint == "" {
+			return fmt.Errorf("Health-check-endpoints contains empty endpoint")
+		}
+		switch endpoint.Type {
+		case constants.ENDPOINT_TYPE_RPC:
+			countTypedRPCs++
+		case constants.ENDPOINT_TYPE_EVM_RPC, constants.ENDPOINT_TYPE_GRPC, constants.ENDPOINT_TYPE_REST:
+			// ok
+		default:
+			return fmt.Errorf(
+				"invalid type [%s] of health-check endpoint %s, must be one of: %s, %s, %s, %s",
+				endpoint.Type, endpoint.Endpoint,
+				constants.ENDPOINT_TYPE_RPC, constants.ENDPOINT_TYPE_EVM_RPC, constants.ENDPOINT_TYPE_GRPC, constants.ENDPOINT_TYPE_REST,
+			)
+		}
+	}
+
+	if len(c.RPCs) == 0 && len(c.HealthCheckRPC) == 0 && countTypedRPCs == 0 {
 		return fmt.Errorf("either RPCs or Health-check-RPCs are required")
 	}
 
+	if c.SdkVersion != "" {
+		if _, _, success := utils.ParseMajorMinorVersion(c.SdkVersion); !success {
+			return fmt.Errorf("invalid SDK version %s, expected format like v0.47", c.SdkVersion)
+		}
+	}
+
+	switch c.GovVersion {
+	case "", constants.GOV_VERSION_V1, constants.GOV_VERSION_V1BETA1:
+		// ok
+	default:
+		return fmt.Errorf("invalid gov version %s, must be %s or %s", c.GovVersion, constants.GOV_VERSION_V1, constants.GOV_VERSION_V1BETA1)
+	}
+
+	if c.Explorer.Validator != "" && !strings.Contains(c.Explorer.Validator, constants.EXPLORER_PLACEHOLDER_VALOPER) {
+		return fmt.Errorf("explorer validator URL pattern must contain %s", constants.EXPLORER_PLACEHOLDER_VALOPER)
+	}
+	if c.Explorer.Tx != "" && !strings.Contains(c.Explorer.Tx, constants.EXPLORER_PLACEHOLDER_TX) {
+		return fmt.Errorf("explorer tx URL pattern must contain %s", constants.EXPLORER_PLACEHOLDER_TX)
+	}
+
 	return nil
 }
 
+// GetHealthCheckEndpointsByType returns the managed endpoints of the given type,
+// including the ones declared in the legacy lists `health-check-rpc` and `health-check-evm-rpc`.
+func (c ChainConfig) GetHealthCheckEndpointsByType(endpointType string) []string {
+	var endpoints []string
+
+	switch endpointType {
+	case constants.ENDPOINT_TYPE_RPC:
+		endpoints = append(endpoints, c.HealthCheckRPC...)
+	case constants.ENDPOINT_TYPE_EVM_RPC:
+		endpoints = append(endpoints, c.HealthCheckEvmRPC...)
+	}
+
+	for _, endpoint := range c.HealthCheckEndpoints {
+		if endpoint.Type == endpointType {
+			endpoints = append(endpoints, endpoint.Endpoint)
+		}
+	}
+
+	return endpoints
+}
+
 func (c ChainsConfig) Validate(usersConfig *UsersConfig) error {
 	if len(c) == 0 {
 		return fmt.Errorf("no chain config")
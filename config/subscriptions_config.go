@@ -0,0 +1,218 @@
+package config
+
+import (
+	"encoding/json"
+	"github.com/bcdevtools/validator-health-check/constants"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SubscriptionsConfig is the managed overlay of watchers, maintained via Telegram commands
+// and merged with the hand-written `chain.*.yaml` files when loading chains config.
+type SubscriptionsConfig struct {
+	NextRequestId uint64                `json:"next-request-id"`
+	Pending       []SubscriptionRequest `json:"pending,omitempty"`  // waiting for root approval
+	Approved      []Subscription        `json:"approved,omitempty"` // merged into chains config
+}
+
+type SubscriptionRequest struct {
+	Id          uint64    `json:"id"`
+	Identity    string    `json:"identity"`
+	Chain       string    `json:"chain"`
+	Valoper     string    `json:"valoper"`
+	RequestedAt time.Time `json:"requested-at"`
+}
+
+type Subscription struct {
+	Identity   string    `json:"identity"`
+	Chain      string    `json:"chain"`
+	Valoper    string    `json:"valoper"`
+	ApprovedBy string    `json:"approved-by"`
+	ApprovedAt time.Time `json:"approved-at"`
+}
+
+// LoadSubscriptionsConfig loads the managed subscriptions file, empty if the file does not exist
+func LoadSubscriptionsConfig(file string) (*SubscriptionsConfig, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &SubscriptionsConfig{}, nil
+		}
+		return nil, errors.Wrap(err, "failed to read subscriptions file")
+	}
+
+	conf := &SubscriptionsConfig{}
+	if err := json.Unmarshal(bz, conf); err != nil {
+		return nil, errors.Wrap(err, "failed to decode subscriptions file")
+	}
+	return conf, nil
+}
+
+// Save writes the subscriptions to the file, replaced atomically
+func (c *SubscriptionsConfig) Save(file string) error {
+	bz, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode subscriptions")
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp subscriptions file")
+	}
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	_, err = tmpFile.Write(bz)
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	if errClose := tmpFile.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to write temp subscriptions file")
+	}
+
+	if err := os.Chmod(tmpFile.Name(), constants.FILE_PERMISSION); err != nil {
+		return errors.Wrap(err, "failed to set permission of temp subscriptions file")
+	}
+
+	return errors.Wrap(os.Rename(tmpFile.Name(), file), "failed to replace subscriptions file")
+}
+
+// Request queues a subscription request for root approval.
+// If the same request is already pending, the pending one is returned.
+func (c *SubscriptionsConfig) Request(identity, chain, valoper string, now time.Time) (request SubscriptionRequest, duplicated bool) {
+	for _, pending := range c.Pending {
+		if pending.Identity == identity && pending.Chain == chain && pending.Valoper == valoper {
+			return pending, true
+		}
+	}
+
+	c.NextRequestId++
+	request = SubscriptionRequest{
+		Id:          c.NextRequestId,
+		Identity:    identity,
+		Chain:       chain,
+		Valoper:     valoper,
+		RequestedAt: now,
+	}
+	c.Pending = append(c.Pending, request)
+	return request, false
+}
+
+// Approve moves the pending request into the approved subscriptions
+func (c *SubscriptionsConfig) Approve(requestId uint64, approvedBy string, now time.Time) (request SubscriptionRequest, found bool) {
+	request, found = c.removePending(requestId)
+	if !found {
+		return
+	}
+
+	if !c.IsSubscribed(request.Identity, request.Chain, request.Valoper) {
+		c.Approved = append(c.Approved, Subscription{
+			Identity:   request.Identity,
+			Chain:      request.Chain,
+			Valoper:    request.Valoper,
+			ApprovedBy: approvedBy,
+			ApprovedAt: now,
+		})
+	}
+	return
+}
+
+// Deny removes the pending request
+func (c *SubscriptionsConfig) Deny(requestId uint64) (request SubscriptionRequest, found bool) {
+	return c.removePending(requestId)
+}
+
+// Unsubscribe removes the approved subscriptions and cancels the pending requests of the user to the validator
+func (c *SubscriptionsConfig) Unsubscribe(identity, valoper string) (removed, cancelled int) {
+	approved := make([]Subscription, 0, len(c.Approved))
+	for _, subscription := range c.Approved {
+		if subscription.Identity == identity && subscription.Valoper == valoper {
+			removed++
+			continue
+		}
+		approved = append(approved, subscription)
+	}
+	c.Approved = approved
+
+	pending := make([]SubscriptionRequest, 0, len(c.Pending))
+	for _, request := range c.Pending {
+		if request.Identity == identity && request.Valoper == valoper {
+			cancelled++
+			continue
+		}
+		pending = append(pending, request)
+	}
+	c.Pending = pending
+
+	return
+}
+
+func (c *SubscriptionsConfig) IsSubscribed(identity, chain, valoper string) bool {
+	for _, subscription := range c.Approved {
+		if subscription.Identity == identity && subscription.Chain == chain && subscription.Valoper == valoper {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *SubscriptionsConfig) removePending(requestId uint64) (request SubscriptionRequest, found bool) {
+	for i, pending := range c.Pending {
+		if pending.Id == requestId {
+			c.Pending = append(c.Pending[:i], c.Pending[i+1:]...)
+			return pending, true
+		}
+	}
+	return
+}
+
+// MergeInto adds the approved subscriptions as watchers into the chains config.
+// Subscriptions to chains no longer configured are ignored.
+// Subscriptions of users not found or having incomplete Telegram config are skipped and returned,
+// so a removed user does not invalidate the chains config.
+func (c *SubscriptionsConfig) MergeInto(chainsConfig ChainsConfig, usersConfig *UsersConfig) (skipped []Subscription) {
+	for _, subscription := range c.Approved {
+		if userRecord, found := usersConfig.Users[subscription.Identity]; !found || userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
+			skipped = append(skipped, subscription)
+			continue
+		}
+
+		for i := range chainsConfig {
+			chainConfig := &chainsConfig[i]
+			if chainConfig.ChainName != subscription.Chain {
+				continue
+			}
+
+			if chainConfig.Validators == nil {
+				chainConfig.Validators = make(map[string]*ChainValidatorConfig)
+			}
+
+			validatorConfig, found := chainConfig.Validators[subscription.Valoper]
+			if !found {
+				validatorConfig = &ChainValidatorConfig{
+					ValidatorOperatorAddress: subscription.Valoper,
+				}
+				chainConfig.Validators[subscription.Valoper] = validatorConfig
+			}
+
+			var alreadyWatcher bool
+			for _, watcher := range validatorConfig.Watchers {
+				if watcher == subscription.Identity {
+					alreadyWatcher = true
+					break
+				}
+			}
+			if !alreadyWatcher {
+				validatorConfig.Watchers = append(validatorConfig.Watchers, subscription.Identity)
+			}
+		}
+	}
+
+	return
+}
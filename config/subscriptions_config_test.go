@@ -0,0 +1,98 @@
+package config
+
+import (
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionsConfig(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	const valoper = "cosmosvaloper1abc"
+
+	conf := &SubscriptionsConfig{}
+	request, duplicated := conf.Request("alice", "cosmos", valoper, now)
+	require.False(t, duplicated)
+	require.Equal(t, uint64(1), request.Id)
+
+	request, duplicated = conf.Request("alice", "cosmos", valoper, now)
+	require.True(t, duplicated)
+	require.Equal(t, uint64(1), request.Id)
+
+	request, _ = conf.Request("bob", "cosmos", valoper, now)
+	require.Equal(t, uint64(2), request.Id)
+
+	_, found := conf.Approve(99, "root", now)
+	require.False(t, found)
+
+	_, found = conf.Approve(1, "root", now)
+	require.True(t, found)
+	require.True(t, conf.IsSubscribed("alice", "cosmos", valoper))
+
+	_, found = conf.Deny(2)
+	require.True(t, found)
+	require.Empty(t, conf.Pending)
+	require.False(t, conf.IsSubscribed("bob", "cosmos", valoper))
+
+	// save and load
+	file := filepath.Join(t.TempDir(), "subscriptions.json")
+	require.NoError(t, conf.Save(file))
+	loaded, err := LoadSubscriptionsConfig(file)
+	require.NoError(t, err)
+	require.Equal(t, conf.Approved, loaded.Approved)
+	require.Equal(t, conf.NextRequestId, loaded.NextRequestId)
+
+	loaded, err = LoadSubscriptionsConfig(filepath.Join(t.TempDir(), "not-exists.json"))
+	require.NoError(t, err)
+	require.Empty(t, loaded.Approved)
+
+	// merge
+	conf.Approved = append(conf.Approved,
+		Subscription{Identity: "bob", Chain: "cosmos", Valoper: "cosmosvaloper1new"},
+		Subscription{Identity: "bob", Chain: "removed-chain", Valoper: valoper},
+	)
+	chainsConfig := ChainsConfig{
+		{
+			ChainName: "cosmos",
+			Validators: map[string]*ChainValidatorConfig{
+				valoper: {ValidatorOperatorAddress: valoper, Watchers: []string{"root", "alice"}},
+			},
+		},
+	}
+	validTelegramConfig := &UserTelegramConfig{Username: "user", UserId: 1, Token: "token"}
+	usersConfig := &UsersConfig{
+		Users: map[string]UserRecord{
+			"root":  {Identity: "root", Root: true, TelegramConfig: validTelegramConfig},
+			"alice": {Identity: "alice", TelegramConfig: validTelegramConfig},
+			"bob":   {Identity: "bob", TelegramConfig: validTelegramConfig},
+			"carol": {Identity: "carol", TelegramConfig: &UserTelegramConfig{Username: "carol"}},
+		},
+	}
+	skipped := conf.MergeInto(chainsConfig, usersConfig)
+	require.Empty(t, skipped)
+	require.Equal(t, []string{"root", "alice"}, chainsConfig[0].Validators[valoper].Watchers, "already watcher")
+	require.Equal(t, []string{"bob"}, chainsConfig[0].Validators["cosmosvaloper1new"].Watchers)
+	require.Len(t, chainsConfig, 1)
+
+	// subscriptions of users removed or having incomplete telegram config are skipped
+	invalidSubscriptions := []Subscription{
+		{Identity: "carol", Chain: "cosmos", Valoper: valoper},
+		{Identity: "removed-user", Chain: "cosmos", Valoper: "cosmosvaloper1removed"},
+	}
+	withInvalid := &SubscriptionsConfig{Approved: append(append([]Subscription{}, conf.Approved...), invalidSubscriptions...)}
+	skipped = withInvalid.MergeInto(chainsConfig, usersConfig)
+	require.Equal(t, invalidSubscriptions, skipped)
+	require.Equal(t, []string{"root", "alice"}, chainsConfig[0].Validators[valoper].Watchers)
+	require.NotContains(t, chainsConfig[0].Validators, "cosmosvaloper1removed")
+
+	// unsubscribe
+	conf.Request("alice", "cosmos", "cosmosvaloper1other", now)
+	removed, cancelled := conf.Unsubscribe("alice", valoper)
+	require.Equal(t, 1, removed)
+	require.Equal(t, 0, cancelled)
+	removed, cancelled = conf.Unsubscribe("alice", "cosmosvaloper1other")
+	require.Equal(t, 0, removed)
+	require.Equal(t, 1, cancelled)
+	require.False(t, conf.IsSubscribed("alice", "cosmos", valoper))
+}
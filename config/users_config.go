@@ -9,6 +9,9 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // embed time zone database, time zones of users must be available on any host
 )
 
 type UsersConfig struct {
@@ -16,17 +19,28 @@ type UsersConfig struct {
 }
 
 type UserRecord struct {
-	Identity       string              `mapstructure:"-"`
-	Root           bool                `mapstructure:"root"`
-	TelegramConfig *UserTelegramConfig `mapstructure:"telegram,omitempty"`
+	Identity            string              `mapstructure:"-"`
+	Root                bool                `mapstructure:"root"`
+	TelegramConfig      *UserTelegramConfig `mapstructure:"telegram,omitempty"`
+	MinSeverityPerChain map[string]string   `mapstructure:"min-severity-per-chain,omitempty"` // chain name -> minimum severity of alerts to receive
+	Timezone            string              `mapstructure:"timezone,omitempty"`               // IANA time zone name, eg: Asia/Singapore, default UTC
+	QuietHours          []string            `mapstructure:"quiet-hours,omitempty"`            // windows in time zone of the user, eg: 22:00-07:00
+	Report              *UserReportConfig   `mapstructure:"report,omitempty"`                 // scheduled summary of watched validators
 }
 
 type UserRecords []UserRecord
 
 type UserTelegramConfig struct {
-	Username string `mapstructure:"username"`
-	UserId   int64  `mapstructure:"id"`
-	Token    string `mapstructure:"token"` // token used to send message to this user
+	Username    string `mapstructure:"username"`
+	UserId      int64  `mapstructure:"id"`
+	Token       string `mapstructure:"token"`                  // token used to send message to this user
+	MinSeverity string `mapstructure:"min-severity,omitempty"` // minimum severity of alerts to receive via Telegram
+}
+
+type UserReportConfig struct {
+	Schedule string `mapstructure:"schedule"`          // daily || weekly, disabled if empty
+	At       string `mapstructure:"at,omitempty"`      // HH:MM in time zone of the user
+	Weekday  string `mapstructure:"weekday,omitempty"` // weekly report only
 }
 
 // LoadUsersConfig load the configuration from `users.yaml` file within the specified application's home directory
@@ -84,9 +98,25 @@ func (c UsersConfig) PrintOptions() {
 				}
 				return "yes"
 			}())
+			headerPrintf("    > Telegram min severity: %s\n", userRecord.TelegramConfig.GetMinSeverity())
 		} else {
 			headerPrintf("    > No telegram configuration\n")
 		}
+		for chainName, minSeverity := range userRecord.MinSeverityPerChain {
+			headerPrintf("    > Min severity on chain %s: %s\n", chainName, minSeverity)
+		}
+		headerPrintf("    > Timezone: %s\n", userRecord.GetLocation().String())
+		if len(userRecord.QuietHours) > 0 {
+			headerPrintf("    > Quiet hours: %s\n", strings.Join(userRecord.QuietHours, ", "))
+		}
+		if userRecord.Report.IsEnabled() {
+			headerPrintf("    > Report: %s at %s%s\n", userRecord.Report.Schedule, userRecord.Report.GetAt(), func() string {
+				if userRecord.Report.Schedule == constants.REPORT_SCHEDULE_WEEKLY {
+					return " on " + userRecord.Report.GetWeekday()
+				}
+				return ""
+			}())
+		}
 	}
 }
 
@@ -129,10 +159,95 @@ func (r UserRecord) Validate() error {
 	if r.TelegramConfig.IsEmptyOrIncompleteConfig() {
 		return fmt.Errorf("telegram config is incomplete")
 	}
+	if r.TelegramConfig.MinSeverity != "" && !isValidSeverity(r.TelegramConfig.MinSeverity) {
+		return fmt.Errorf("invalid telegram min severity %s", r.TelegramConfig.MinSeverity)
+	}
+
+	// min severity per chain
+	for chainName, minSeverity := range r.MinSeverityPerChain {
+		if !isValidSeverity(minSeverity) {
+			return fmt.Errorf("invalid min severity %s of chain %s", minSeverity, chainName)
+		}
+	}
+
+	// timezone & quiet hours
+	if r.Timezone != "" {
+		if _, err := loadLocation(r.Timezone); err != nil {
+			return errors.Wrapf(err, "invalid timezone %s", r.Timezone)
+		}
+	}
+	for _, quietHours := range r.QuietHours {
+		if _, _, err := parseQuietHoursWindow(quietHours); err != nil {
+			return errors.Wrapf(err, "invalid quiet hours %s", quietHours)
+		}
+	}
+
+	// report
+	if r.Report != nil {
+		if err := r.Report.Validate(); err != nil {
+			return errors.Wrap(err, "invalid report config")
+		}
+	}
 
 	return nil
 }
 
+// GetLocation returns the time zone of the user, UTC if not configured
+func (r UserRecord) GetLocation() *time.Location {
+	if r.Timezone == "" {
+		return time.UTC
+	}
+
+	location, err := loadLocation(r.Timezone)
+	if err != nil {
+		return time.UTC // validated when loading config
+	}
+	return location
+}
+
+// IsQuietHoursAt returns true if the given time falls into any quiet hours window of the user
+func (r UserRecord) IsQuietHoursAt(t time.Time) bool {
+	if len(r.QuietHours) < 1 {
+		return false
+	}
+
+	localTime := t.In(r.GetLocation())
+	minuteOfDay := localTime.Hour()*60 + localTime.Minute()
+
+	for _, quietHours := range r.QuietHours {
+		start, end, err := parseQuietHoursWindow(quietHours)
+		if err != nil {
+			continue // validated when loading config
+		}
+
+		if start <= end {
+			if minuteOfDay >= start && minuteOfDay < end {
+				return true
+			}
+		} else if minuteOfDay >= start || minuteOfDay < end { // over midnight
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMinSeverityOfChainName returns the minimum severity of alerts of the chain to be received, empty if not configured.
+func (r UserRecord) GetMinSeverityOfChainName(chainName string) string {
+	if minSeverity, found := r.MinSeverityPerChain[chainName]; found {
+		return minSeverity
+	}
+
+	// keys are lower-cased when reading from config
+	for configuredChainName, minSeverity := range r.MinSeverityPerChain {
+		if strings.EqualFold(configuredChainName, chainName) {
+			return minSeverity
+		}
+	}
+
+	return ""
+}
+
 func (r UserRecords) Validate() error {
 	if len(r) < 1 {
 		return fmt.Errorf("no user record")
@@ -173,6 +288,147 @@ func (r UserRecords) Validate() error {
 	return nil
 }
 
+// GetReportPeriod returns the period covered by the latest scheduled report at the given time, false if report is disabled
+func (r UserRecord) GetReportPeriod(now time.Time) (since, until time.Time, enabled bool) {
+	if !r.Report.IsEnabled() {
+		return
+	}
+
+	atMinuteOfDay, err := parseMinuteOfDay(r.Report.GetAt())
+	if err != nil {
+		return // validated when loading config
+	}
+
+	localNow := now.In(r.GetLocation())
+	until = time.Date(localNow.Year(), localNow.Month(), localNow.Day(), atMinuteOfDay/60, atMinuteOfDay%60, 0, 0, localNow.Location())
+	if until.After(localNow) {
+		until = until.AddDate(0, 0, -1)
+	}
+
+	if r.Report.Schedule == constants.REPORT_SCHEDULE_WEEKLY {
+		weekday, err := parseWeekday(r.Report.GetWeekday())
+		if err != nil {
+			return // validated when loading config
+		}
+		for until.Weekday() != weekday {
+			until = until.AddDate(0, 0, -1)
+		}
+		since = until.AddDate(0, 0, -7)
+	} else {
+		since = until.AddDate(0, 0, -1)
+	}
+
+	enabled = true
+	return
+}
+
+func (c *UserReportConfig) IsEnabled() bool {
+	return c != nil && c.Schedule != ""
+}
+
+// GetAt returns the time of day to send the report, in time zone of the user
+func (c *UserReportConfig) GetAt() string {
+	if c == nil || c.At == "" {
+		return constants.DEFAULT_REPORT_AT
+	}
+	return c.At
+}
+
+// GetWeekday returns the day of week to send the weekly report
+func (c *UserReportConfig) GetWeekday() string {
+	if c == nil || c.Weekday == "" {
+		return constants.DEFAULT_REPORT_WEEKDAY
+	}
+	return c.Weekday
+}
+
+func (c *UserReportConfig) Validate() error {
+	switch c.Schedule {
+	case "", constants.REPORT_SCHEDULE_DAILY, constants.REPORT_SCHEDULE_WEEKLY:
+	default:
+		return fmt.Errorf("schedule must be one of: %s, %s", constants.REPORT_SCHEDULE_DAILY, constants.REPORT_SCHEDULE_WEEKLY)
+	}
+	if _, err := parseMinuteOfDay(c.GetAt()); err != nil {
+		return err
+	}
+	if _, err := parseWeekday(c.GetWeekday()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetMinSeverity returns the minimum severity of alerts to be received via Telegram
+func (c *UserTelegramConfig) GetMinSeverity() string {
+	if c == nil || c.MinSeverity == "" {
+		return constants.DEFAULT_MIN_SEVERITY
+	}
+	return c.MinSeverity
+}
+
 func (c *UserTelegramConfig) IsEmptyOrIncompleteConfig() bool {
 	return c == nil || c.Username == "" || c.UserId == 0 || c.Token == ""
 }
+
+// parseQuietHoursWindow parses window in format HH:MM-HH:MM, returns start and end as minute of day
+func parseQuietHoursWindow(window string) (start, end int, err error) {
+	spl := strings.Split(window, "-")
+	if len(spl) != 2 {
+		err = fmt.Errorf("must be in format HH:MM-HH:MM")
+		return
+	}
+
+	if start, err = parseMinuteOfDay(spl[0]); err != nil {
+		return
+	}
+	if end, err = parseMinuteOfDay(spl[1]); err != nil {
+		return
+	}
+	if start == end {
+		err = fmt.Errorf("start and end must be different")
+	}
+	return
+}
+
+// parseMinuteOfDay parses time of day in format HH:MM, returns minute of day
+func parseMinuteOfDay(hhmm string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(hhmm))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s, must be in format HH:MM", hhmm)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func parseWeekday(name string) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(weekday.String(), name) {
+			return weekday, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("invalid weekday %s", name)
+}
+
+var cacheLocations sync.Map // time zone name -> *time.Location
+
+// loadLocation loads the time zone with cache, to prevent reading time zone database repeatedly
+func loadLocation(name string) (*time.Location, error) {
+	if cached, found := cacheLocations.Load(name); found {
+		return cached.(*time.Location), nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheLocations.Store(name, location)
+	return location, nil
+}
+
+func isValidSeverity(severity string) bool {
+	switch severity {
+	case constants.SEVERITY_INFO, constants.SEVERITY_WARNING, constants.SEVERITY_CRITICAL, constants.SEVERITY_FATAL:
+		return true
+	default:
+		return false
+	}
+}
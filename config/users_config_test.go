@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestUserRecord_Validate(t *testing.T) {
@@ -111,13 +112,104 @@ func TestUserRecord_Validate(t *testing.T) {
 			wantErr:         true,
 			wantErrContains: "telegram token must be set",
 		},
+		{
+			name: "pass with min severity",
+			userRecord: UserRecord{
+				Identity: "1",
+				TelegramConfig: &UserTelegramConfig{
+					Username:    "1",
+					UserId:      1,
+					Token:       "1",
+					MinSeverity: "warning",
+				},
+				MinSeverityPerChain: map[string]string{
+					"cosmoshub-4": "critical",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "telegram min severity validation",
+			userRecord: UserRecord{
+				Identity: "1",
+				TelegramConfig: &UserTelegramConfig{
+					Username:    "1",
+					UserId:      1,
+					Token:       "1",
+					MinSeverity: "error",
+				},
+			},
+			wantErr:         true,
+			wantErrContains: "invalid telegram min severity",
+		},
+		{
+			name: "min severity per chain validation",
+			userRecord: UserRecord{
+				Identity: "1",
+				TelegramConfig: &UserTelegramConfig{
+					Username: "1",
+					UserId:   1,
+					Token:    "1",
+				},
+				MinSeverityPerChain: map[string]string{
+					"cosmoshub-4": "Fatal",
+				},
+			},
+			wantErr:         true,
+			wantErrContains: "invalid min severity Fatal of chain cosmoshub-4",
+		},
+		{
+			name: "pass with timezone and quiet hours",
+			userRecord: UserRecord{
+				Identity: "1",
+				TelegramConfig: &UserTelegramConfig{
+					Username: "1",
+					UserId:   1,
+					Token:    "1",
+				},
+				Timezone:   "Asia/Singapore",
+				QuietHours: []string{"22:00-07:00", "12:00-13:30"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "timezone validation",
+			userRecord: UserRecord{
+				Identity: "1",
+				TelegramConfig: &UserTelegramConfig{
+					Username: "1",
+					UserId:   1,
+					Token:    "1",
+				},
+				Timezone: "Mars/Olympus",
+			},
+			wantErr:         true,
+			wantErrContains: "invalid timezone",
+		},
+		{
+			name: "quiet hours validation",
+			userRecord: UserRecord{
+				Identity: "1",
+				TelegramConfig: &UserTelegramConfig{
+					Username: "1",
+					UserId:   1,
+					Token:    "1",
+				},
+				QuietHours: []string{"22:00"},
+			},
+			wantErr:         true,
+			wantErrContains: "invalid quiet hours",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := UserRecord{
-				Identity:       tt.userRecord.Identity,
-				Root:           tt.userRecord.Root,
-				TelegramConfig: tt.userRecord.TelegramConfig,
+				Identity:            tt.userRecord.Identity,
+				Root:                tt.userRecord.Root,
+				TelegramConfig:      tt.userRecord.TelegramConfig,
+				MinSeverityPerChain: tt.userRecord.MinSeverityPerChain,
+				Timezone:            tt.userRecord.Timezone,
+				QuietHours:          tt.userRecord.QuietHours,
 			}
 			if tt.wantErr {
 				err := r.Validate()
@@ -130,3 +222,58 @@ func TestUserRecord_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRecord_IsQuietHoursAt(t *testing.T) {
+	userRecord := UserRecord{
+		Timezone:   "Asia/Singapore", // UTC+8
+		QuietHours: []string{"22:00-07:00", "12:00-13:30"},
+	}
+
+	at := func(hhmmUTC string) time.Time {
+		tUTC, err := time.Parse(time.DateTime, "2024-01-01 "+hhmmUTC+":00")
+		require.NoError(t, err)
+		return tUTC
+	}
+
+	require.True(t, userRecord.IsQuietHoursAt(at("14:00")), "22:00 local")
+	require.True(t, userRecord.IsQuietHoursAt(at("22:59")), "06:59 local")
+	require.False(t, userRecord.IsQuietHoursAt(at("23:00")), "07:00 local, window ended")
+	require.False(t, userRecord.IsQuietHoursAt(at("13:59")), "21:59 local")
+	require.True(t, userRecord.IsQuietHoursAt(at("04:00")), "12:00 local")
+	require.False(t, userRecord.IsQuietHoursAt(at("05:30")), "13:30 local, window ended")
+
+	require.False(t, UserRecord{}.IsQuietHoursAt(at("00:00")))
+	require.Equal(t, time.UTC, UserRecord{}.GetLocation())
+}
+
+func TestUserRecord_GetReportPeriod(t *testing.T) {
+	now := time.Date(2024, 1, 3, 2, 0, 0, 0, time.UTC) // Wednesday, 10:00 in Asia/Singapore
+
+	_, _, enabled := UserRecord{}.GetReportPeriod(now)
+	require.False(t, enabled)
+
+	daily := UserRecord{
+		Timezone: "Asia/Singapore",
+		Report:   &UserReportConfig{Schedule: "daily", At: "09:30"},
+	}
+	since, until, enabled := daily.GetReportPeriod(now)
+	require.True(t, enabled)
+	require.Equal(t, time.Date(2024, 1, 3, 1, 30, 0, 0, time.UTC), until.UTC())
+	require.Equal(t, until.Add(-24*time.Hour), since)
+
+	daily.Report.At = "11:00" // not yet today
+	_, until, _ = daily.GetReportPeriod(now)
+	require.Equal(t, time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC), until.UTC())
+
+	weekly := UserRecord{
+		Report: &UserReportConfig{Schedule: "weekly", Weekday: "Monday"},
+	}
+	since, until, enabled = weekly.GetReportPeriod(now)
+	require.True(t, enabled)
+	require.Equal(t, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), until.UTC(), "default at 09:00 on Monday")
+	require.Equal(t, time.Date(2023, 12, 25, 9, 0, 0, 0, time.UTC), since.UTC())
+
+	require.Error(t, (&UserReportConfig{Schedule: "monthly"}).Validate())
+	require.Error(t, (&UserReportConfig{Schedule: "weekly", Weekday: "someday"}).Validate())
+	require.Error(t, (&UserReportConfig{Schedule: "daily", At: "25:00"}).Validate())
+}
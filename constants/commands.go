@@ -1,13 +1,24 @@
 package constants
 
 const (
-	CommandMe         = "me"
-	CommandHelp       = "help"
-	CommandChains     = "chains"
-	CommandValidators = "validators"
-	CommandPause      = "pause"
-	CommandStatus     = "status"
-	CommandLast       = "last"
-	CommandSearch     = "search"
-	CommandSilent     = "silent"
+	CommandMe          = "me"
+	CommandHelp        = "help"
+	CommandChains      = "chains"
+	CommandValidators  = "validators"
+	CommandPause       = "pause"
+	CommandUnpause     = "unpause"
+	CommandStatus      = "status"
+	CommandLast        = "last"
+	CommandSearch      = "search"
+	CommandSilent      = "silent"
+	CommandUnsilence   = "unsilence"
+	CommandOutbox      = "outbox"
+	CommandSubscribe   = "subscribe"
+	CommandUnsubscribe = "unsubscribe"
+	CommandApprove     = "approve"
+	CommandDeny        = "deny"
+	CommandCheck       = "check"
+	CommandGov         = "gov"
+	CommandRpc         = "rpc"
+	CommandAudit       = "audit"
 )
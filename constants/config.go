@@ -11,11 +11,94 @@ const (
 const (
 	MINIMUM_BETWEEN_TELEGRAM_PUSH_SAME_USER = 1 * time.Minute
 
-	BATCH_SIZE_TELEGRAM_PUSH_PER_USER = 20
+	TELEGRAM_BOT_MESSAGES_PER_SECOND  = 30 // global limit of Telegram API per bot
+	TELEGRAM_CHAT_MESSAGES_PER_SECOND = 1  // limit of Telegram API per chat
+	TELEGRAM_SEND_MAX_ATTEMPTS        = 5
+	TELEGRAM_SEND_MIN_BACKOFF         = 1 * time.Second // backoff of transient failures, doubled each attempt
+	TELEGRAM_PUSH_CONCURRENCY         = 8               // number of receiver queues drained concurrently
+
+	OUTBOX_MAX_ATTEMPTS      = 20             // delivery attempts before moving message to dead-letter
+	OUTBOX_MAX_RETRY_BACKOFF = 1 * time.Hour  // maximum back-off between delivery attempts
+	OUTBOX_MESSAGE_MAX_AGE   = 24 * time.Hour // messages not delivered within this duration are moved to dead-letter
+	OUTBOX_MAX_DEAD_LETTERS  = 200
+	OUTBOX_MAX_RECEIPTS      = 500
+
+	AUDIT_LOG_MAX_SIZE        = 10 * 1024 * 1024 // bytes, the audit log is rotated when reaching this size
+	AUDIT_LOG_MAX_BACKUPS     = 5                // rotated audit log files kept, the oldest one is removed
+	AUDIT_LOG_DEFAULT_ENTRIES = 10               // entries shown by /audit command by default
+	AUDIT_LOG_MAX_ENTRIES     = 50               // maximum entries shown by /audit command
+
+	BATCH_SIZE_TELEGRAM_PUSH_PER_USER      = 20
+	BATCH_SIZE_TELEGRAM_QUIET_HOURS_DIGEST = 100 // messages held during quiet hours, also limited by Telegram message length
 
 	BATCH_MESSAGES_LINE_DIVIDER = "\n---\n"
 
+	TELEGRAM_MESSAGE_MAX_LENGTH = 4096 // maximum length of the text of a Telegram message
+
 	INFORM_TELEGRAM_IF_BLOCK_OLDER_THAN = 3 * time.Minute
 
 	SILENT_PATTERN_MINIMUM_LENGTH = 10
+
+	INFORM_TELEGRAM_IF_MANAGED_ENDPOINT_LAG_BLOCKS = 10
+
+	INFORM_TELEGRAM_IF_VALIDATOR_NODE_LAG_BLOCKS = 5
+
+	DEFAULT_MIN_INBOUND_PEERS_VALIDATOR_NODE  = 0 // validators behind sentries do not have inbound peers
+	DEFAULT_MIN_OUTBOUND_PEERS_VALIDATOR_NODE = 2
+
+	DEFAULT_QUERY_SNAPSHOT_REUSE_BLOCKS = 1 // query snapshot of the previous block is reused, unless invalidated by streamed events
+
+	MIN_DURATION_BETWEEN_REQUESTED_HEALTH_CHECK = 5 * time.Second // roughly a block, to prevent health-check storm from event-driven checks
+	MIN_DURATION_BETWEEN_ON_DEMAND_HEALTH_CHECK = 1 * time.Minute // per user, health-check requested via /check command
+	ON_DEMAND_HEALTH_CHECK_TIMEOUT              = 5 * time.Minute // stop waiting for the findings of the health-check requested via /check command
+
+	STREAMING_RECONNECT_MIN_BACKOFF = 1 * time.Second
+	STREAMING_RECONNECT_MAX_BACKOFF = 5 * time.Minute
+	STREAMING_STALL_TIMEOUT         = 2 * time.Minute // reconnect if no new block received within this duration
+
+	STREAMING_NOT_SUPPORTED_RECHECK_INTERVAL = 1 * time.Hour // check again whether the node of the chain supports streaming
+
+	RPC_QUERY_TIMEOUT          = 15 * time.Second // timeout of each RPC query of health-check
+	HEALTH_CHECK_DRAIN_TIMEOUT = 1 * time.Minute  // maximum duration to wait for in-flight health-checks to finish on shutdown
+
+	HEARTBEAT_PING_TIMEOUT                = 10 * time.Second
+	MIN_DURATION_BETWEEN_HEARTBEATS       = 1 * time.Minute
+	WATCHDOG_STALE_HEALTH_CHECK_INTERVALS = 3             // alert root users if a chain has not been health-checked for this number of intervals
+	WATCHDOG_REMIND_STALE_INTERVAL        = 1 * time.Hour // remind root users while a chain is still not health-checked
+
+	REPORT_CHECK_INTERVAL    = 1 * time.Minute
+	REPORT_MAX_DELAY         = 1 * time.Hour      // scheduled reports missed by more than this duration, eg: daemon was down, are skipped
+	REPORT_HISTORY_RETENTION = 8 * 24 * time.Hour // health-check history kept to compose reports, must cover the weekly report
+)
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	REPORT_SCHEDULE_DAILY  = "daily"
+	REPORT_SCHEDULE_WEEKLY = "weekly"
+
+	DEFAULT_REPORT_AT      = "09:00"
+	DEFAULT_REPORT_WEEKDAY = "monday"
+)
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	HA_BACKEND_FILE = "file"
+
+	DEFAULT_HA_BACKEND        = HA_BACKEND_FILE
+	DEFAULT_HA_LEASE_FILE     = "leader.lease" // relative to home directory
+	DEFAULT_HA_LEASE_DURATION = 30 * time.Second
+	MINIMUM_HA_LEASE_DURATION = 5 * time.Second
+)
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	DEFAULT_TELEGRAM_WEBHOOK_LISTEN_ADDRESS = ":8080"
+
+	TELEGRAM_WEBHOOK_SECRET_TOKEN_HEADER = "X-Telegram-Bot-Api-Secret-Token"
+	TELEGRAM_WEBHOOK_UPDATES_BUFFER      = 100             // updates buffered per bot, Telegram re-delivers the updates rejected when the buffer is full
+	TELEGRAM_WEBHOOK_MAX_BODY_SIZE       = 1 << 20         // bytes
+	TELEGRAM_WEBHOOK_SHUTDOWN_TIMEOUT    = 5 * time.Second // maximum duration to wait for in-flight updates on shutdown
+
+	TELEGRAM_LONG_POLLING_TIMEOUT     = 10              // seconds, short so the instance losing leadership stops polling soon
+	TELEGRAM_LONG_POLLING_RETRY_DELAY = 3 * time.Second // delay before polling again after failure, eg: conflict with another instance
 )
@@ -10,11 +10,15 @@ const (
 
 	// Do not change bellow
 
-	DEFAULT_HOME           = "." + BINARY_NAME
-	CONFIG_FILE_NAME       = "config." + CONFIG_TYPE
-	USERS_FILE_NAME        = "users." + CONFIG_TYPE
-	CHAIN_FILE_NAME_PREFIX = "chain."
-	CONFIG_TYPE            = "yaml"
+	DEFAULT_HOME            = "." + BINARY_NAME
+	CONFIG_FILE_NAME        = "config." + CONFIG_TYPE
+	USERS_FILE_NAME         = "users." + CONFIG_TYPE
+	CHAIN_FILE_NAME_PREFIX  = "chain."
+	OUTBOX_FILE_NAME        = "outbox.json"
+	SUBSCRIPTIONS_FILE_NAME = "subscriptions.json"
+	AUDIT_LOG_FILE_NAME     = "audit.log"
+	TEMPLATES_DIR_NAME      = "templates"
+	CONFIG_TYPE             = "yaml"
 )
 
 //goland:noinspection GoSnakeCaseUsage
@@ -27,3 +31,39 @@ const (
 	FILE_PERMISSION     = 0o600
 	FILE_PERMISSION_STR = "600"
 )
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	GOV_VERSION_V1      = "v1"
+	GOV_VERSION_V1BETA1 = "v1beta1"
+)
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	ENDPOINT_TYPE_RPC     = "rpc"
+	ENDPOINT_TYPE_EVM_RPC = "evm-rpc"
+	ENDPOINT_TYPE_GRPC    = "grpc"
+	ENDPOINT_TYPE_REST    = "rest"
+)
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	EXPLORER_PLACEHOLDER_VALOPER = "{valoper}"
+	EXPLORER_PLACEHOLDER_TX      = "{tx}"
+)
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	PARSE_MODE_HTML       = "HTML"
+	PARSE_MODE_MARKDOWNV2 = "MarkdownV2"
+)
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	SEVERITY_INFO     = "info"
+	SEVERITY_WARNING  = "warning"
+	SEVERITY_CRITICAL = "critical"
+	SEVERITY_FATAL    = "fatal"
+
+	DEFAULT_MIN_SEVERITY = SEVERITY_INFO // all alerts are sent unless configured
+)
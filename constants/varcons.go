@@ -1,5 +1,7 @@
 package constants
 
+import "time"
+
 // Define soft constants (variable type) in this file
 
 //goland:noinspection GoSnakeCaseUsage
@@ -8,3 +10,9 @@ var (
 	COMMIT_HASH = ""
 	BUILD_DATE  = ""
 )
+
+//goland:noinspection GoSnakeCaseUsage
+var (
+	DEFAULT_TLS_CERT_EXPIRY_ALERT_LEAD_TIMES = []time.Duration{14 * 24 * time.Hour, 3 * 24 * time.Hour, 24 * time.Hour}
+	DEFAULT_GOV_VOTE_ESCALATION_LEAD_TIMES   = []time.Duration{48 * time.Hour, 12 * time.Hour, 2 * time.Hour}
+)
@@ -3,7 +3,6 @@ package chain_registry
 import (
 	"github.com/bcdevtools/validator-health-check/config"
 	"sync"
-	"time"
 )
 
 var mutex sync.RWMutex
@@ -45,29 +44,6 @@ func UpdateChainsConfigWL(chainConfigs config.ChainsConfig, usersConfig *config.
 	return nil
 }
 
-// GetFirstChainConfigForHealthCheckRL returns the first chain config that needs to be health-checked.
-//
-// NOTICE: this update the last health-check of the chain to recent time, so that the next health-check worker will not pick it up.
-func GetFirstChainConfigForHealthCheckRL(minDurationSinceLastHealthCheck time.Duration) RegisteredChainConfig {
-	mutex.RLock()
-	defer mutex.RUnlock()
-
-	allChainsConfig := getCopyAllChainConfigs().Sort()
-
-	for _, chainConfig := range allChainsConfig {
-		if paused, _ := IsChainPausedRL(chainConfig.GetChainName()); paused {
-			continue
-		}
-		lastHealthCheckUTC := chainConfig.GetLastHealthCheckUtcRL()
-		if time.Since(lastHealthCheckUTC) >= minDurationSinceLastHealthCheck {
-			chainConfig.SetLastHealthCheckUtcWL() // prevent double health-check
-			return chainConfig
-		}
-	}
-
-	return nil
-}
-
 // GetCopyAllChainConfigsRL returns a copy of all chain configs.
 func GetCopyAllChainConfigsRL() RegisteredChainsConfig {
 	mutex.RLock()
@@ -94,3 +70,12 @@ func HasChainRL(chainName string) bool {
 	_, found := globalChainNameToChainConfig[chainName]
 	return found
 }
+
+// GetChainConfigRL returns the registered config of the chain.
+func GetChainConfigRL(chainName string) (RegisteredChainConfig, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	chainConfig, found := globalChainNameToChainConfig[chainName]
+	return chainConfig, found
+}
@@ -0,0 +1,129 @@
+package chain_registry
+
+import (
+	"sync"
+	"time"
+)
+
+var healthCheckRequestMutex sync.RWMutex
+var requestedHealthCheck map[string]time.Time // chain name -> requested at
+var healthCheckRequestedSignal = make(chan struct{}, 1)
+var healthCheckCompletionWaiters map[string][]healthCheckCompletionWaiter // chain name -> waiters
+
+type healthCheckCompletionWaiter struct {
+	requestedAt time.Time
+	completed   chan struct{}
+}
+
+// RequestHealthCheckWL requests the chain to be health-checked as soon as possible, regardless of the health-check interval.
+// Used by event-driven checks, when relevant events are emitted by the chain.
+func RequestHealthCheckWL(chainName string) {
+	healthCheckRequestMutex.Lock()
+	defer healthCheckRequestMutex.Unlock()
+
+	if _, found := requestedHealthCheck[chainName]; !found {
+		requestedHealthCheck[chainName] = time.Now().UTC()
+	}
+
+	select {
+	case healthCheckRequestedSignal <- struct{}{}:
+	default:
+	}
+}
+
+// RequestHealthCheckAndWaitWL requests the chain to be health-checked as soon as possible, like RequestHealthCheckWL.
+// The returned channel is closed when a health-check of the chain, dispatched after the request, completes.
+// The returned function must be called when the caller stops waiting before completion (e.g. timed out),
+// to drop the waiter, since the chain might be paused, removed or never complete.
+// Used by on-demand checks, to reply the findings.
+func RequestHealthCheckAndWaitWL(chainName string) (completed <-chan struct{}, stopWaiting func()) {
+	waiter := healthCheckCompletionWaiter{
+		requestedAt: time.Now().UTC(),
+		completed:   make(chan struct{}),
+	}
+
+	healthCheckRequestMutex.Lock()
+	healthCheckCompletionWaiters[chainName] = append(healthCheckCompletionWaiters[chainName], waiter)
+	healthCheckRequestMutex.Unlock()
+
+	RequestHealthCheckWL(chainName)
+
+	return waiter.completed, func() {
+		removeHealthCheckCompletionWaiterWL(chainName, waiter.completed)
+	}
+}
+
+// removeHealthCheckCompletionWaiterWL drops the waiter of the chain, if not yet notified.
+func removeHealthCheckCompletionWaiterWL(chainName string, completed chan struct{}) {
+	healthCheckRequestMutex.Lock()
+	defer healthCheckRequestMutex.Unlock()
+
+	waiters := healthCheckCompletionWaiters[chainName]
+	for i, waiter := range waiters {
+		if waiter.completed != completed {
+			continue
+		}
+
+		remaining := append(waiters[:i:i], waiters[i+1:]...)
+		if len(remaining) > 0 {
+			healthCheckCompletionWaiters[chainName] = remaining
+		} else {
+			delete(healthCheckCompletionWaiters, chainName)
+		}
+		return
+	}
+}
+
+// NotifyHealthCheckCompletedWL notifies the waiters of the chain which requested before the completed health-check was dispatched.
+func NotifyHealthCheckCompletedWL(chainName string, dispatchedAt time.Time) {
+	healthCheckRequestMutex.Lock()
+	defer healthCheckRequestMutex.Unlock()
+
+	waiters, found := healthCheckCompletionWaiters[chainName]
+	if !found {
+		return
+	}
+
+	var remaining []healthCheckCompletionWaiter
+	for _, waiter := range waiters {
+		if waiter.requestedAt.After(dispatchedAt) {
+			remaining = append(remaining, waiter)
+			continue
+		}
+		close(waiter.completed)
+	}
+
+	if len(remaining) > 0 {
+		healthCheckCompletionWaiters[chainName] = remaining
+	} else {
+		delete(healthCheckCompletionWaiters, chainName)
+	}
+}
+
+// ConsumeRequestedHealthChecksWL returns the chains requested to be health-checked and clears the requests.
+func ConsumeRequestedHealthChecksWL() []string {
+	healthCheckRequestMutex.Lock()
+	defer healthCheckRequestMutex.Unlock()
+
+	if len(requestedHealthCheck) == 0 {
+		return nil
+	}
+
+	chainNames := make([]string, 0, len(requestedHealthCheck))
+	for chainName := range requestedHealthCheck {
+		chainNames = append(chainNames, chainName)
+	}
+	requestedHealthCheck = make(map[string]time.Time)
+
+	return chainNames
+}
+
+// HealthCheckRequestedSignal returns the channel signaled when any health-check is requested.
+func HealthCheckRequestedSignal() <-chan struct{} {
+	return healthCheckRequestedSignal
+}
+
+func init() {
+	requestedHealthCheck = make(map[string]time.Time)
+	healthCheckCompletionWaiters = make(map[string][]healthCheckCompletionWaiter)
+}
@@ -0,0 +1,41 @@
+package chain_registry
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRequestHealthCheckAndWaitWL_stopWaiting(t *testing.T) {
+	const chainName = "chain_stop_waiting"
+
+	completed1, stopWaiting1 := RequestHealthCheckAndWaitWL(chainName)
+	completed2, _ := RequestHealthCheckAndWaitWL(chainName)
+	_ = ConsumeRequestedHealthChecksWL()
+
+	stopWaiting1()
+	require.Len(t, healthCheckCompletionWaiters[chainName], 1, "waiter stopped waiting must be dropped")
+
+	stopWaiting1() // no-op when called again
+
+	NotifyHealthCheckCompletedWL(chainName, time.Now().UTC())
+	select {
+	case <-completed2:
+	default:
+		t.Fatal("remaining waiter should be notified")
+	}
+	select {
+	case <-completed1:
+		t.Fatal("dropped waiter should not be notified")
+	default:
+	}
+
+	_, found := healthCheckCompletionWaiters[chainName]
+	require.False(t, found)
+
+	_, stopWaiting3 := RequestHealthCheckAndWaitWL(chainName)
+	_ = ConsumeRequestedHealthChecksWL()
+	stopWaiting3()
+	_, found = healthCheckCompletionWaiters[chainName]
+	require.False(t, found, "chain without waiters must be removed")
+}
@@ -6,58 +6,98 @@ import (
 )
 
 var pauserMutex sync.RWMutex
-var pausedChains map[string]time.Time
-var pausedValidators map[string]time.Time
+var pausedChains map[string]PauseEntry
+var pausedValidators map[string]PauseEntry
 
-func PauseChainWL(chainName string, duration time.Duration) time.Time {
+// PauseEntry records who paused a chain or a validator, why and until when
+type PauseEntry struct {
+	Expiry    time.Time
+	PausedBy  string // identity of the user
+	Reason    string // optional
+	CreatedAt time.Time
+}
+
+func PauseChainWL(chainName string, duration time.Duration, pausedBy, reason string) PauseEntry {
 	pauserMutex.Lock()
 	defer pauserMutex.Unlock()
 
-	expiry := time.Now().UTC().Add(duration)
-	pausedChains[chainName] = expiry
-	return expiry
+	entry := newPauseEntry(duration, pausedBy, reason)
+	pausedChains[chainName] = entry
+	return entry
 }
 
-func UnpauseChainWL(chainName string) {
+// UnpauseChainWL removes the pause of the chain, returns the removed entry if the chain was paused
+func UnpauseChainWL(chainName string) (PauseEntry, bool) {
 	pauserMutex.Lock()
 	defer pauserMutex.Unlock()
 
+	entry, paused := pausedChains[chainName]
 	delete(pausedChains, chainName)
+	return entry, paused && entry.isActive()
 }
 
 func IsChainPausedRL(chainName string) (bool, time.Time) {
+	entry, paused := GetChainPauseEntryRL(chainName)
+	return paused, entry.Expiry
+}
+
+// GetChainPauseEntryRL returns the pause entry of the chain, false if not paused or expired
+func GetChainPauseEntryRL(chainName string) (PauseEntry, bool) {
 	pauserMutex.RLock()
 	defer pauserMutex.RUnlock()
 
-	expiry, paused := pausedChains[chainName]
-	return paused && time.Now().UTC().Before(expiry), expiry
+	entry, paused := pausedChains[chainName]
+	return entry, paused && entry.isActive()
 }
 
-func PauseValidatorWL(valoper string, duration time.Duration) time.Time {
+func PauseValidatorWL(valoper string, duration time.Duration, pausedBy, reason string) PauseEntry {
 	pauserMutex.Lock()
 	defer pauserMutex.Unlock()
 
-	expiry := time.Now().UTC().Add(duration)
-	pausedValidators[valoper] = expiry
-	return expiry
+	entry := newPauseEntry(duration, pausedBy, reason)
+	pausedValidators[valoper] = entry
+	return entry
 }
 
-func UnpauseValidatorWL(valoper string) {
+// UnpauseValidatorWL removes the pause of the validator, returns the removed entry if the validator was paused
+func UnpauseValidatorWL(valoper string) (PauseEntry, bool) {
 	pauserMutex.Lock()
 	defer pauserMutex.Unlock()
 
+	entry, paused := pausedValidators[valoper]
 	delete(pausedValidators, valoper)
+	return entry, paused && entry.isActive()
 }
 
 func IsValidatorPausedRL(valoper string) (bool, time.Time) {
+	entry, paused := GetValidatorPauseEntryRL(valoper)
+	return paused, entry.Expiry
+}
+
+// GetValidatorPauseEntryRL returns the pause entry of the validator, false if not paused or expired
+func GetValidatorPauseEntryRL(valoper string) (PauseEntry, bool) {
 	pauserMutex.RLock()
 	defer pauserMutex.RUnlock()
 
-	expiry, paused := pausedValidators[valoper]
-	return paused && time.Now().UTC().Before(expiry), expiry
+	entry, paused := pausedValidators[valoper]
+	return entry, paused && entry.isActive()
+}
+
+func newPauseEntry(duration time.Duration, pausedBy, reason string) PauseEntry {
+	nowUTC := time.Now().UTC()
+	return PauseEntry{
+		Expiry:    nowUTC.Add(duration),
+		PausedBy:  pausedBy,
+		Reason:    reason,
+		CreatedAt: nowUTC,
+	}
+}
+
+func (e PauseEntry) isActive() bool {
+	return time.Now().UTC().Before(e.Expiry)
 }
 
 func init() {
-	pausedChains = make(map[string]time.Time)
-	pausedValidators = make(map[string]time.Time)
+	pausedChains = make(map[string]PauseEntry)
+	pausedValidators = make(map[string]PauseEntry)
 }
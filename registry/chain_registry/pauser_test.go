@@ -0,0 +1,56 @@
+package chain_registry
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPauseValidatorWL(t *testing.T) {
+	const valoper = "valoper_pause"
+
+	_, paused := GetValidatorPauseEntryRL(valoper)
+	require.False(t, paused)
+
+	entry := PauseValidatorWL(valoper, time.Hour, "alice", "maintenance")
+	require.Equal(t, "alice", entry.PausedBy)
+	require.Equal(t, "maintenance", entry.Reason)
+	require.Equal(t, time.Hour, entry.Expiry.Sub(entry.CreatedAt))
+
+	got, paused := GetValidatorPauseEntryRL(valoper)
+	require.True(t, paused)
+	require.Equal(t, entry, got)
+
+	paused, expiry := IsValidatorPausedRL(valoper)
+	require.True(t, paused)
+	require.Equal(t, entry.Expiry, expiry)
+
+	removed, wasPaused := UnpauseValidatorWL(valoper)
+	require.True(t, wasPaused)
+	require.Equal(t, entry, removed)
+
+	_, wasPaused = UnpauseValidatorWL(valoper)
+	require.False(t, wasPaused, "already unpaused")
+
+	PauseValidatorWL(valoper, -time.Second, "alice", "")
+	_, paused = GetValidatorPauseEntryRL(valoper)
+	require.False(t, paused, "expired pause must not be effective")
+	_, wasPaused = UnpauseValidatorWL(valoper)
+	require.False(t, wasPaused, "expired pause is not reported as paused")
+}
+
+func TestPauseChainWL(t *testing.T) {
+	const chainName = "chain_pause"
+
+	entry := PauseChainWL(chainName, time.Hour, "root", "")
+	got, paused := GetChainPauseEntryRL(chainName)
+	require.True(t, paused)
+	require.Equal(t, entry, got)
+
+	removed, wasPaused := UnpauseChainWL(chainName)
+	require.True(t, wasPaused)
+	require.Equal(t, "root", removed.PausedBy)
+
+	paused, _ = IsChainPausedRL(chainName)
+	require.False(t, paused)
+}
@@ -2,8 +2,10 @@ package chain_registry
 
 import (
 	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
 	"github.com/bcdevtools/validator-health-check/utils"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -13,9 +15,17 @@ type RegisteredChainConfig interface {
 	GetChainId() string
 	IsPriority() bool
 	GetRPCs() []string
-	InformPriorityLatestHealthyRpcWL(string)
 	GetValidators() []ValidatorOfRegisteredChainConfig
 	GetHealthCheckRPCs() []string
+	GetHealthCheckEvmRPCs() []string
+	GetHealthCheckGrpcs() []string
+	GetHealthCheckRests() []string
+	GetSdkVersionOverride() string
+	GetGovVersionOverride() string
+	IsStreamingEnabled() bool
+	GetSnapshotReuseBlocks() int64
+	GetExplorerValidatorUrl(valoper string) string
+	GetExplorerTxUrl(txHash string) string
 	GetLastHealthCheckUtcRL() time.Time
 	SetLastHealthCheckUtcWL()
 }
@@ -28,17 +38,27 @@ type ValidatorOfRegisteredChainConfig struct {
 	ValidatorOperatorAddress string
 	WatchersIdentity         []string
 	OptionalHealthCheckRPC   string
+	MinInboundPeers          int
+	MinOutboundPeers         int
 }
 
 type registeredChainConfig struct {
 	sync.RWMutex
-	chainName          string
-	chainId            string
-	priority           bool
-	rpc                []string
-	validators         []ValidatorOfRegisteredChainConfig
-	healthCheckRPC     []string
-	lastHealthCheckUtc time.Time
+	chainName           string
+	chainId             string
+	priority            bool
+	rpc                 []string
+	validators          []ValidatorOfRegisteredChainConfig
+	healthCheckRPC      []string
+	healthCheckEvmRPC   []string
+	healthCheckGrpc     []string
+	healthCheckRest     []string
+	sdkVersion          string
+	govVersion          string
+	streaming           bool
+	snapshotReuseBlocks int64
+	explorer            config.ChainExplorerConfig
+	lastHealthCheckUtc  time.Time
 }
 
 func newRegisteredChainConfig(chainConfig config.ChainConfig) RegisteredChainConfig {
@@ -49,6 +69,13 @@ func newRegisteredChainConfig(chainConfig config.ChainConfig) RegisteredChainCon
 		}
 		return rpc
 	}
+	normalizeGrpcs := func(grpc ...string) []string {
+		normalizedGrpcs := make([]string, len(grpc))
+		for i, g := range grpc {
+			normalizedGrpcs[i] = utils.NormalizeGrpcEndpoint(g)
+		}
+		return normalizedGrpcs
+	}
 	normalizeRPCs := func(rpc ...string) []string {
 		normalizedRPCs := make([]string, len(rpc))
 		for i, r := range rpc {
@@ -61,7 +88,7 @@ func newRegisteredChainConfig(chainConfig config.ChainConfig) RegisteredChainCon
 		chainName: chainConfig.ChainName,
 		chainId:   chainConfig.ChainId,
 		priority:  chainConfig.Priority,
-		rpc:       normalizeRPCs(utils.Distinct[string](append(chainConfig.RPCs, chainConfig.HealthCheckRPC...)...)...),
+		rpc:       normalizeRPCs(utils.Distinct[string](append(chainConfig.RPCs, chainConfig.GetHealthCheckEndpointsByType(constants.ENDPOINT_TYPE_RPC)...)...)...),
 		validators: func() []ValidatorOfRegisteredChainConfig {
 			var validators []ValidatorOfRegisteredChainConfig
 			for _, chainValidatorConfig := range chainConfig.Validators {
@@ -69,11 +96,39 @@ func newRegisteredChainConfig(chainConfig config.ChainConfig) RegisteredChainCon
 					ValidatorOperatorAddress: chainValidatorConfig.ValidatorOperatorAddress,
 					WatchersIdentity:         chainValidatorConfig.Watchers,
 					OptionalHealthCheckRPC:   normalizeRPC(chainValidatorConfig.OptionalHealthCheckRPC),
+					MinInboundPeers: func() int {
+						if chainValidatorConfig.MinInboundPeers == 0 {
+							return constants.DEFAULT_MIN_INBOUND_PEERS_VALIDATOR_NODE
+						}
+						return chainValidatorConfig.MinInboundPeers
+					}(),
+					MinOutboundPeers: func() int {
+						if chainValidatorConfig.MinOutboundPeers == 0 {
+							return constants.DEFAULT_MIN_OUTBOUND_PEERS_VALIDATOR_NODE
+						}
+						return chainValidatorConfig.MinOutboundPeers
+					}(),
 				})
 			}
 			return validators
 		}(),
-		healthCheckRPC: normalizeRPCs(chainConfig.HealthCheckRPC...),
+		healthCheckRPC:    normalizeRPCs(chainConfig.GetHealthCheckEndpointsByType(constants.ENDPOINT_TYPE_RPC)...),
+		healthCheckEvmRPC: normalizeRPCs(chainConfig.GetHealthCheckEndpointsByType(constants.ENDPOINT_TYPE_EVM_RPC)...),
+		healthCheckGrpc:   utils.Distinct[string](normalizeGrpcs(chainConfig.GetHealthCheckEndpointsByType(constants.ENDPOINT_TYPE_GRPC)...)...),
+		healthCheckRest:   normalizeRPCs(chainConfig.GetHealthCheckEndpointsByType(constants.ENDPOINT_TYPE_REST)...),
+		sdkVersion:        chainConfig.SdkVersion,
+		govVersion:        chainConfig.GovVersion,
+		streaming:         chainConfig.Streaming,
+		snapshotReuseBlocks: func() int64 {
+			if chainConfig.SnapshotReuseBlocks == 0 {
+				return constants.DEFAULT_QUERY_SNAPSHOT_REUSE_BLOCKS
+			}
+			if chainConfig.SnapshotReuseBlocks < 0 {
+				return 0
+			}
+			return int64(chainConfig.SnapshotReuseBlocks)
+		}(),
+		explorer: chainConfig.Explorer,
 	}
 }
 
@@ -93,31 +148,57 @@ func (r *registeredChainConfig) GetRPCs() []string {
 	return r.rpc[:]
 }
 
-func (r *registeredChainConfig) InformPriorityLatestHealthyRpcWL(rpc string) {
-	if len(r.rpc) == 0 {
-		return
-	}
+func (r *registeredChainConfig) GetValidators() []ValidatorOfRegisteredChainConfig {
+	return r.validators[:]
+}
 
-	r.Lock()
-	defer r.Unlock()
+func (r *registeredChainConfig) GetHealthCheckRPCs() []string {
+	return r.healthCheckRPC
+}
 
-	reorderedRpc := []string{rpc}
-	for _, r := range r.rpc {
-		if r == rpc {
-			continue
-		}
-		reorderedRpc = append(reorderedRpc, r)
-	}
+func (r *registeredChainConfig) GetHealthCheckEvmRPCs() []string {
+	return r.healthCheckEvmRPC
+}
 
-	r.rpc = reorderedRpc
+func (r *registeredChainConfig) GetHealthCheckGrpcs() []string {
+	return r.healthCheckGrpc
 }
 
-func (r *registeredChainConfig) GetValidators() []ValidatorOfRegisteredChainConfig {
-	return r.validators[:]
+func (r *registeredChainConfig) GetHealthCheckRests() []string {
+	return r.healthCheckRest
 }
 
-func (r *registeredChainConfig) GetHealthCheckRPCs() []string {
-	return r.healthCheckRPC
+func (r *registeredChainConfig) GetSdkVersionOverride() string {
+	return r.sdkVersion
+}
+
+func (r *registeredChainConfig) GetGovVersionOverride() string {
+	return r.govVersion
+}
+
+func (r *registeredChainConfig) IsStreamingEnabled() bool {
+	return r.streaming
+}
+
+// GetSnapshotReuseBlocks returns the maximum number of blocks the query snapshot can be behind to be reused
+func (r *registeredChainConfig) GetSnapshotReuseBlocks() int64 {
+	return r.snapshotReuseBlocks
+}
+
+// GetExplorerValidatorUrl returns the explorer URL of the validator, empty if explorer is not configured
+func (r *registeredChainConfig) GetExplorerValidatorUrl(valoper string) string {
+	if r.explorer.Validator == "" || valoper == "" {
+		return ""
+	}
+	return strings.ReplaceAll(r.explorer.Validator, constants.EXPLORER_PLACEHOLDER_VALOPER, valoper)
+}
+
+// GetExplorerTxUrl returns the explorer URL of the transaction, empty if explorer is not configured
+func (r *registeredChainConfig) GetExplorerTxUrl(txHash string) string {
+	if r.explorer.Tx == "" || txHash == "" {
+		return ""
+	}
+	return strings.ReplaceAll(r.explorer.Tx, constants.EXPLORER_PLACEHOLDER_TX, txHash)
 }
 
 func (r *registeredChainConfig) GetLastHealthCheckUtcRL() time.Time {
@@ -0,0 +1,212 @@
+package rpc_client_registry
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// EvmRpcClient is a minimal client for EVM JSON-RPC endpoints exposed by Ethermint-based chains
+type EvmRpcClient interface {
+	GetEndpoint() string
+	BlockNumber(ctx context.Context) (int64, error)
+	Syncing(ctx context.Context) (EvmSyncStatus, error)
+	PeerCount(ctx context.Context) (int64, error)
+	WithLogger(logging.Logger) EvmRpcClient
+}
+
+// EvmSyncStatus is the result of `eth_syncing`
+type EvmSyncStatus struct {
+	Syncing       bool
+	CurrentBlock  int64
+	HighestBlock  int64
+	StartingBlock int64
+}
+
+var _ EvmRpcClient = &evmRpcClient{}
+
+type evmRpcClient struct {
+	logger logging.Logger
+
+	endpoint   string
+	httpClient *http.Client
+	requestId  uint64
+}
+
+type evmJsonRpcRequest struct {
+	JsonRpc string        `json:"jsonrpc"`
+	Id      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type evmJsonRpcResponse struct {
+	Id     uint64           `json:"id"`
+	Result json.RawMessage  `json:"result"`
+	Error  *evmJsonRpcError `json:"error"`
+}
+
+type evmJsonRpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newEvmRpcClient(endpoint string) EvmRpcClient {
+	return &evmRpcClient{
+		endpoint: endpoint,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (c *evmRpcClient) GetEndpoint() string {
+	return c.endpoint
+}
+
+func (c *evmRpcClient) WithLogger(logger logging.Logger) EvmRpcClient {
+	c.logger = logger
+	return c
+}
+
+// BlockNumber calls `eth_blockNumber`
+func (c *evmRpcClient) BlockNumber(ctx context.Context) (int64, error) {
+	var result string
+	if err := c.call(ctx, "eth_blockNumber", &result); err != nil {
+		return 0, err
+	}
+
+	return parseEvmHexQuantity(result)
+}
+
+// Syncing calls `eth_syncing`, the result is either `false` or an object describing the sync progress
+func (c *evmRpcClient) Syncing(ctx context.Context) (EvmSyncStatus, error) {
+	var result json.RawMessage
+	if err := c.call(ctx, "eth_syncing", &result); err != nil {
+		return EvmSyncStatus{}, err
+	}
+
+	if strings.TrimSpace(string(result)) == "false" {
+		return EvmSyncStatus{}, nil
+	}
+
+	var progress struct {
+		CurrentBlock  string `json:"currentBlock"`
+		HighestBlock  string `json:"highestBlock"`
+		StartingBlock string `json:"startingBlock"`
+	}
+	if err := json.Unmarshal(result, &progress); err != nil {
+		return EvmSyncStatus{}, errors.Wrap(err, "failed to unmarshal sync progress")
+	}
+
+	status := EvmSyncStatus{
+		Syncing: true,
+	}
+	var err error
+	if status.CurrentBlock, err = parseEvmHexQuantity(progress.CurrentBlock); err != nil {
+		return EvmSyncStatus{}, errors.Wrap(err, "bad current block")
+	}
+	if status.HighestBlock, err = parseEvmHexQuantity(progress.HighestBlock); err != nil {
+		return EvmSyncStatus{}, errors.Wrap(err, "bad highest block")
+	}
+	if progress.StartingBlock != "" {
+		if status.StartingBlock, err = parseEvmHexQuantity(progress.StartingBlock); err != nil {
+			return EvmSyncStatus{}, errors.Wrap(err, "bad starting block")
+		}
+	}
+
+	return status, nil
+}
+
+// PeerCount calls `net_peerCount`
+func (c *evmRpcClient) PeerCount(ctx context.Context) (int64, error) {
+	var result json.RawMessage
+	if err := c.call(ctx, "net_peerCount", &result); err != nil {
+		return 0, err
+	}
+
+	// some implementations return a number instead of a hex quantity
+	var hexQuantity string
+	if err := json.Unmarshal(result, &hexQuantity); err == nil {
+		return parseEvmHexQuantity(hexQuantity)
+	}
+
+	var number int64
+	if err := json.Unmarshal(result, &number); err != nil {
+		return 0, fmt.Errorf("unexpected peer count result: %s", string(result))
+	}
+	return number, nil
+}
+
+func (c *evmRpcClient) call(ctx context.Context, method string, result interface{}) error {
+	bzReq, err := json.Marshal(evmJsonRpcRequest{
+		JsonRpc: "2.0",
+		Id:      atomic.AddUint64(&c.requestId, 1),
+		Method:  method,
+		Params:  []interface{}{},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(bzReq))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpRes, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return errors.Wrapf(err, "failed to call %s", method)
+	}
+	defer func() {
+		_ = httpRes.Body.Close()
+	}()
+
+	if httpRes.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to call %s, status code %d", method, httpRes.StatusCode)
+	}
+
+	bzRes, err := io.ReadAll(httpRes.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read response of %s", method)
+	}
+
+	var res evmJsonRpcResponse
+	if err := json.Unmarshal(bzRes, &res); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal response of %s", method)
+	}
+
+	if res.Error != nil {
+		return fmt.Errorf("%s returns error code %d: %s", method, res.Error.Code, res.Error.Message)
+	}
+
+	if err := json.Unmarshal(res.Result, result); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal result of %s", method)
+	}
+
+	return nil
+}
+
+func parseEvmHexQuantity(hexQuantity string) (int64, error) {
+	if !strings.HasPrefix(hexQuantity, "0x") {
+		return 0, fmt.Errorf("invalid hex quantity: %s", hexQuantity)
+	}
+
+	number, err := strconv.ParseInt(hexQuantity[2:], 16, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid hex quantity: %s", hexQuantity)
+	}
+
+	return number, nil
+}
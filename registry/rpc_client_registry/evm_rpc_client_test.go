@@ -0,0 +1,98 @@
+package rpc_client_registry
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newFakeEvmJsonRpcServer(t *testing.T, results map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req evmJsonRpcRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		result, found := results[req.Method]
+		if !found {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+}
+
+func TestEvmRpcClient(t *testing.T) {
+	t.Run("synced node", func(t *testing.T) {
+		server := newFakeEvmJsonRpcServer(t, map[string]string{
+			"eth_blockNumber": `"0x1b4"`,
+			"eth_syncing":     `false`,
+			"net_peerCount":   `"0x19"`,
+		})
+		defer server.Close()
+
+		client := newEvmRpcClient(server.URL)
+
+		blockNumber, err := client.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(436), blockNumber)
+
+		syncStatus, err := client.Syncing(context.Background())
+		require.NoError(t, err)
+		require.False(t, syncStatus.Syncing)
+
+		peerCount, err := client.PeerCount(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(25), peerCount)
+	})
+
+	t.Run("syncing node", func(t *testing.T) {
+		server := newFakeEvmJsonRpcServer(t, map[string]string{
+			"eth_syncing":   `{"startingBlock":"0x1","currentBlock":"0x10","highestBlock":"0x20"}`,
+			"net_peerCount": `3`,
+		})
+		defer server.Close()
+
+		client := newEvmRpcClient(server.URL)
+
+		syncStatus, err := client.Syncing(context.Background())
+		require.NoError(t, err)
+		require.True(t, syncStatus.Syncing)
+		require.Equal(t, int64(1), syncStatus.StartingBlock)
+		require.Equal(t, int64(16), syncStatus.CurrentBlock)
+		require.Equal(t, int64(32), syncStatus.HighestBlock)
+
+		peerCount, err := client.PeerCount(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(3), peerCount)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		server := newFakeEvmJsonRpcServer(t, map[string]string{})
+		defer server.Close()
+
+		client := newEvmRpcClient(server.URL)
+
+		_, err := client.BlockNumber(context.Background())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "method not found")
+	})
+}
+
+func TestParseEvmHexQuantity(t *testing.T) {
+	number, err := parseEvmHexQuantity("0x0")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), number)
+
+	number, err = parseEvmHexQuantity("0xff")
+	require.NoError(t, err)
+	require.Equal(t, int64(255), number)
+
+	_, err = parseEvmHexQuantity("255")
+	require.Error(t, err)
+
+	_, err = parseEvmHexQuantity("0xzz")
+	require.Error(t, err)
+}
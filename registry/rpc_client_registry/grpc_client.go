@@ -0,0 +1,91 @@
+package rpc_client_registry
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/bcdevtools/validator-health-check/codec"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"strings"
+	"time"
+)
+
+// GrpcClient is a minimal client for Cosmos-SDK gRPC endpoints, used for health-check purpose
+type GrpcClient interface {
+	GetEndpoint() string
+	GetLatestBlock(ctx context.Context) (height int64, blockTime time.Time, err error)
+	WithLogger(logging.Logger) GrpcClient
+}
+
+var _ GrpcClient = &grpcClient{}
+
+type grpcClient struct {
+	logger logging.Logger
+
+	endpoint string
+	conn     *grpc.ClientConn
+}
+
+// newGrpcClient creates a new gRPC client.
+// Endpoint with scheme `https://` or `grpcs://` will use TLS, otherwise plain-text connection is used.
+func newGrpcClient(endpoint string) (GrpcClient, error) {
+	target := endpoint
+	transportCredentials := insecure.NewCredentials()
+
+	if strings.HasPrefix(endpoint, "https://") || strings.HasPrefix(endpoint, "grpcs://") {
+		target = endpoint[strings.Index(endpoint, "://")+3:]
+		transportCredentials = credentials.NewTLS(&tls.Config{
+			MinVersion: tls.VersionTLS12,
+		})
+	} else if strings.Contains(endpoint, "://") {
+		target = endpoint[strings.Index(endpoint, "://")+3:]
+	}
+	target = strings.TrimSuffix(target, "/")
+
+	conn, err := grpc.Dial(
+		target,
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(codec.CryptoCodec.GRPCCodec())),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create gRPC connection for %s", endpoint)
+	}
+
+	return &grpcClient{
+		endpoint: endpoint,
+		conn:     conn,
+	}, nil
+}
+
+func (c *grpcClient) GetEndpoint() string {
+	return c.endpoint
+}
+
+// GetLatestBlock calls `cosmos.base.tendermint.v1beta1.Service/GetLatestBlock`
+func (c *grpcClient) GetLatestBlock(ctx context.Context) (height int64, blockTime time.Time, err error) {
+	res, err := tmservice.NewServiceClient(c.conn).GetLatestBlock(ctx, &tmservice.GetLatestBlockRequest{})
+	if err != nil {
+		return 0, time.Time{}, errors.Wrap(err, "failed to get latest block")
+	}
+
+	if res.SdkBlock != nil {
+		return res.SdkBlock.Header.Height, res.SdkBlock.Header.Time, nil
+	}
+
+	if res.Block != nil {
+		return res.Block.Header.Height, res.Block.Header.Time, nil
+	}
+
+	return 0, time.Time{}, fmt.Errorf("block is missing from response")
+}
+
+func (c *grpcClient) WithLogger(logger logging.Logger) GrpcClient {
+	c.logger = logger
+	return c
+}
@@ -0,0 +1,111 @@
+package rpc_client_registry
+
+import (
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/bcdevtools/validator-health-check/utils"
+	"strings"
+	"sync"
+)
+
+var managedEndpointMutex sync.RWMutex
+var globalEvmRpcToClient map[string]EvmRpcClient
+var globalGrpcToClient map[string]GrpcClient
+var globalRestToClient map[string]RestClient
+
+func GetEvmRpcClientByEndpointWL(endpoint string, logger logging.Logger) EvmRpcClient {
+	if endpoint == "" {
+		panic("empty evm rpc endpoint")
+	}
+
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	managedEndpointMutex.RLock()
+	client, found := globalEvmRpcToClient[endpoint]
+	managedEndpointMutex.RUnlock()
+	if found {
+		return client
+	}
+
+	managedEndpointMutex.Lock()
+	defer managedEndpointMutex.Unlock()
+
+	// double check
+	client, found = globalEvmRpcToClient[endpoint]
+	if found {
+		return client
+	}
+
+	client = newEvmRpcClient(endpoint).WithLogger(logger)
+
+	globalEvmRpcToClient[endpoint] = client
+	return client
+}
+
+func GetGrpcClientByEndpointWL(endpoint string, logger logging.Logger) (GrpcClient, error) {
+	if endpoint == "" {
+		panic("empty grpc endpoint")
+	}
+
+	endpoint = utils.NormalizeGrpcEndpoint(endpoint)
+
+	managedEndpointMutex.RLock()
+	client, found := globalGrpcToClient[endpoint]
+	managedEndpointMutex.RUnlock()
+	if found {
+		return client, nil
+	}
+
+	managedEndpointMutex.Lock()
+	defer managedEndpointMutex.Unlock()
+
+	// double check
+	client, found = globalGrpcToClient[endpoint]
+	if found {
+		return client, nil
+	}
+
+	client, err := newGrpcClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	client = client.WithLogger(logger)
+
+	globalGrpcToClient[endpoint] = client
+	return client, nil
+}
+
+func GetRestClientByEndpointWL(endpoint string, logger logging.Logger) RestClient {
+	if endpoint == "" {
+		panic("empty rest endpoint")
+	}
+
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	managedEndpointMutex.RLock()
+	client, found := globalRestToClient[endpoint]
+	managedEndpointMutex.RUnlock()
+	if found {
+		return client
+	}
+
+	managedEndpointMutex.Lock()
+	defer managedEndpointMutex.Unlock()
+
+	// double check
+	client, found = globalRestToClient[endpoint]
+	if found {
+		return client
+	}
+
+	client = newRestClient(endpoint).WithLogger(logger)
+
+	globalRestToClient[endpoint] = client
+	return client
+}
+
+func init() {
+	globalEvmRpcToClient = make(map[string]EvmRpcClient)
+	globalGrpcToClient = make(map[string]GrpcClient)
+	globalRestToClient = make(map[string]RestClient)
+}
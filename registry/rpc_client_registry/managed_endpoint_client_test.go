@@ -0,0 +1,89 @@
+package rpc_client_registry
+
+import (
+	"context"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/stretchr/testify/require"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRestClient_GetLatestBlock(t *testing.T) {
+	t.Run("legacy block", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/cosmos/base/tendermint/v1beta1/blocks/latest", r.URL.Path)
+			_, _ = w.Write([]byte(`{"block_id":{},"block":{"header":{"height":"123","time":"2023-01-02T03:04:05.123Z"}}}`))
+		}))
+		defer server.Close()
+
+		height, blockTime, err := newRestClient(server.URL + "/").GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(123), height)
+		require.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 123_000_000, time.UTC), blockTime.UTC())
+	})
+
+	t.Run("sdk block takes precedence", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"block":{"header":{"height":"1"}},"sdk_block":{"header":{"height":"456","time":"2023-01-02T03:04:05Z"}}}`))
+		}))
+		defer server.Close()
+
+		height, _, err := newRestClient(server.URL).GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(456), height)
+	})
+
+	t.Run("bad status code", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		_, _, err := newRestClient(server.URL).GetLatestBlock(context.Background())
+		require.Error(t, err)
+	})
+}
+
+type fakeTendermintService struct {
+	tmservice.UnimplementedServiceServer
+	height int64
+}
+
+func (s *fakeTendermintService) GetLatestBlock(_ context.Context, _ *tmservice.GetLatestBlockRequest) (*tmservice.GetLatestBlockResponse, error) {
+	return &tmservice.GetLatestBlockResponse{
+		Block: &tmproto.Block{
+			Header: tmproto.Header{
+				Height: s.height,
+				Time:   time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		},
+	}, nil
+}
+
+func TestGrpcClient_GetLatestBlock(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	tmservice.RegisterServiceServer(server, &fakeTendermintService{height: 789})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	client, err := newGrpcClient(listener.Addr().String())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	height, blockTime, err := client.GetLatestBlock(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(789), height)
+	require.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), blockTime.UTC())
+}
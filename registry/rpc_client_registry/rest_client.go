@@ -0,0 +1,103 @@
+package rpc_client_registry
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RestClient is a minimal client for Cosmos-SDK REST (LCD) endpoints, used for health-check purpose
+type RestClient interface {
+	GetEndpoint() string
+	GetLatestBlock(ctx context.Context) (height int64, blockTime time.Time, err error)
+	WithLogger(logging.Logger) RestClient
+}
+
+var _ RestClient = &restClient{}
+
+type restClient struct {
+	logger logging.Logger
+
+	endpoint   string
+	httpClient *http.Client
+}
+
+func newRestClient(endpoint string) RestClient {
+	return &restClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (c *restClient) GetEndpoint() string {
+	return c.endpoint
+}
+
+// GetLatestBlock calls `/cosmos/base/tendermint/v1beta1/blocks/latest`
+func (c *restClient) GetLatestBlock(ctx context.Context) (height int64, blockTime time.Time, err error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/cosmos/base/tendermint/v1beta1/blocks/latest", nil)
+	if err != nil {
+		return 0, time.Time{}, errors.Wrap(err, "failed to create request")
+	}
+
+	httpRes, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return 0, time.Time{}, errors.Wrap(err, "failed to get latest block")
+	}
+	defer func() {
+		_ = httpRes.Body.Close()
+	}()
+
+	if httpRes.StatusCode != http.StatusOK {
+		return 0, time.Time{}, fmt.Errorf("failed to get latest block, status code %d", httpRes.StatusCode)
+	}
+
+	bz, err := io.ReadAll(httpRes.Body)
+	if err != nil {
+		return 0, time.Time{}, errors.Wrap(err, "failed to read response")
+	}
+
+	type restBlock struct {
+		Header struct {
+			Height string    `json:"height"`
+			Time   time.Time `json:"time"`
+		} `json:"header"`
+	}
+	var res struct {
+		Block    *restBlock `json:"block"`
+		SdkBlock *restBlock `json:"sdk_block"` // since Cosmos-SDK v0.47
+	}
+	if err := json.Unmarshal(bz, &res); err != nil {
+		return 0, time.Time{}, errors.Wrap(err, "failed to unmarshal response")
+	}
+
+	block := res.SdkBlock
+	if block == nil {
+		block = res.Block
+	}
+	if block == nil {
+		return 0, time.Time{}, fmt.Errorf("block is missing from response")
+	}
+
+	height, err = strconv.ParseInt(block.Header.Height, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, errors.Wrapf(err, "invalid block height %s", block.Header.Height)
+	}
+
+	return height, block.Header.Time, nil
+}
+
+func (c *restClient) WithLogger(logger logging.Logger) RestClient {
+	c.logger = logger
+	return c
+}
@@ -4,6 +4,7 @@ package rpc_client_registry
 import (
 	"fmt"
 	"github.com/EscanBE/go-lib/logging"
+	"github.com/bcdevtools/validator-health-check/utils"
 	"github.com/pkg/errors"
 	httpclient "github.com/tendermint/tendermint/rpc/client/http"
 	jsonrpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
@@ -66,3 +67,17 @@ func getTendermintClient(rpc string) (*http.Client, *httpclient.HTTP, error) {
 
 	return httpClient26657, tendermintRpcHttpClient, nil
 }
+
+// NewEventStreamingClient creates and starts a dedicated Tendermint RPC client for subscribing to events via websocket.
+// Caller is responsible for stopping the client.
+func NewEventStreamingClient(endpoint string) (*httpclient.HTTP, error) {
+	endpoint = utils.ReplaceAnySchemeWithHttp(endpoint)
+	endpoint = utils.NormalizeRpcEndpoint(endpoint)
+
+	_, websocketClient, err := getTendermintClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return websocketClient, nil
+}
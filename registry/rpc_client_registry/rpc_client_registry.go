@@ -1,6 +1,7 @@
 package rpc_client_registry
 
 import (
+	"fmt"
 	"github.com/EscanBE/go-lib/logging"
 	"github.com/bcdevtools/validator-health-check/utils"
 	"sync"
@@ -8,6 +9,7 @@ import (
 
 var mutex sync.RWMutex
 var globalRpcToClient map[string]RpcClient
+var globalChainToRpcPool map[string]RpcPool
 
 func GetRpcClientByEndpointWL(endpoint string, logger logging.Logger) (RpcClient, error) {
 	if endpoint == "" {
@@ -58,6 +60,49 @@ func getRpcClientByEndpointRL(endpoint string, acquireRLock bool) (client RpcCli
 	return
 }
 
+// GetRpcPoolByChainWL returns the RPC pool of the chain, endpoints of the pool are updated to the given endpoints.
+func GetRpcPoolByChainWL(chainName string, endpoints []string, logger logging.Logger) RpcPool {
+	if chainName == "" {
+		panic("empty chain name")
+	}
+
+	mutex.Lock()
+	pool, found := globalChainToRpcPool[chainName]
+	if !found {
+		pool = newRpcPool(chainName, endpoints, func(endpoint string) (RpcQuerier, error) {
+			rpcClient, err := GetRpcClientByEndpointWL(endpoint, logger)
+			if err != nil {
+				return nil, err
+			}
+
+			websocketClient := rpcClient.GetWebsocketClient()
+			if websocketClient == nil {
+				return nil, fmt.Errorf("websocket client is nil")
+			}
+
+			return websocketClient, nil
+		}, logger)
+		globalChainToRpcPool[chainName] = pool
+	}
+	mutex.Unlock()
+
+	if found {
+		pool.UpdateEndpoints(endpoints)
+	}
+
+	return pool
+}
+
+// GetRpcPoolByChainRL returns the RPC pool of the chain if it was initialized by health-check.
+func GetRpcPoolByChainRL(chainName string) (pool RpcPool, found bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	pool, found = globalChainToRpcPool[chainName]
+	return
+}
+
 func init() {
 	globalRpcToClient = make(map[string]RpcClient)
+	globalChainToRpcPool = make(map[string]RpcPool)
 }
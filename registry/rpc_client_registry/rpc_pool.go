@@ -0,0 +1,406 @@
+package rpc_client_registry
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"fmt"
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/pkg/errors"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RpcQuerier performs queries against Tendermint RPC.
+// Implemented by the Tendermint RPC client of a single endpoint and by RpcPool, which fails over between endpoints.
+type RpcQuerier interface {
+	ABCIQuery(ctx context.Context, path string, data tmbytes.HexBytes) (*coretypes.ResultABCIQuery, error)
+	Status(ctx context.Context) (*coretypes.ResultStatus, error)
+}
+
+// RpcPool manages the public RPC endpoints of a chain.
+// It tracks latency, error rate and height per endpoint over time, opens circuit breaker on failing endpoints,
+// and transparently retries every query on the next-best endpoint.
+type RpcPool interface {
+	RpcQuerier
+
+	// Refresh probes status of all endpoints in parallel and returns stats of the best endpoint after refreshing.
+	Refresh(ctx context.Context, chainId string) (RpcEndpointStats, error)
+	// UpdateEndpoints replaces the endpoints of the pool, stats of the remaining endpoints are kept.
+	UpdateEndpoints(endpoints []string)
+	// GetRankedEndpointsStats returns stats of all endpoints, ordered from the best to the worst.
+	GetRankedEndpointsStats() []RpcEndpointStats
+}
+
+const (
+	rpcPoolLatencyEwmaAlpha       = 0.3
+	rpcPoolErrorRateEwmaAlpha     = 0.2
+	rpcPoolCircuitBreakerFailures = 3                // consecutive failures to open the circuit breaker
+	rpcPoolCircuitBreakerCooldown = 30 * time.Second // initial cool-down, doubled each time the circuit re-opens
+	rpcPoolCircuitBreakerMaxCool  = 10 * time.Minute
+	rpcPoolAcceptableBlocksBehind = 2 // endpoints lagging not more than this number of blocks are considered as up-to-date
+)
+
+// RpcEndpointStats is the snapshot of the statistic of an endpoint in the pool
+type RpcEndpointStats struct {
+	Endpoint             string
+	LatencyEwma          time.Duration
+	ErrorRate            float64 // exponentially weighted, 0 to 1
+	TotalRequests        uint64
+	TotalFailures        uint64
+	ConsecutiveFailures  int
+	LatestBlockHeight    int64
+	LatestBlockTime      time.Time
+	LastError            string
+	LastErrorTime        time.Time
+	LastSuccessTime      time.Time
+	NetworkMismatch      bool
+	CircuitOpen          bool
+	CircuitOpenUntil     time.Time
+	circuitCooldown      time.Duration
+	circuitHalfOpenTrial bool
+}
+
+// IsUsable returns true if the endpoint can be used for querying at the moment
+func (s RpcEndpointStats) IsUsable() bool {
+	return !s.NetworkMismatch && !s.CircuitOpen
+}
+
+// IsPreferable returns true if the endpoint is usable and its latest request succeeded with block height reported
+func (s RpcEndpointStats) IsPreferable() bool {
+	return s.IsUsable() && s.ConsecutiveFailures == 0 && s.LatestBlockHeight > 0
+}
+
+var _ RpcPool = &rpcPool{}
+
+type rpcPool struct {
+	sync.RWMutex
+	chainName string
+	endpoints []string
+	stats     map[string]*RpcEndpointStats
+	newClient func(endpoint string) (RpcQuerier, error)
+	logger    logging.Logger
+}
+
+func newRpcPool(chainName string, endpoints []string, newClient func(endpoint string) (RpcQuerier, error), logger logging.Logger) *rpcPool {
+	pool := &rpcPool{
+		chainName: chainName,
+		stats:     make(map[string]*RpcEndpointStats),
+		newClient: newClient,
+		logger:    logger,
+	}
+	pool.UpdateEndpoints(endpoints)
+	return pool
+}
+
+func (p *rpcPool) UpdateEndpoints(endpoints []string) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.endpoints = append([]string{}, endpoints...)
+
+	stats := make(map[string]*RpcEndpointStats)
+	for _, endpoint := range p.endpoints {
+		existing, found := p.stats[endpoint]
+		if found {
+			stats[endpoint] = existing
+		} else {
+			stats[endpoint] = &RpcEndpointStats{
+				Endpoint: endpoint,
+			}
+		}
+	}
+	p.stats = stats
+}
+
+func (p *rpcPool) Refresh(ctx context.Context, chainId string) (RpcEndpointStats, error) {
+	var endpoints []string
+	for _, stats := range p.GetRankedEndpointsStats() {
+		if stats.CircuitOpen {
+			// do not probe endpoints having circuit breaker opened, until cool-down is over
+			continue
+		}
+		endpoints = append(endpoints, stats.Endpoint)
+	}
+
+	if len(endpoints) == 0 {
+		p.RLock()
+		noEndpoint := len(p.endpoints) == 0
+		p.RUnlock()
+		if noEndpoint {
+			return RpcEndpointStats{}, fmt.Errorf("no RPC endpoint of chain %s", p.chainName)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func(endpoint string) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					p.recordFailure(endpoint, fmt.Errorf("panic: %v", r))
+				}
+			}()
+
+			client, err := p.newClient(endpoint)
+			if err != nil {
+				p.recordFailure(endpoint, errors.Wrap(err, "failed to get RPC client"))
+				return
+			}
+
+			startTime := time.Now()
+			resultStatus, err := client.Status(ctx)
+			if err != nil {
+				p.recordFailure(endpoint, errors.Wrap(err, "failed to get status"))
+				return
+			}
+
+			p.recordSuccess(endpoint, time.Since(startTime))
+			p.recordStatus(endpoint, resultStatus, chainId)
+		}(endpoint)
+	}
+	wg.Wait()
+
+	ranked := p.GetRankedEndpointsStats()
+	best := ranked[0]
+	if !best.IsPreferable() {
+		if best.NetworkMismatch {
+			return best, fmt.Errorf("no usable RPC, network mismatch of %s, expected %s", best.Endpoint, chainId)
+		}
+		if best.LastError != "" {
+			return best, fmt.Errorf("no usable RPC, last error of %s: %s", best.Endpoint, best.LastError)
+		}
+		return best, fmt.Errorf("no usable RPC")
+	}
+
+	return best, nil
+}
+
+func (p *rpcPool) GetRankedEndpointsStats() []RpcEndpointStats {
+	p.Lock()
+	defer p.Unlock()
+
+	var maxHeight int64
+	ranked := make([]RpcEndpointStats, 0, len(p.endpoints))
+	for _, endpoint := range p.endpoints {
+		stats := p.stats[endpoint]
+		p.updateCircuitState(stats)
+		ranked = append(ranked, *stats)
+		if stats.IsUsable() && stats.ConsecutiveFailures == 0 && stats.LatestBlockHeight > maxHeight {
+			maxHeight = stats.LatestBlockHeight
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return lessRpcEndpointStats(ranked[i], ranked[j], maxHeight)
+	})
+
+	return ranked
+}
+
+// lessRpcEndpointStats returns true if the left endpoint is preferred over the right one.
+// Usable endpoints come first, then endpoints not failing recently, then up-to-date endpoints,
+// then the lowest score (latency weighted by error rate).
+func lessRpcEndpointStats(left, right RpcEndpointStats, maxHeight int64) bool {
+	if left.IsUsable() != right.IsUsable() {
+		return left.IsUsable()
+	}
+
+	// the latest request to an endpoint failed, its height might be stale
+	if (left.ConsecutiveFailures == 0) != (right.ConsecutiveFailures == 0) {
+		return left.ConsecutiveFailures == 0
+	}
+
+	leftUpToDate := maxHeight-left.LatestBlockHeight <= rpcPoolAcceptableBlocksBehind
+	rightUpToDate := maxHeight-right.LatestBlockHeight <= rpcPoolAcceptableBlocksBehind
+	if leftUpToDate != rightUpToDate {
+		return leftUpToDate
+	}
+
+	if !leftUpToDate && left.LatestBlockHeight != right.LatestBlockHeight {
+		return left.LatestBlockHeight > right.LatestBlockHeight
+	}
+
+	return left.score() < right.score()
+}
+
+// score of an endpoint, lower is better
+func (s RpcEndpointStats) score() float64 {
+	latency := float64(s.LatencyEwma)
+	if s.TotalRequests == 0 {
+		// not measured yet, put it behind the measured healthy endpoints
+		latency = float64(time.Second)
+	}
+	return latency * (1 + 4*s.ErrorRate)
+}
+
+func (p *rpcPool) ABCIQuery(ctx context.Context, path string, data tmbytes.HexBytes) (*coretypes.ResultABCIQuery, error) {
+	return execWithFailover(p, func(client RpcQuerier) (*coretypes.ResultABCIQuery, error) {
+		return client.ABCIQuery(ctx, path, data)
+	})
+}
+
+func (p *rpcPool) Status(ctx context.Context) (*coretypes.ResultStatus, error) {
+	return execWithFailover(p, func(client RpcQuerier) (*coretypes.ResultStatus, error) {
+		return client.Status(ctx)
+	})
+}
+
+// execWithFailover executes the request on the best endpoint, and on the next-best endpoints if failed.
+func execWithFailover[T any](p *rpcPool, f func(client RpcQuerier) (T, error)) (res T, err error) {
+	var tried int
+	for _, stats := range p.GetRankedEndpointsStats() {
+		if !stats.IsUsable() {
+			continue
+		}
+
+		endpoint := stats.Endpoint
+		if !p.acquireTrial(endpoint) {
+			continue
+		}
+
+		tried++
+
+		var client RpcQuerier
+		client, err = p.newClient(endpoint)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to get RPC client %s", endpoint)
+			p.recordFailure(endpoint, err)
+			continue
+		}
+
+		startTime := time.Now()
+		res, err = f(client)
+		if err != nil {
+			p.recordFailure(endpoint, err)
+			if p.logger != nil {
+				p.logger.Debug("RPC request failed, trying next-best endpoint", "chain", p.chainName, "endpoint", endpoint, "error", err.Error())
+			}
+			if ctxErr := ctxErrOrNil(err); ctxErr != nil {
+				return
+			}
+			continue
+		}
+
+		p.recordSuccess(endpoint, time.Since(startTime))
+		return
+	}
+
+	if tried == 0 {
+		err = fmt.Errorf("no usable RPC endpoint of chain %s, all circuit breakers are open", p.chainName)
+	} else {
+		err = errors.Wrapf(err, "all %d usable RPC endpoints of chain %s failed, last error", tried, p.chainName)
+	}
+	return
+}
+
+func ctxErrOrNil(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return nil
+}
+
+// acquireTrial returns false if the endpoint is in half-open state and another request is already trialing it
+func (p *rpcPool) acquireTrial(endpoint string) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	stats, found := p.stats[endpoint]
+	if !found {
+		return false
+	}
+
+	if stats.ConsecutiveFailures < rpcPoolCircuitBreakerFailures {
+		return true
+	}
+
+	// half-open, allow a single trial request
+	if stats.circuitHalfOpenTrial {
+		return false
+	}
+	stats.circuitHalfOpenTrial = true
+	return true
+}
+
+// updateCircuitState moves the circuit breaker from open to half-open when the cool-down is over
+func (p *rpcPool) updateCircuitState(stats *RpcEndpointStats) {
+	if stats.CircuitOpen && !time.Now().Before(stats.CircuitOpenUntil) {
+		stats.CircuitOpen = false
+	}
+}
+
+func (p *rpcPool) recordSuccess(endpoint string, latency time.Duration) {
+	p.Lock()
+	defer p.Unlock()
+
+	stats, found := p.stats[endpoint]
+	if !found {
+		return
+	}
+
+	stats.TotalRequests++
+	if stats.LatencyEwma == 0 {
+		stats.LatencyEwma = latency
+	} else {
+		stats.LatencyEwma = time.Duration(rpcPoolLatencyEwmaAlpha*float64(latency) + (1-rpcPoolLatencyEwmaAlpha)*float64(stats.LatencyEwma))
+	}
+	stats.ErrorRate = (1 - rpcPoolErrorRateEwmaAlpha) * stats.ErrorRate
+	stats.ConsecutiveFailures = 0
+	stats.LastSuccessTime = time.Now()
+	stats.CircuitOpen = false
+	stats.circuitCooldown = 0
+	stats.circuitHalfOpenTrial = false
+}
+
+func (p *rpcPool) recordFailure(endpoint string, err error) {
+	p.Lock()
+	defer p.Unlock()
+
+	stats, found := p.stats[endpoint]
+	if !found {
+		return
+	}
+
+	stats.TotalRequests++
+	stats.TotalFailures++
+	stats.ErrorRate = rpcPoolErrorRateEwmaAlpha + (1-rpcPoolErrorRateEwmaAlpha)*stats.ErrorRate
+	stats.ConsecutiveFailures++
+	stats.LastError = err.Error()
+	stats.LastErrorTime = time.Now()
+	stats.circuitHalfOpenTrial = false
+
+	if stats.ConsecutiveFailures >= rpcPoolCircuitBreakerFailures {
+		if stats.circuitCooldown == 0 {
+			stats.circuitCooldown = rpcPoolCircuitBreakerCooldown
+		} else {
+			stats.circuitCooldown *= 2
+			if stats.circuitCooldown > rpcPoolCircuitBreakerMaxCool {
+				stats.circuitCooldown = rpcPoolCircuitBreakerMaxCool
+			}
+		}
+		stats.CircuitOpen = true
+		stats.CircuitOpenUntil = time.Now().Add(stats.circuitCooldown)
+	}
+}
+
+func (p *rpcPool) recordStatus(endpoint string, resultStatus *coretypes.ResultStatus, chainId string) {
+	p.Lock()
+	defer p.Unlock()
+
+	stats, found := p.stats[endpoint]
+	if !found {
+		return
+	}
+
+	stats.NetworkMismatch = resultStatus.NodeInfo.Network != chainId
+	if stats.NetworkMismatch {
+		stats.LastError = fmt.Sprintf("network mismatch, expected %s, got %s", chainId, resultStatus.NodeInfo.Network)
+		stats.LastErrorTime = time.Now()
+	}
+	stats.LatestBlockHeight = resultStatus.SyncInfo.LatestBlockHeight
+	stats.LatestBlockTime = resultStatus.SyncInfo.LatestBlockTime
+}
@@ -0,0 +1,188 @@
+package rpc_client_registry
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/p2p"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeRpcQuerier struct {
+	sync.Mutex
+	network     string
+	height      int64
+	latency     time.Duration
+	fail        bool
+	abciQueries int
+}
+
+func (f *fakeRpcQuerier) ABCIQuery(_ context.Context, path string, _ tmbytes.HexBytes) (*coretypes.ResultABCIQuery, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.abciQueries++
+	time.Sleep(f.latency)
+	if f.fail {
+		return nil, fmt.Errorf("connection refused")
+	}
+	result := &coretypes.ResultABCIQuery{}
+	result.Response.Value = []byte(path)
+	return result, nil
+}
+
+func (f *fakeRpcQuerier) Status(_ context.Context) (*coretypes.ResultStatus, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	time.Sleep(f.latency)
+	if f.fail {
+		return nil, fmt.Errorf("connection refused")
+	}
+	return &coretypes.ResultStatus{
+		NodeInfo: p2p.DefaultNodeInfo{
+			Network: f.network,
+		},
+		SyncInfo: coretypes.SyncInfo{
+			LatestBlockHeight: f.height,
+			LatestBlockTime:   time.Now(),
+		},
+	}, nil
+}
+
+func newFakeRpcPool(clients map[string]*fakeRpcQuerier) *rpcPool {
+	var endpoints []string
+	for endpoint := range clients {
+		endpoints = append(endpoints, endpoint)
+	}
+	return newRpcPool("chain", endpoints, func(endpoint string) (RpcQuerier, error) {
+		return clients[endpoint], nil
+	}, nil)
+}
+
+func Test_rpcPool_Refresh(t *testing.T) {
+	clients := map[string]*fakeRpcQuerier{
+		"fast-lagging": {network: "chain-1", height: 90},
+		"slow":         {network: "chain-1", height: 100, latency: 20 * time.Millisecond},
+		"fast":         {network: "chain-1", height: 99},
+		"wrong-chain":  {network: "chain-2", height: 1000},
+		"down":         {network: "chain-1", fail: true},
+	}
+	pool := newFakeRpcPool(clients)
+
+	best, err := pool.Refresh(context.Background(), "chain-1")
+	require.NoError(t, err)
+	require.Equal(t, "fast", best.Endpoint, "up-to-date endpoint with the lowest latency should be the best")
+	require.Equal(t, int64(99), best.LatestBlockHeight)
+
+	ranked := pool.GetRankedEndpointsStats()
+	require.Len(t, ranked, len(clients))
+	require.Equal(t, "fast", ranked[0].Endpoint)
+	require.Equal(t, "slow", ranked[1].Endpoint)
+	require.Equal(t, "fast-lagging", ranked[2].Endpoint)
+	require.True(t, ranked[len(ranked)-1].NetworkMismatch || ranked[len(ranked)-2].NetworkMismatch)
+
+	clients["fast"].Lock()
+	clients["fast"].network = "chain-2"
+	clients["fast"].Unlock()
+	clients["slow"].Lock()
+	clients["slow"].fail = true
+	clients["slow"].Unlock()
+
+	best, err = pool.Refresh(context.Background(), "chain-1")
+	require.NoError(t, err)
+	require.Equal(t, "fast-lagging", best.Endpoint)
+
+	for _, client := range clients {
+		client.fail = true
+	}
+	_, err = pool.Refresh(context.Background(), "chain-1")
+	require.Error(t, err)
+}
+
+func Test_rpcPool_ABCIQueryFailover(t *testing.T) {
+	clients := map[string]*fakeRpcQuerier{
+		"primary":   {network: "chain-1", height: 100},
+		"secondary": {network: "chain-1", height: 100, latency: 5 * time.Millisecond},
+	}
+	pool := newFakeRpcPool(clients)
+
+	_, err := pool.Refresh(context.Background(), "chain-1")
+	require.NoError(t, err)
+
+	clients["primary"].fail = true
+
+	result, err := pool.ABCIQuery(context.Background(), "/path", nil)
+	require.NoError(t, err, "query should be transparently retried on the next-best endpoint")
+	require.Equal(t, "/path", string(result.Response.Value))
+	require.Equal(t, 1, clients["primary"].abciQueries)
+	require.Equal(t, 1, clients["secondary"].abciQueries)
+
+	ranked := pool.GetRankedEndpointsStats()
+	require.Equal(t, "secondary", ranked[0].Endpoint, "failing endpoint should be demoted")
+	require.Equal(t, "connection refused", ranked[1].LastError)
+	require.Greater(t, ranked[1].ErrorRate, 0.0)
+
+	// keep failing on probes
+	for i := 1; i < rpcPoolCircuitBreakerFailures; i++ {
+		best, err := pool.Refresh(context.Background(), "chain-1")
+		require.NoError(t, err)
+		require.Equal(t, "secondary", best.Endpoint)
+	}
+
+	ranked = pool.GetRankedEndpointsStats()
+	require.True(t, ranked[1].CircuitOpen, "circuit breaker of the failing endpoint should be opened")
+
+	// circuit opened, the failing endpoint should not be tried anymore
+	clients["secondary"].fail = true
+	_, err = pool.ABCIQuery(context.Background(), "/path", nil)
+	require.Error(t, err)
+	require.Equal(t, 1, clients["primary"].abciQueries)
+	clients["secondary"].fail = false
+
+	// after cool-down, a trial request closes the circuit on success
+	pool.Lock()
+	pool.stats["primary"].CircuitOpenUntil = time.Now().Add(-time.Second)
+	pool.Unlock()
+	clients["primary"].fail = false
+	clients["secondary"].fail = true
+
+	_, err = pool.ABCIQuery(context.Background(), "/path", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, clients["primary"].abciQueries)
+
+	ranked = pool.GetRankedEndpointsStats()
+	for _, stats := range ranked {
+		if stats.Endpoint == "primary" {
+			require.False(t, stats.CircuitOpen)
+			require.Zero(t, stats.ConsecutiveFailures)
+		}
+	}
+
+	clients["primary"].fail = true
+	_, err = pool.ABCIQuery(context.Background(), "/path", nil)
+	require.Error(t, err, "all endpoints failed")
+}
+
+func Test_rpcPool_UpdateEndpoints(t *testing.T) {
+	clients := map[string]*fakeRpcQuerier{
+		"a": {network: "chain-1", height: 100},
+		"b": {network: "chain-1", height: 100},
+	}
+	pool := newFakeRpcPool(clients)
+
+	_, err := pool.Refresh(context.Background(), "chain-1")
+	require.NoError(t, err)
+
+	pool.UpdateEndpoints([]string{"a", "c"})
+	ranked := pool.GetRankedEndpointsStats()
+	require.Len(t, ranked, 2)
+	require.Equal(t, "a", ranked[0].Endpoint, "stats of remaining endpoint should be kept")
+	require.Equal(t, uint64(1), ranked[0].TotalRequests)
+	require.Equal(t, "c", ranked[1].Endpoint)
+	require.Zero(t, ranked[1].TotalRequests)
+}
@@ -0,0 +1,85 @@
+package telegram_bot_registry
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"github.com/bcdevtools/validator-health-check/constants"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"time"
+)
+
+// pollUpdates receives updates via long polling and delivers them into the updates channel until stopped,
+// the updates channel is closed on return.
+//
+// Unlike tgbotapi.BotAPI.GetUpdatesChan, polling can be restarted after stopped,
+// so the instance can stop receiving updates when losing leadership and start again when re-elected.
+//
+// Telegram confirms the updates only when the next request is made with a higher offset,
+// the updates returned after stopped are not delivered, so they are re-delivered to the next instance polling updates.
+func (t *telegramBot) pollUpdates(updates chan<- tgbotapi.Update, stop <-chan struct{}, previousPollerDone <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	defer close(updates)
+
+	if previousPollerDone != nil {
+		// prevent conflict with the request of previous session which might not be completed yet
+		<-previousPollerDone
+	}
+
+	api := t.bot.ExposeBotAPI()
+	config := tgbotapi.NewUpdate(0)
+	config.Timeout = constants.TELEGRAM_LONG_POLLING_TIMEOUT
+	unconfirmed := false // delivered updates not yet confirmed by a subsequent request
+	stopped := func() bool {
+		select {
+		case <-stop:
+			if unconfirmed {
+				t.confirmPolledUpdates(api, config.Offset)
+			}
+			return true
+		default:
+			return false
+		}
+	}
+
+	for {
+		if stopped() {
+			return
+		}
+
+		batch, err := api.GetUpdates(config)
+		if err != nil {
+			t.logger.Error("failed to get updates of telegram bot", "bot", t.bot.GetBotUsername(), "error", err.Error())
+			select {
+			case <-stop:
+			case <-time.After(constants.TELEGRAM_LONG_POLLING_RETRY_DELAY):
+			}
+			continue
+		}
+		unconfirmed = false
+
+		for _, update := range batch {
+			if stopped() {
+				return
+			}
+
+			select {
+			case updates <- update:
+				if update.UpdateID >= config.Offset {
+					config.Offset = update.UpdateID + 1
+					unconfirmed = true
+				}
+			case <-stop:
+				_ = stopped()
+				return
+			}
+		}
+	}
+}
+
+// confirmPolledUpdates confirms the updates delivered, so they will not be re-delivered to the next instance polling updates
+func (t *telegramBot) confirmPolledUpdates(api *tgbotapi.BotAPI, offset int) {
+	config := tgbotapi.NewUpdate(offset)
+	config.Limit = 1 // updates returned are not confirmed
+	if _, err := api.GetUpdates(config); err != nil {
+		t.logger.Error("failed to confirm updates of telegram bot", "bot", t.bot.GetBotUsername(), "error", err.Error())
+	}
+}
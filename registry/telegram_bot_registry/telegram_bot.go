@@ -2,10 +2,14 @@ package telegram_bot_registry
 
 //goland:noinspection SpellCheckingInspection
 import (
+	"github.com/EscanBE/go-lib/logging"
 	libbot "github.com/EscanBE/go-lib/telegram/bot"
+	"github.com/bcdevtools/validator-health-check/constants"
+	"github.com/bcdevtools/validator-health-check/utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/google/uuid"
 	"sort"
+	"strconv"
 	"sync"
 )
 
@@ -15,7 +19,13 @@ type TelegramBot interface {
 	BotID() string
 	GetInnerTelegramBot() *libbot.TelegramBot
 	GetUpdatesChannel() tgbotapi.UpdatesChannel
+	// SuspendReceivingUpdates stops receiving updates until GetUpdatesChannel is called again
+	SuspendReceivingUpdates()
 	StopReceivingUpdates()
+	// WebhookId returns the ID of the bot, used as the last path segment of the webhook URL
+	WebhookId() string
+	// ReceiveWebhookUpdate enqueues the update received via webhook, returns false if the update can not be accepted now
+	ReceiveWebhookUpdate(update tgbotapi.Update) bool
 	AddChatIdWL(chatId int64)
 	GetAllChainIdsRL() []int64
 	PriorityWL()
@@ -28,15 +38,25 @@ type telegramBot struct {
 	sync.RWMutex
 	id       string
 	bot      *libbot.TelegramBot
+	logger   logging.Logger
 	chatIds  map[int64]bool
 	priority bool
+
+	useWebhook        bool
+	updates           chan tgbotapi.Update // nil if not receiving updates
+	stopPolling       chan struct{}        // closed to stop the long polling session
+	pollerDone        chan struct{}        // closed when the long polling session completed
+	webhookRegistered bool
+	stoppedReceiving  bool
 }
 
-func newTelegramBot(bot *libbot.TelegramBot) TelegramBot {
+func newTelegramBot(bot *libbot.TelegramBot, logger logging.Logger, useWebhook bool) *telegramBot {
 	return &telegramBot{
-		id:      uuid.New().String(),
-		bot:     bot,
-		chatIds: make(map[int64]bool),
+		id:         uuid.New().String(),
+		bot:        bot,
+		logger:     logger,
+		chatIds:    make(map[int64]bool),
+		useWebhook: useWebhook,
 	}
 }
 
@@ -48,12 +68,127 @@ func (t *telegramBot) GetInnerTelegramBot() *libbot.TelegramBot {
 	return t.bot
 }
 
+// GetUpdatesChannel starts receiving updates, via long polling or by registering the webhook.
+// The channel is closed when receiving updates is suspended or stopped.
 func (t *telegramBot) GetUpdatesChannel() tgbotapi.UpdatesChannel {
-	return t.bot.GetUpdatesChannel()
+	t.Lock()
+	if t.updates != nil {
+		// already receiving
+		updates := t.updates
+		t.Unlock()
+		return updates
+	}
+
+	if t.stoppedReceiving {
+		t.Unlock()
+		updates := make(chan tgbotapi.Update)
+		close(updates)
+		return updates
+	}
+
+	if !t.useWebhook {
+		updates := make(chan tgbotapi.Update) // unbuffered, only the updates taken by receiver are confirmed
+		t.updates = updates
+		t.stopPolling = make(chan struct{})
+		previousPollerDone := t.pollerDone
+		t.pollerDone = make(chan struct{})
+		go t.pollUpdates(updates, t.stopPolling, previousPollerDone, t.pollerDone)
+		t.Unlock()
+		return updates
+	}
+
+	updates := make(chan tgbotapi.Update, constants.TELEGRAM_WEBHOOK_UPDATES_BUFFER)
+	t.updates = updates
+	t.Unlock()
+
+	publicUrl, secretToken, _ := getWebhookSettingsRL()
+	webhookUrl := publicUrl + "/" + t.WebhookId()
+	_, err := utils.Retry(func() (any, error) {
+		return nil, setWebhook(t.bot.ExposeBotAPI(), webhookUrl, secretToken)
+	})
+	if err != nil {
+		t.logger.Error("failed to register webhook of telegram bot", "bot", t.bot.GetBotUsername(), "error", err.Error())
+	} else {
+		t.Lock()
+		// not registered if suspended meanwhile, eg: lost leadership
+		t.webhookRegistered = t.updates == updates
+		t.Unlock()
+		t.logger.Info("registered webhook of telegram bot", "bot", t.bot.GetBotUsername(), "url", publicUrl+"/***")
+	}
+
+	return updates
 }
 
+// SuspendReceivingUpdates stops receiving updates, eg: when losing leadership.
+// Receiving updates can be started again by GetUpdatesChannel.
+//
+// The webhook is not deregistered but no longer considered registered by this instance,
+// so it will not be deregistered on shutdown, because it might have been registered by the new leader.
+func (t *telegramBot) SuspendReceivingUpdates() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.closeUpdatesChannel()
+	t.webhookRegistered = false
+}
+
+// StopReceivingUpdates stops receiving updates permanently, the webhook is deregistered if registered by this instance
 func (t *telegramBot) StopReceivingUpdates() {
-	t.bot.StopReceivingUpdates()
+	t.Lock()
+	if t.stoppedReceiving {
+		t.Unlock()
+		return
+	}
+	t.stoppedReceiving = true
+	t.closeUpdatesChannel()
+	webhookRegistered := t.webhookRegistered
+	t.Unlock()
+
+	if !webhookRegistered {
+		// not the instance receiving updates, eg: standby instance
+		return
+	}
+
+	if err := deleteWebhook(t.bot.ExposeBotAPI()); err != nil {
+		t.logger.Error("failed to deregister webhook of telegram bot", "bot", t.bot.GetBotUsername(), "error", err.Error())
+	}
+}
+
+// closeUpdatesChannel closes the channel of the current session of receiving updates, if any.
+// Must be called with the lock held.
+func (t *telegramBot) closeUpdatesChannel() {
+	if t.updates == nil {
+		return
+	}
+
+	if t.useWebhook {
+		close(t.updates)
+	} else {
+		close(t.stopPolling) // the channel is closed by the poller
+		t.stopPolling = nil
+	}
+	t.updates = nil
+}
+
+func (t *telegramBot) WebhookId() string {
+	return strconv.FormatInt(t.bot.ExposeBotAPI().Self.ID, 10)
+}
+
+func (t *telegramBot) ReceiveWebhookUpdate(update tgbotapi.Update) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	if !t.useWebhook || t.updates == nil || !t.webhookRegistered {
+		// not receiving updates via webhook, eg: standby instance, let Telegram re-deliver
+		return false
+	}
+
+	select {
+	case t.updates <- update:
+		return true
+	default:
+		return false // buffer is full
+	}
 }
 
 func (t *telegramBot) AddChatIdWL(chatId int64) {
@@ -44,7 +44,7 @@ func GetTelegramBotByTokenWL(token string, logger logging.Logger) (TelegramBot,
 	}
 
 	_bot = _bot.WithLogger(logger)
-	bot = newTelegramBot(_bot)
+	bot = newTelegramBot(_bot, logger, webhookPublicUrl != "")
 
 	globalTelegramBotByToken[token] = bot
 
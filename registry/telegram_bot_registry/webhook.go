@@ -0,0 +1,70 @@
+package telegram_bot_registry
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"encoding/json"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+var webhookPublicUrl string   // empty if receiving updates via long polling
+var webhookSecretToken string // sent by Telegram in header of each update
+
+// webhookRequester performs requests to Telegram Bot API, implemented by tgbotapi.BotAPI
+type webhookRequester interface {
+	MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+}
+
+// UseWebhookWL switches bots to receive updates via webhook instead of long polling.
+// Must be called before any bot is created.
+func UseWebhookWL(publicUrl, secretToken string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if len(globalTelegramBotByToken) > 0 {
+		panic("webhook must be configured before creating any bot")
+	}
+
+	webhookPublicUrl = strings.TrimSuffix(publicUrl, "/")
+	webhookSecretToken = secretToken
+}
+
+// getWebhookSettingsRL returns the webhook settings, enabled is false if receiving updates via long polling
+func getWebhookSettingsRL() (publicUrl, secretToken string, enabled bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	return webhookPublicUrl, webhookSecretToken, webhookPublicUrl != ""
+}
+
+// GetTelegramBotByWebhookIdRL returns the bot receiving updates via webhook with the given ID
+func GetTelegramBotByWebhookIdRL(webhookId string) (TelegramBot, bool) {
+	for _, bot := range GetAllTelegramBotsRL() {
+		if bot.WebhookId() == webhookId {
+			return bot, true
+		}
+	}
+	return nil, false
+}
+
+// setWebhook registers the webhook URL of the bot, Telegram delivers updates with the secret token in header
+func setWebhook(requester webhookRequester, webhookUrl, secretToken string) error {
+	allowedUpdates, err := json.Marshal([]string{"message"})
+	if err != nil {
+		return err
+	}
+
+	_, err = requester.MakeRequest("setWebhook", tgbotapi.Params{
+		"url":             webhookUrl,
+		"secret_token":    secretToken,
+		"allowed_updates": string(allowedUpdates),
+	})
+	return errors.Wrap(err, "failed to set webhook")
+}
+
+// deleteWebhook deregisters the webhook of the bot, pending updates are kept to be delivered later
+func deleteWebhook(requester webhookRequester) error {
+	_, err := requester.MakeRequest("deleteWebhook", tgbotapi.Params{})
+	return errors.Wrap(err, "failed to delete webhook")
+}
@@ -2,6 +2,7 @@ package user_registry
 
 import (
 	"github.com/bcdevtools/validator-health-check/config"
+	"sort"
 	"sync"
 )
 
@@ -69,3 +70,18 @@ func GetRootUsersIdentityRL() []string {
 	}
 	return rootUsersIdentity
 }
+
+// GetAllUserRecordsRL returns all the user records, sorted by identity
+func GetAllUserRecordsRL() config.UserRecords {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	userRecords := make(config.UserRecords, 0, len(globalIdentityToUsersConfig))
+	for _, userRecord := range globalIdentityToUsersConfig {
+		userRecords = append(userRecords, userRecord)
+	}
+	sort.Slice(userRecords, func(i, j int) bool {
+		return userRecords[i].Identity < userRecords[j].Identity
+	})
+	return userRecords
+}
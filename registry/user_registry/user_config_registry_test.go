@@ -12,6 +12,7 @@ func TestUpdateUsersConfigWL(t *testing.T) {
 	require.NoError(t, UpdateUsersConfigWL(config.UserRecords{
 		{
 			Identity: "1i",
+			Root:     true,
 			TelegramConfig: &config.UserTelegramConfig{
 				Username: "1u",
 				UserId:   1,
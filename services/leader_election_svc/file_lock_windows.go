@@ -0,0 +1,16 @@
+//go:build windows
+
+package leader_election_svc
+
+import (
+	"fmt"
+	"os"
+)
+
+func lockFileExclusive(_ *os.File) error {
+	return fmt.Errorf("file lease store is not supported on windows")
+}
+
+func unlockFile(_ *os.File) error {
+	return nil
+}
@@ -0,0 +1,218 @@
+package leader_election_svc
+
+//goland:noinspection SpellCheckingInspection
+import (
+	libapp "github.com/EscanBE/go-lib/app"
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/bcdevtools/validator-health-check/config"
+	"sync"
+	"time"
+)
+
+// TakeoverHandler is invoked when this instance took over the leadership from another instance
+// whose lease expired without being released, means the heartbeat of the previous leader disappeared.
+type TakeoverHandler func(previousLeader Lease)
+
+var electorMutex sync.RWMutex
+var globalElector *elector
+
+type elector struct {
+	sync.RWMutex
+	logger        logging.Logger
+	store         LeaseStore
+	instanceId    string
+	leaseDuration time.Duration
+	onTakeover    TakeoverHandler
+
+	leader         bool
+	leaseExpiresAt time.Time // expiry of the lease held by this instance
+	currentLeader  Lease     // latest known lease, held by this or another instance
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// StartLeaderElectionService starts the routine competing for the leader lease.
+// Until elected, this instance is standby: health-checks keep running to keep the state warm but no alert is sent.
+// When high-availability is not enabled, the service is not started and this instance is always the leader.
+func StartLeaderElectionService(appCtx config.AppContext, store LeaseStore, onTakeover TakeoverHandler) {
+	electorMutex.Lock()
+	defer electorMutex.Unlock()
+
+	if globalElector != nil {
+		panic("leader election service already started")
+	}
+
+	globalElector = newElector(appCtx.Logger, store, appCtx.AppConfig.HighAvailability.GetInstanceId(), appCtx.AppConfig.HighAvailability.GetLeaseDuration(), onTakeover)
+	go globalElector.start()
+}
+
+// StopLeaderElectionService stops competing for the leader lease and releases the lease if held,
+// so the standby instance can take over immediately.
+func StopLeaderElectionService() {
+	electorMutex.RLock()
+	e := globalElector
+	electorMutex.RUnlock()
+
+	if e == nil {
+		return
+	}
+
+	e.shutdown()
+}
+
+// IsLeaderRL returns true if this instance is the leader, always true when high-availability is not enabled
+func IsLeaderRL() bool {
+	electorMutex.RLock()
+	e := globalElector
+	electorMutex.RUnlock()
+
+	if e == nil {
+		return true
+	}
+
+	return e.isLeaderRL()
+}
+
+// WaitUntilLeader blocks until this instance is the leader
+func WaitUntilLeader() {
+	for !IsLeaderRL() {
+		time.Sleep(time.Second)
+	}
+}
+
+// GetStatusRL returns the ID of this instance, whether it is the leader and the latest known lease.
+// Returns enabled=false when high-availability is not enabled.
+func GetStatusRL() (enabled bool, instanceId string, leader bool, currentLeader Lease) {
+	electorMutex.RLock()
+	e := globalElector
+	electorMutex.RUnlock()
+
+	if e == nil {
+		return false, "", true, Lease{}
+	}
+
+	e.RLock()
+	defer e.RUnlock()
+
+	return true, e.instanceId, e.leader, e.currentLeader
+}
+
+func newElector(logger logging.Logger, store LeaseStore, instanceId string, leaseDuration time.Duration, onTakeover TakeoverHandler) *elector {
+	return &elector{
+		logger:        logger,
+		store:         store,
+		instanceId:    instanceId,
+		leaseDuration: leaseDuration,
+		onTakeover:    onTakeover,
+		stop:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+}
+
+func (e *elector) start() {
+	defer libapp.TryRecoverAndExecuteExitFunctionIfRecovered(e.logger)
+	defer close(e.stopped)
+
+	e.logger.Info("starting leader election", "instance", e.instanceId, "lease-duration", e.leaseDuration)
+
+	// renew well before expiry, tolerates a missed renewal
+	ticker := time.NewTicker(e.leaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		e.runRound(time.Now().UTC())
+
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runRound tries to acquire or renew the lease, updates the leadership state accordingly
+func (e *elector) runRound(now time.Time) {
+	previous, acquired, err := e.store.TryAcquire(e.instanceId, e.leaseDuration, now)
+	if err != nil {
+		e.logger.Error("failed to acquire leader lease", "instance", e.instanceId, "error", err.Error())
+
+		e.Lock()
+		defer e.Unlock()
+		if e.leader && !now.Before(e.leaseExpiresAt) {
+			// can not renew, other instance may take over at any time
+			e.leader = false
+			e.logger.Error("stepped down from leader, lease expired without renewal", "instance", e.instanceId)
+		}
+		return
+	}
+
+	var tookOver bool
+	func() {
+		e.Lock()
+		defer e.Unlock()
+
+		if !acquired {
+			e.currentLeader = previous
+			if e.leader {
+				e.leader = false
+				e.logger.Error("lost leadership, lease is held by other instance", "instance", e.instanceId, "leader", previous.Holder)
+			}
+			return
+		}
+
+		e.leaseExpiresAt = now.Add(e.leaseDuration)
+		e.currentLeader = Lease{
+			Holder:     e.instanceId,
+			AcquiredAt: now,
+			RenewedAt:  now,
+			ExpiresAt:  e.leaseExpiresAt,
+		}
+		if previous.Holder == e.instanceId {
+			e.currentLeader.AcquiredAt = previous.AcquiredAt
+		}
+
+		if e.leader {
+			return
+		}
+
+		e.leader = true
+		tookOver = previous.Holder != "" && previous.Holder != e.instanceId
+		e.logger.Info("became leader", "instance", e.instanceId, "previous-leader", previous.Holder)
+	}()
+
+	if tookOver && e.onTakeover != nil {
+		e.onTakeover(previous)
+	}
+}
+
+func (e *elector) shutdown() {
+	select {
+	case <-e.stop:
+		return
+	default:
+		close(e.stop)
+	}
+	<-e.stopped
+
+	e.Lock()
+	defer e.Unlock()
+
+	if !e.leader {
+		return
+	}
+
+	e.leader = false
+	if err := e.store.Release(e.instanceId); err != nil {
+		e.logger.Error("failed to release leader lease", "instance", e.instanceId, "error", err.Error())
+	} else {
+		e.logger.Info("released leader lease", "instance", e.instanceId)
+	}
+}
+
+func (e *elector) isLeaderRL() bool {
+	e.RLock()
+	defer e.RUnlock()
+
+	return e.leader
+}
@@ -0,0 +1,97 @@
+package leader_election_svc
+
+import (
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/stretchr/testify/require"
+	"path"
+	"testing"
+	"time"
+)
+
+func Test_fileLeaseStore(t *testing.T) {
+	store := NewFileLeaseStore(path.Join(t.TempDir(), "leader.lease"))
+	now := time.Now().UTC()
+	ttl := 30 * time.Second
+
+	previous, acquired, err := store.TryAcquire("a", ttl, now)
+	require.NoError(t, err)
+	require.True(t, acquired, "free lease should be acquired")
+	require.Empty(t, previous.Holder)
+
+	previous, acquired, err = store.TryAcquire("b", ttl, now.Add(time.Second))
+	require.NoError(t, err)
+	require.False(t, acquired, "lease held by other instance should not be acquired")
+	require.Equal(t, "a", previous.Holder)
+
+	previous, acquired, err = store.TryAcquire("a", ttl, now.Add(10*time.Second))
+	require.NoError(t, err)
+	require.True(t, acquired, "holder should be able to renew")
+	require.Equal(t, "a", previous.Holder)
+
+	previous, acquired, err = store.TryAcquire("b", ttl, now.Add(10*time.Second+ttl))
+	require.NoError(t, err)
+	require.True(t, acquired, "expired lease should be taken over")
+	require.Equal(t, "a", previous.Holder)
+	require.Equal(t, now, previous.AcquiredAt, "acquired time should be kept on renewal")
+
+	require.NoError(t, store.Release("a"), "release by non-holder should be no-op")
+	_, acquired, err = store.TryAcquire("a", ttl, now.Add(11*time.Second+ttl))
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	require.NoError(t, store.Release("b"))
+	previous, acquired, err = store.TryAcquire("a", ttl, now.Add(11*time.Second+ttl))
+	require.NoError(t, err)
+	require.True(t, acquired, "released lease should be acquired immediately")
+	require.Empty(t, previous.Holder)
+}
+
+func Test_elector_runRound(t *testing.T) {
+	store := NewFileLeaseStore(path.Join(t.TempDir(), "leader.lease"))
+	ttl := 30 * time.Second
+	now := time.Now().UTC()
+
+	var takeovers []Lease
+	onTakeover := func(previousLeader Lease) {
+		takeovers = append(takeovers, previousLeader)
+	}
+
+	leader := newElector(logging.NewDefaultLogger(), store, "leader", ttl, onTakeover)
+	standby := newElector(logging.NewDefaultLogger(), store, "standby", ttl, onTakeover)
+
+	leader.runRound(now)
+	standby.runRound(now)
+	require.True(t, leader.isLeaderRL())
+	require.False(t, standby.isLeaderRL())
+	require.Equal(t, "leader", standby.currentLeader.Holder)
+
+	// leader keeps renewing
+	for i := 1; i <= 5; i++ {
+		at := now.Add(time.Duration(i) * ttl / 3)
+		leader.runRound(at)
+		standby.runRound(at)
+		require.True(t, leader.isLeaderRL())
+		require.False(t, standby.isLeaderRL())
+	}
+	require.Empty(t, takeovers)
+
+	// heartbeat of leader disappeared
+	at := now.Add(5*ttl/3 + ttl)
+	standby.runRound(at)
+	require.True(t, standby.isLeaderRL())
+	require.Len(t, takeovers, 1, "root users should be informed")
+	require.Equal(t, "leader", takeovers[0].Holder)
+
+	// previous leader comes back, steps down
+	leader.runRound(at.Add(time.Second))
+	require.False(t, leader.isLeaderRL())
+	require.Equal(t, "standby", leader.currentLeader.Holder)
+
+	// graceful hand-over does not alert
+	close(standby.stopped)
+	standby.shutdown()
+	require.False(t, standby.isLeaderRL())
+	leader.runRound(at.Add(2 * time.Second))
+	require.True(t, leader.isLeaderRL())
+	require.Len(t, takeovers, 1)
+}
@@ -0,0 +1,66 @@
+package leader_election_svc
+
+import (
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	"sync"
+	"time"
+)
+
+// Lease is the leadership lease, held by the leader and renewed periodically as heartbeat
+type Lease struct {
+	Holder     string    `json:"holder"` // instance ID of the leader, empty if released
+	AcquiredAt time.Time `json:"acquired-at"`
+	RenewedAt  time.Time `json:"renewed-at"`
+	ExpiresAt  time.Time `json:"expires-at"`
+}
+
+// IsHeldAt returns true if the lease is held by any instance and not yet expired at the given time
+func (l Lease) IsHeldAt(now time.Time) bool {
+	return l.Holder != "" && now.Before(l.ExpiresAt)
+}
+
+// LeaseStore is the backend storing the leadership lease, shared by all instances.
+type LeaseStore interface {
+	// TryAcquire acquires or renews the lease for the holder, if the lease is free, expired or already held by the holder.
+	// Returns the lease before this attempt and whether the holder now holds the lease.
+	TryAcquire(holder string, ttl time.Duration, now time.Time) (previous Lease, acquired bool, err error)
+
+	// Release releases the lease if held by the holder, so other instances can take over immediately.
+	Release(holder string) error
+}
+
+// LeaseStoreFactory creates lease store from the high-availability config
+type LeaseStoreFactory func(haConfig config.HighAvailabilityConfig, homeDir string) (LeaseStore, error)
+
+var backendsMutex sync.RWMutex
+var leaseStoreBackends map[string]LeaseStoreFactory
+
+// RegisterLeaseStoreBackend registers a lease store backend, to be selected via `ha.backend` config
+func RegisterLeaseStoreBackend(backend string, factory LeaseStoreFactory) {
+	backendsMutex.Lock()
+	defer backendsMutex.Unlock()
+
+	leaseStoreBackends[backend] = factory
+}
+
+// NewLeaseStore creates lease store of the configured backend
+func NewLeaseStore(haConfig config.HighAvailabilityConfig, homeDir string) (LeaseStore, error) {
+	backendsMutex.RLock()
+	factory, found := leaseStoreBackends[haConfig.GetBackend()]
+	backendsMutex.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("unknown lease store backend %s", haConfig.GetBackend())
+	}
+
+	return factory(haConfig, homeDir)
+}
+
+func init() {
+	leaseStoreBackends = make(map[string]LeaseStoreFactory)
+	leaseStoreBackends[constants.HA_BACKEND_FILE] = func(haConfig config.HighAvailabilityConfig, homeDir string) (LeaseStore, error) {
+		return NewFileLeaseStore(haConfig.GetLeaseFilePath(homeDir)), nil
+	}
+}
@@ -0,0 +1,140 @@
+package leader_election_svc
+
+import (
+	"encoding/json"
+	"github.com/bcdevtools/validator-health-check/constants"
+	"github.com/pkg/errors"
+	"os"
+	"path"
+	"time"
+)
+
+var _ LeaseStore = &fileLeaseStore{}
+
+// fileLeaseStore stores the lease as JSON file, guarded by an exclusive lock on a sibling `.lock` file.
+// All instances must access the same file, e.g. on the same host or a shared volume supporting file locks.
+type fileLeaseStore struct {
+	leaseFile string
+}
+
+// NewFileLeaseStore creates lease store backed by the given lease file
+func NewFileLeaseStore(leaseFile string) LeaseStore {
+	return &fileLeaseStore{
+		leaseFile: leaseFile,
+	}
+}
+
+func (s *fileLeaseStore) TryAcquire(holder string, ttl time.Duration, now time.Time) (previous Lease, acquired bool, err error) {
+	err = s.withLock(func() error {
+		previous, err = s.read()
+		if err != nil {
+			return err
+		}
+
+		if previous.Holder != holder && previous.IsHeldAt(now) {
+			return nil
+		}
+
+		lease := Lease{
+			Holder:     holder,
+			AcquiredAt: now,
+			RenewedAt:  now,
+			ExpiresAt:  now.Add(ttl),
+		}
+		if previous.Holder == holder {
+			lease.AcquiredAt = previous.AcquiredAt
+		}
+
+		if err := s.write(lease); err != nil {
+			return err
+		}
+
+		acquired = true
+		return nil
+	})
+	return
+}
+
+func (s *fileLeaseStore) Release(holder string) error {
+	return s.withLock(func() error {
+		lease, err := s.read()
+		if err != nil {
+			return err
+		}
+
+		if lease.Holder != holder {
+			return nil
+		}
+
+		return s.write(Lease{})
+	})
+}
+
+func (s *fileLeaseStore) withLock(f func() error) error {
+	lockFile, err := os.OpenFile(s.leaseFile+".lock", os.O_CREATE|os.O_RDWR, constants.FILE_PERMISSION)
+	if err != nil {
+		return errors.Wrap(err, "failed to open lock file")
+	}
+	defer func() {
+		_ = lockFile.Close()
+	}()
+
+	if err := lockFileExclusive(lockFile); err != nil {
+		return errors.Wrap(err, "failed to lock lease file")
+	}
+	defer func() {
+		_ = unlockFile(lockFile)
+	}()
+
+	return f()
+}
+
+func (s *fileLeaseStore) read() (Lease, error) {
+	bz, err := os.ReadFile(s.leaseFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Lease{}, nil
+		}
+		return Lease{}, errors.Wrap(err, "failed to read lease file")
+	}
+
+	if len(bz) == 0 {
+		return Lease{}, nil
+	}
+
+	var lease Lease
+	if err := json.Unmarshal(bz, &lease); err != nil {
+		return Lease{}, errors.Wrap(err, "failed to decode lease file")
+	}
+	return lease, nil
+}
+
+// write replaces the lease file atomically, readers never see a partially written lease
+func (s *fileLeaseStore) write(lease Lease) error {
+	bz, err := json.Marshal(lease)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode lease")
+	}
+
+	tmpFile, err := os.CreateTemp(path.Dir(s.leaseFile), path.Base(s.leaseFile)+".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp lease file")
+	}
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	_, err = tmpFile.Write(bz)
+	if errClose := tmpFile.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to write temp lease file")
+	}
+
+	if err := os.Chmod(tmpFile.Name(), constants.FILE_PERMISSION); err != nil {
+		return errors.Wrap(err, "failed to set permission of temp lease file")
+	}
+
+	return errors.Wrap(os.Rename(tmpFile.Name(), s.leaseFile), "failed to replace lease file")
+}
@@ -0,0 +1,99 @@
+package telegram_call_center_svc
+
+import (
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxAuditArgsLength     = 200 // args recorded into the audit log are truncated to this length
+	maxAuditResponseLength = 100 // response recorded into the audit log is summarized to this length
+	maxAuditMessageLength  = 3500
+)
+
+// processCommandAudit processes command /audit [n], root only.
+// Shows the latest entries of the audit log.
+func (e *employee) processCommandAudit(updateCtx *telegramUpdateCtx) error {
+	if !updateCtx.isRootUser {
+		return e.sendRootOnlyResponse(updateCtx)
+	}
+
+	n := constants.AUDIT_LOG_DEFAULT_ENTRIES
+	args := strings.Fields(updateCtx.commandArgs())
+	if len(args) > 1 {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Usage: /%s [n]", constants.CommandAudit))
+	}
+	if len(args) > 0 {
+		var err error
+		n, err = strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > constants.AUDIT_LOG_MAX_ENTRIES {
+			return e.sendResponse(updateCtx, fmt.Sprintf("Number of entries must be from 1 to %d", constants.AUDIT_LOG_MAX_ENTRIES))
+		}
+	}
+
+	entries, err := e.auditLog.Recent(n)
+	if err != nil {
+		_ = e.sendResponse(updateCtx, "Failed to read the audit log, please try again later")
+		return err
+	}
+	if len(entries) == 0 {
+		return e.sendResponse(updateCtx, "No audit entry")
+	}
+
+	// entries are sent in multiple messages if exceeding the Telegram message length limit
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Latest %d audit entries:", len(entries)))
+	for _, entry := range entries {
+		var line strings.Builder
+		line.WriteString(fmt.Sprintf("\n\n%s /%s", updateCtx.formatTime(entry.Time), entry.Command))
+		if entry.Args != "" {
+			line.WriteString(" ")
+			line.WriteString(entry.Args)
+		}
+		if entry.Identity != "" {
+			line.WriteString(fmt.Sprintf("\nby %s", entry.Identity))
+		} else {
+			line.WriteString("\nby unknown user")
+		}
+		line.WriteString(fmt.Sprintf(" (%d", entry.UserId))
+		if entry.Username != "" {
+			line.WriteString(fmt.Sprintf(" @%s", entry.Username))
+		}
+		if entry.ChatId != entry.UserId {
+			line.WriteString(fmt.Sprintf(", chat %d", entry.ChatId))
+		}
+		line.WriteString(fmt.Sprintf("), %s", entry.Outcome))
+		if entry.Response != "" {
+			line.WriteString(fmt.Sprintf("\n> %s", entry.Response))
+		}
+		if entry.Error != "" {
+			line.WriteString(fmt.Sprintf("\nError: %s", summarizeAuditText(entry.Error, maxAuditResponseLength)))
+		}
+
+		if sb.Len()+line.Len() > maxAuditMessageLength {
+			if err := e.sendResponse(updateCtx, sb.String()); err != nil {
+				return err
+			}
+			sb.Reset()
+		}
+		sb.WriteString(line.String())
+	}
+
+	return e.sendResponse(updateCtx, strings.TrimPrefix(sb.String(), "\n\n"))
+}
+
+// summarizeAuditText returns the first line of the text, truncated to the max length
+func summarizeAuditText(text string, maxLength int) string {
+	text = strings.TrimSpace(text)
+	if idx := strings.IndexByte(text, '\n'); idx >= 0 {
+		text = strings.TrimSpace(text[:idx]) + " ..."
+	}
+
+	runes := []rune(text)
+	if len(runes) > maxLength {
+		text = string(runes[:maxLength]) + "..."
+	}
+	return text
+}
@@ -0,0 +1,132 @@
+package telegram_call_center_svc
+
+import (
+	"fmt"
+	libapp "github.com/EscanBE/go-lib/app"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	tptemplates "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/templates"
+	"github.com/bcdevtools/validator-health-check/utils"
+	hcw "github.com/bcdevtools/validator-health-check/work/health_check_worker"
+	"strings"
+	"time"
+)
+
+// processCommandCheck processes command /check <chain or valoper>.
+// An out-of-band health-check of the chain is scheduled, findings are replied when the health-check completes.
+func (e *employee) processCommandCheck(updateCtx *telegramUpdateCtx) error {
+	args := strings.Fields(updateCtx.commandArgs())
+	if len(args) != 1 {
+		if updateCtx.isRootUser {
+			return e.sendResponse(updateCtx, fmt.Sprintf("Usage: /%s <chain or valoper>", constants.CommandCheck))
+		}
+		return e.sendResponse(updateCtx, fmt.Sprintf("Usage: /%s <valoper>", constants.CommandCheck))
+	}
+	target := args[0]
+
+	var chainName, valoper string
+	if updateCtx.isRootUser && chainreg.HasChainRL(target) {
+		chainName = target
+	} else {
+		for _, chain := range chainreg.GetCopyAllChainConfigsRL() {
+			for _, validator := range chain.GetValidators() {
+				if validator.ValidatorOperatorAddress != target {
+					continue
+				}
+				if updateCtx.isRootUser || utils.Contains(validator.WatchersIdentity, updateCtx.identity) {
+					chainName = chain.GetChainName()
+					valoper = target
+				}
+			}
+		}
+	}
+
+	if chainName == "" {
+		if updateCtx.isRootUser {
+			return e.sendResponse(updateCtx, fmt.Sprintf("No chain or validator found with the provided identifier!\nSee the list at /%s or /%s or use /%s", constants.CommandChains, constants.CommandValidators, constants.CommandSearch))
+		}
+		return e.sendResponse(updateCtx, fmt.Sprintf("No validator found with the provided identifier!\nSee the list at /%s or use /%s", constants.CommandValidators, constants.CommandSearch))
+	}
+
+	if paused, _ := chainreg.IsChainPausedRL(chainName); paused {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Chain [%s] is paused, see /%s", chainName, constants.CommandStatus))
+	}
+
+	if !e.rateLimiter.Request(fmt.Sprintf("%s-%d", constants.CommandCheck, updateCtx.userId()), constants.MIN_DURATION_BETWEEN_ON_DEMAND_HEALTH_CHECK) {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Only one /%s per %s is allowed, please try again later", constants.CommandCheck, constants.MIN_DURATION_BETWEEN_ON_DEMAND_HEALTH_CHECK))
+	}
+
+	requestedAt := time.Now().UTC()
+	completed, stopWaiting := chainreg.RequestHealthCheckAndWaitWL(chainName)
+
+	go func() {
+		logger := e.appCtx.Logger
+		defer libapp.TryRecoverAndExecuteExitFunctionIfRecovered(logger)
+
+		var findings string
+		select {
+		case <-completed:
+			findings = describeOnDemandHealthCheckFindings(updateCtx, chainName, valoper, requestedAt)
+		case <-time.After(constants.ON_DEMAND_HEALTH_CHECK_TIMEOUT):
+			stopWaiting()
+			findings = fmt.Sprintf("Health-check of [%s] did not complete within %s, please check /%s later", chainName, constants.ON_DEMAND_HEALTH_CHECK_TIMEOUT, constants.CommandLast)
+		}
+
+		if err := e.sendLongResponse(updateCtx, findings); err != nil {
+			logger.Error("failed to reply findings of on-demand health-check", "chain", chainName, "valoper", valoper, "error", err.Error())
+		}
+	}()
+
+	return e.sendResponse(updateCtx, fmt.Sprintf("Health-check of [%s] has been scheduled, findings will be replied when completed", chainName))
+}
+
+// describeOnDemandHealthCheckFindings describes the findings of the health-check of the chain, completed after the request.
+// If valoper is provided, the latest health-check result of the validator is included.
+func describeOnDemandHealthCheckFindings(updateCtx *telegramUpdateCtx, chainName, valoper string, requestedAt time.Time) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Health-check findings of [%s]", chainName))
+	describeFindings := func(findings []hcw.HealthCheckFinding) {
+		if len(findings) == 0 {
+			sb.WriteString(" None")
+			return
+		}
+		for _, finding := range findings {
+			sb.WriteString(fmt.Sprintf("\n%s %s", tptemplates.GetSeverityEmoji(finding.Severity), finding.Message))
+			if finding.Suppressed {
+				sb.WriteString(" (alert suppressed by spam prevention)")
+			}
+		}
+	}
+
+	sb.WriteString("\n\nChain findings:")
+	describeFindings(hcw.GetFindingsSinceRL(chainName, "", requestedAt))
+
+	var valopers []string
+	if valoper != "" {
+		valopers = append(valopers, valoper)
+	} else if chain, found := chainreg.GetChainConfigRL(chainName); found {
+		for _, validator := range chain.GetValidators() {
+			valopers = append(valopers, validator.ValidatorOperatorAddress)
+		}
+	}
+
+	for _, valoper := range valopers {
+		sb.WriteString("\n\n")
+		cache, found := hcw.GetCacheValidatorHealthCheckRL(valoper)
+		if !found || cache.TimeOccurs.Before(requestedAt) {
+			sb.WriteString(fmt.Sprintf("Validator %s was not health-checked", valoper))
+			continue
+		}
+
+		if len(valopers) == 1 {
+			sb.WriteString(describeValidatorHealthCheck(updateCtx, cache))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s (%s)", cache.Moniker, valoper))
+		}
+		sb.WriteString("\nFindings:")
+		describeFindings(hcw.GetFindingsSinceRL(chainName, valoper, requestedAt))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,111 @@
+package telegram_call_center_svc
+
+import (
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	"github.com/bcdevtools/validator-health-check/utils"
+	hcw "github.com/bcdevtools/validator-health-check/work/health_check_worker"
+	"strings"
+	"time"
+)
+
+// processCommandGov processes command /gov [chain].
+// Lists the proposals on voting period of the subscribed chains and the votes of the watched validators.
+// Root users can provide any chain, all validators of the chain are listed.
+func (e *employee) processCommandGov(updateCtx *telegramUpdateCtx) error {
+	args := strings.Fields(updateCtx.commandArgs())
+	if len(args) > 1 {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Usage: /%s [chain]", constants.CommandGov))
+	}
+
+	type chainToList struct {
+		chainName string
+		valopers  []string
+	}
+
+	var chains []chainToList
+	for _, chain := range chainreg.GetCopyAllChainConfigsRL().Sort() {
+		if len(args) > 0 && chain.GetChainName() != args[0] {
+			continue
+		}
+
+		var valopers []string
+		for _, validator := range chain.GetValidators() {
+			if utils.Contains(validator.WatchersIdentity, updateCtx.identity) || (updateCtx.isRootUser && len(args) > 0) {
+				valopers = append(valopers, validator.ValidatorOperatorAddress)
+			}
+		}
+		if len(valopers) > 0 {
+			chains = append(chains, chainToList{
+				chainName: chain.GetChainName(),
+				valopers:  valopers,
+			})
+		}
+	}
+
+	if len(chains) == 0 {
+		if len(args) > 0 {
+			return e.sendResponse(updateCtx, fmt.Sprintf("No chain you subscribed found with the provided name!\nSee the list at /%s", constants.CommandChains))
+		}
+		return e.sendResponse(updateCtx, "You did not subscribe any chain")
+	}
+
+	var sb strings.Builder
+	for i, chain := range chains {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(fmt.Sprintf("[%s]", chain.chainName))
+
+		snapshot, found := hcw.GetCacheGovSnapshotRL(chain.chainName)
+		if !found {
+			sb.WriteString(" proposals not checked yet")
+			continue
+		}
+		if len(snapshot.Proposals) == 0 {
+			sb.WriteString(" No proposal on voting period")
+		}
+
+		for _, proposal := range snapshot.Proposals {
+			sb.WriteString(fmt.Sprintf("\n\n#%d", proposal.Id))
+			if proposal.Title != "" {
+				sb.WriteString(" ")
+				sb.WriteString(proposal.Title)
+			}
+			if proposal.VotingEndTime != nil {
+				sb.WriteString("\nVoting end: ")
+				sb.WriteString(updateCtx.formatTime(*proposal.VotingEndTime))
+				if timeLeft := time.Until(*proposal.VotingEndTime); timeLeft > 0 {
+					sb.WriteString(fmt.Sprintf(" (%s left)", timeLeft.Truncate(time.Minute)))
+				} else {
+					sb.WriteString(" (ended)")
+				}
+			}
+
+			for _, valoper := range chain.valopers {
+				sb.WriteString("\n- ")
+				if cache, found := hcw.GetCacheValidatorHealthCheckRL(valoper); found && cache.Moniker != "" {
+					sb.WriteString(cache.Moniker)
+				} else {
+					sb.WriteString(valoper)
+				}
+				sb.WriteString(": ")
+
+				vote, known := proposal.Votes[valoper]
+				if !known {
+					sb.WriteString("unknown")
+				} else if vote == "" {
+					sb.WriteString("NOT VOTED")
+				} else {
+					sb.WriteString(fmt.Sprintf("voted %s", vote))
+				}
+			}
+		}
+
+		sb.WriteString("\n\nUpdated: ")
+		sb.WriteString(updateCtx.formatTime(snapshot.UpdatedAt))
+	}
+
+	return e.sendLongResponse(updateCtx, sb.String())
+}
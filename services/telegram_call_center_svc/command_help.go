@@ -16,13 +16,30 @@ func (e *employee) processCommandHelp(updateCtx *telegramUpdateCtx) error {
 	sb.WriteString(fmt.Sprintf("\n/%s - Show validators you subscribed", constants.CommandValidators))
 	sb.WriteString(fmt.Sprintf("\n/%s <valoper> - Show last health-check statistic of a validator", constants.CommandLast))
 	if updateCtx.isRootUser {
-		sb.WriteString(fmt.Sprintf("\n/%s <chain or valoper> <duration> - Pause a chain or a validator", constants.CommandPause))
+		sb.WriteString(fmt.Sprintf("\n/%s <chain or valoper> [duration] [reason] - Pause a chain or a validator", constants.CommandPause))
+		sb.WriteString(fmt.Sprintf("\n/%s <chain or valoper> - Unpause a chain or a validator", constants.CommandUnpause))
 	} else {
-		sb.WriteString(fmt.Sprintf("\n/%s <valoper> <duration> - Pause a validator", constants.CommandPause))
+		sb.WriteString(fmt.Sprintf("\n/%s <valoper> [duration] [reason] - Pause a validator", constants.CommandPause))
+		sb.WriteString(fmt.Sprintf("\n/%s <valoper> - Unpause a validator", constants.CommandUnpause))
 	}
-	sb.WriteString(fmt.Sprintf("\n/%s - Show paused chains and validators", constants.CommandStatus))
+	if updateCtx.isRootUser {
+		sb.WriteString(fmt.Sprintf("\n/%s <chain or valoper> - Health-check now and reply the findings", constants.CommandCheck))
+	} else {
+		sb.WriteString(fmt.Sprintf("\n/%s <valoper> - Health-check now and reply the findings", constants.CommandCheck))
+	}
+	sb.WriteString(fmt.Sprintf("\n/%s [chain] - Show proposals on voting period and votes of your validators", constants.CommandGov))
+	sb.WriteString(fmt.Sprintf("\n/%s - Show paused chains and validators, who paused and why", constants.CommandStatus))
 	sb.WriteString(fmt.Sprintf("\n/%s - Search for a validator by part of it address", constants.CommandSearch))
-	// do not show /silent command
+	sb.WriteString(fmt.Sprintf("\n/%s <valoper> [chain] - Request to watch a validator, approved by root users", constants.CommandSubscribe))
+	sb.WriteString(fmt.Sprintf("\n/%s <valoper> - Stop watching a validator subscribed via /%s", constants.CommandUnsubscribe, constants.CommandSubscribe))
+	// do not show /silent and /unsilence commands
+	if updateCtx.isRootUser {
+		sb.WriteString(fmt.Sprintf("\n/%s [dead [n] | retry] - Show outbound messages, dead letters and delivery receipts", constants.CommandOutbox))
+		sb.WriteString(fmt.Sprintf("\n/%s <chain> - Show RPCs ranking and health of a chain", constants.CommandRpc))
+		sb.WriteString(fmt.Sprintf("\n/%s [n] - Show latest entries of the audit log of commands", constants.CommandAudit))
+		sb.WriteString(fmt.Sprintf("\n/%s [id] - List or approve pending subscription requests", constants.CommandApprove))
+		sb.WriteString(fmt.Sprintf("\n/%s <id> - Deny a pending subscription request", constants.CommandDeny))
+	}
 	sb.WriteString(fmt.Sprintf("\n/%s - Show this help message", constants.CommandHelp))
 
 	return e.sendResponse(updateCtx, sb.String())
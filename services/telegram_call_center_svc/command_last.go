@@ -27,6 +27,18 @@ func (e *employee) processCommandLast(updateCtx *telegramUpdateCtx) error {
 		return e.sendResponse(updateCtx, sb.String())
 	}
 
+	sb.WriteString(describeValidatorHealthCheck(updateCtx, cache))
+	sb.WriteString("\nLast updated: ")
+	sb.WriteString(updateCtx.formatTime(cache.TimeOccurs))
+	sb.WriteString(fmt.Sprintf(" (%.2f ago)", time.Since(cache.TimeOccurs).Seconds()))
+
+	return e.sendResponse(updateCtx, sb.String())
+}
+
+// describeValidatorHealthCheck describes the latest health-check result of the validator
+func describeValidatorHealthCheck(updateCtx *telegramUpdateCtx, cache hcw.CacheValidatorHealthCheck) string {
+	var sb strings.Builder
+
 	if cache.TomeStoned != nil && *cache.TomeStoned {
 		sb.WriteString("** TomeStoned **\n")
 	}
@@ -34,7 +46,7 @@ func (e *employee) processCommandLast(updateCtx *telegramUpdateCtx) error {
 		sb.WriteString("** Jailed **\n")
 		if cache.JailedUntil != nil {
 			sb.WriteString("(until:")
-			sb.WriteString(cache.JailedUntil.Format(time.DateTime))
+			sb.WriteString(updateCtx.formatTime(*cache.JailedUntil))
 			sb.WriteString(")\n")
 		}
 	}
@@ -66,9 +78,34 @@ func (e *employee) processCommandLast(updateCtx *telegramUpdateCtx) error {
 		}
 	}
 
-	sb.WriteString("\nLast updated: ")
-	sb.WriteString(cache.TimeOccurs.Format(time.DateTime))
-	sb.WriteString(fmt.Sprintf(" (%.2f ago)", time.Since(cache.TimeOccurs).Seconds()))
+	if cache.NodeLatestHeight != nil {
+		sb.WriteString("\nNode height: ")
+		sb.WriteString(fmt.Sprintf("%d", *cache.NodeLatestHeight))
+		if cache.NodeLagBlocks != nil && *cache.NodeLagBlocks > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d blocks behind)", *cache.NodeLagBlocks))
+		}
+		if cache.NodeCatchingUp != nil && *cache.NodeCatchingUp {
+			sb.WriteString(" (catching up)")
+		}
+	}
+	if cache.NodeInboundPeers != nil && cache.NodeOutboundPeers != nil {
+		sb.WriteString(fmt.Sprintf("\nNode peers: %d in, %d out", *cache.NodeInboundPeers, *cache.NodeOutboundPeers))
+	}
+	if cache.NodeVersion != "" || cache.NodeAppVersion != "" {
+		sb.WriteString(fmt.Sprintf("\nNode version: %s, app %s", cache.NodeVersion, cache.NodeAppVersion))
+	}
+	if cache.NodeError != "" {
+		sb.WriteString("\nNode error: ")
+		sb.WriteString(cache.NodeError)
+	}
 
-	return e.sendResponse(updateCtx, sb.String())
+	if snapshot, found := hcw.GetChainQuerySnapshotRL(cache.ChainName); found {
+		if stakingValidator, _, found := snapshot.GetValidator(cache.Valoper); found {
+			sb.WriteString(fmt.Sprintf("\nTokens: %s", stakingValidator.Tokens.String()))
+			sb.WriteString(fmt.Sprintf("\nCommission: %s%%", stakingValidator.Commission.Rate.MulInt64(100).String()))
+		}
+		sb.WriteString(fmt.Sprintf("\nChain data at block: %d", snapshot.Height))
+	}
+
+	return sb.String()
 }
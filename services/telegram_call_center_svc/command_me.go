@@ -2,6 +2,7 @@ package telegram_call_center_svc
 
 import (
 	"fmt"
+	usereg "github.com/bcdevtools/validator-health-check/registry/user_registry"
 	"strings"
 )
 
@@ -18,6 +19,12 @@ func (e *employee) processCommandMe(updateCtx *telegramUpdateCtx) error {
 	sb.WriteString("\n")
 	sb.WriteString("Chat ID: ")
 	sb.WriteString(fmt.Sprintf("%d", updateCtx.chatId()))
+	sb.WriteString("\nTimezone: ")
+	sb.WriteString(updateCtx.location.String())
+	if userRecord, found := usereg.GetUserRecordByIdentityRL(updateCtx.identity); found && len(userRecord.QuietHours) > 0 {
+		sb.WriteString("\nQuiet hours: ")
+		sb.WriteString(strings.Join(userRecord.QuietHours, ", "))
+	}
 
 	return e.sendResponse(updateCtx, sb.String())
 }
@@ -0,0 +1,106 @@
+package telegram_call_center_svc
+
+import (
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+	usereg "github.com/bcdevtools/validator-health-check/registry/user_registry"
+	tpsvc "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc"
+	"strconv"
+	"strings"
+)
+
+// processCommandOutbox processes command /outbox, root only.
+// Usage: /outbox, /outbox dead [n], /outbox retry
+func (e *employee) processCommandOutbox(updateCtx *telegramUpdateCtx) error {
+	if !updateCtx.isRootUser {
+		return e.sendRootOnlyResponse(updateCtx)
+	}
+
+	var sb strings.Builder
+
+	args := strings.Fields(updateCtx.commandArgs())
+	if len(args) == 0 {
+		pendingCount := tpsvc.GetPendingMessagesCountRL()
+		sb.WriteString("Pending messages:")
+		if len(pendingCount) == 0 {
+			sb.WriteString(" None")
+		}
+		for receiverId, count := range pendingCount {
+			sb.WriteString(fmt.Sprintf("\n- %s: %d", describeReceiver(receiverId), count))
+		}
+
+		sb.WriteString(fmt.Sprintf("\n\nDead letters: %d", len(tpsvc.GetDeadLettersRL())))
+
+		receipts := tpsvc.GetDeliveryReceiptsRL()
+		sb.WriteString("\n\nLatest deliveries:")
+		if len(receipts) == 0 {
+			sb.WriteString(" None")
+		}
+		for i, receipt := range receipts {
+			if i >= 5 {
+				break
+			}
+			sb.WriteString(fmt.Sprintf(
+				"\n- [%s] %d message(s) to %s, telegram message id %d",
+				updateCtx.formatTime(receipt.DeliveredAtUTC), len(receipt.MessageIDs), describeReceiver(receipt.ReceiverID), receipt.TelegramMessageID,
+			))
+		}
+
+		sb.WriteString(fmt.Sprintf("\n\nUsage: /%s dead [n] - show latest dead letters", constants.CommandOutbox))
+		sb.WriteString(fmt.Sprintf("\n/%s retry - re-enqueue all dead letters", constants.CommandOutbox))
+		return e.sendResponse(updateCtx, sb.String())
+	}
+
+	switch args[0] {
+	case "dead":
+		limit := 10
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return e.sendResponse(updateCtx, "Invalid number of dead letters!")
+			}
+			limit = n
+		}
+
+		deadLetters := tpsvc.GetDeadLettersRL()
+		if len(deadLetters) == 0 {
+			return e.sendResponse(updateCtx, "No dead letter")
+		}
+
+		sb.WriteString(fmt.Sprintf("Dead letters (%d):", len(deadLetters)))
+		for i, deadLetter := range deadLetters {
+			if i >= limit {
+				break
+			}
+
+			message := deadLetter.Message.Message
+			if len(message) > 200 {
+				message = message[:200] + "..."
+			}
+			sb.WriteString(fmt.Sprintf(
+				"\n\n- [%s] to %s, %d attempt(s), %s",
+				updateCtx.formatTime(deadLetter.DeadAtUTC), describeReceiver(deadLetter.Message.ReceiverID), deadLetter.Message.Attempts, deadLetter.Reason,
+			))
+			if deadLetter.Message.LastError != "" {
+				sb.WriteString(fmt.Sprintf("\nLast error: %s", deadLetter.Message.LastError))
+			}
+			sb.WriteString(fmt.Sprintf("\n%s", message))
+		}
+	case "retry":
+		count := tpsvc.RetryDeadLettersWL()
+		sb.WriteString(fmt.Sprintf("Re-enqueued %d dead letter(s)", count))
+	default:
+		sb.WriteString("Invalid arguments!")
+		sb.WriteString(fmt.Sprintf("\n\nUsage: /%s [dead [n] | retry]", constants.CommandOutbox))
+	}
+
+	return e.sendResponse(updateCtx, sb.String())
+}
+
+// describeReceiver returns the identity of the receiver if known, otherwise the Telegram user ID
+func describeReceiver(receiverId int64) string {
+	if userRecord, found := usereg.GetUserRecordByTelegramUserIdRL(receiverId); found {
+		return userRecord.Identity
+	}
+	return fmt.Sprintf("%d", receiverId)
+}
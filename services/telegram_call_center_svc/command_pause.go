@@ -4,15 +4,22 @@ import (
 	"fmt"
 	"github.com/bcdevtools/validator-health-check/constants"
 	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	usereg "github.com/bcdevtools/validator-health-check/registry/user_registry"
+	tpsvc "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
 	"strings"
 	"time"
+	"unicode"
 )
 
-// processCommandPause processes command /pause
+// maxPauseReasonLength is the maximum length of the reason provided when pausing or silencing
+const maxPauseReasonLength = 200
+
+// processCommandPause processes command /pause <target> [duration] [reason]
 func (e *employee) processCommandPause(updateCtx *telegramUpdateCtx) error {
 	var sb strings.Builder
 
-	args := updateCtx.commandArgs()
+	args := strings.Fields(updateCtx.commandArgs())
 	if len(args) == 0 {
 		if updateCtx.isRootUser {
 			sb.WriteString("Please provide a chain or a validator to pause!")
@@ -21,20 +28,20 @@ func (e *employee) processCommandPause(updateCtx *telegramUpdateCtx) error {
 			sb.WriteString("Please provide a validator to pause!")
 			sb.WriteString(fmt.Sprintf("\nSee the list at /%s", constants.CommandValidators))
 		}
+		sb.WriteString(fmt.Sprintf("\n\nUsage: /%s <target> [duration] [reason]", constants.CommandPause))
 		return e.sendResponse(updateCtx, sb.String())
 	}
 
-	spl := strings.Split(args, " ")
-	if len(spl) > 2 {
-		sb.WriteString("Invalid arguments!")
-		return e.sendResponse(updateCtx, sb.String())
-	}
+	target := args[0]
+	args = args[1:]
 
-	target := spl[0]
+	// the reason comes next to the duration, or alone when pausing without release date.
+	// The first argument starting with a digit is the duration, so a typo like 2hr is rejected instead of being the reason.
 	var duration *time.Duration
 	var ultimatePause bool
-	if len(spl) > 1 {
-		part := spl[1]
+	if len(args) > 0 && unicode.IsDigit(rune(args[0][0])) {
+		part := args[0]
+		args = args[1:]
 		switch part {
 		case "0", "0s":
 			duration = nil // unpause
@@ -60,18 +67,43 @@ func (e *employee) processCommandPause(updateCtx *telegramUpdateCtx) error {
 		ultimatePause = true
 	}
 
+	reason := strings.Join(args, " ")
+	if len(reason) > maxPauseReasonLength {
+		sb.WriteString(fmt.Sprintf("Reason must be at most %d characters!", maxPauseReasonLength))
+		return e.sendResponse(updateCtx, sb.String())
+	}
+
+	return e.processCommandPauseTarget(updateCtx, target, duration, ultimatePause, reason)
+}
+
+// processCommandUnpause processes command /unpause <target>
+func (e *employee) processCommandUnpause(updateCtx *telegramUpdateCtx) error {
+	args := strings.Fields(updateCtx.commandArgs())
+	if len(args) != 1 {
+		if updateCtx.isRootUser {
+			return e.sendResponse(updateCtx, fmt.Sprintf("Usage: /%s <chain or valoper>", constants.CommandUnpause))
+		}
+		return e.sendResponse(updateCtx, fmt.Sprintf("Usage: /%s <valoper>", constants.CommandUnpause))
+	}
+
+	return e.processCommandPauseTarget(updateCtx, args[0], nil, false, "")
+}
+
+// processCommandPauseTarget pauses the chain or the validator, unpause if duration is nil
+func (e *employee) processCommandPauseTarget(updateCtx *telegramUpdateCtx, target string, duration *time.Duration, ultimatePause bool, reason string) error {
 	if updateCtx.isRootUser && !strings.Contains(target, "valoper") {
-		found, err := e.processCommandPauseTryChainForRoot(updateCtx, target, duration, ultimatePause)
+		found, err := e.processCommandPauseTryChainForRoot(updateCtx, target, duration, ultimatePause, reason)
 		if found || err != nil {
 			return err
 		}
 	}
 
-	found, err := e.processCommandPauseTryValidator(updateCtx, target, duration, ultimatePause)
+	found, err := e.processCommandPauseTryValidator(updateCtx, target, duration, ultimatePause, reason)
 	if found || err != nil {
 		return err
 	}
 
+	var sb strings.Builder
 	if updateCtx.isRootUser {
 		sb.WriteString("No chain or validator found with the provided identifier!")
 		sb.WriteString(fmt.Sprintf("\nSee the list at /%s or /%s or use /%s", constants.CommandChains, constants.CommandValidators, constants.CommandSearch))
@@ -83,7 +115,7 @@ func (e *employee) processCommandPause(updateCtx *telegramUpdateCtx) error {
 	return e.sendResponse(updateCtx, sb.String())
 }
 
-func (e *employee) processCommandPauseTryChainForRoot(updateCtx *telegramUpdateCtx, chain string, duration *time.Duration, ultimatePause bool) (found bool, err error) {
+func (e *employee) processCommandPauseTryChainForRoot(updateCtx *telegramUpdateCtx, chain string, duration *time.Duration, ultimatePause bool, reason string) (found bool, err error) {
 	if !updateCtx.isRootUser {
 		panic("this method should only be called by root user")
 	}
@@ -93,36 +125,38 @@ func (e *employee) processCommandPauseTryChainForRoot(updateCtx *telegramUpdateC
 	}
 
 	if duration == nil {
-		chainreg.UnpauseChainWL(chain)
-		e.enqueueToAllRootUsers(
+		entry, wasPaused := chainreg.UnpauseChainWL(chain)
+		if !wasPaused {
+			return true, e.sendResponse(updateCtx, fmt.Sprintf("Chain [%s] was not paused", chain))
+		}
+		e.enqueueComposedToAllRootUsers(
 			updateCtx,
-			fmt.Sprintf("%s (%s) has unpaused chain [%s]", updateCtx.identity, updateCtx.username, chain),
-			false,
+			func(formatTime func(time.Time) string) string {
+				return fmt.Sprintf("%s (%s) has unpaused chain [%s], %s", updateCtx.identity, updateCtx.username, chain, describePauseEntry(entry, formatTime))
+			},
+			tptypes.SeverityInfo,
 		)
 		return true, e.sendResponse(updateCtx, fmt.Sprintf("Chain [%s] has unpaused", chain))
 	}
 
-	expiry := chainreg.PauseChainWL(chain, *duration)
-	e.enqueueToAllRootUsers(
+	entry := chainreg.PauseChainWL(chain, *duration, updateCtx.identity, reason)
+	e.enqueueComposedToAllRootUsers(
 		updateCtx,
-		fmt.Sprintf("%s (%s) has PAUSED chain [%s] %s", updateCtx.identity, updateCtx.username, chain, func() string {
-			if ultimatePause {
-				return "without release date"
-			} else {
-				return fmt.Sprintf("for %s, until %s", duration.String(), expiry.Format(time.DateTime))
-			}
-		}()),
-		true,
+		func(formatTime func(time.Time) string) string {
+			return fmt.Sprintf("%s (%s) has PAUSED chain [%s] %s%s", updateCtx.identity, updateCtx.username, chain, describePauseDuration(duration, entry.Expiry, ultimatePause, formatTime), describeReason(reason))
+		},
+		tptypes.SeverityWarning,
 	)
 	if ultimatePause {
 		return true, e.sendResponse(updateCtx, fmt.Sprintf("Chain [%s] has been PAUSED without release date", chain))
 	} else {
-		return true, e.sendResponse(updateCtx, fmt.Sprintf("Chain [%s] has been PAUSED for %s, until %s", chain, duration.String(), expiry.Format(time.DateTime)))
+		return true, e.sendResponse(updateCtx, fmt.Sprintf("Chain [%s] has been PAUSED for %s, until %s", chain, duration.String(), updateCtx.formatTime(entry.Expiry)))
 	}
 }
 
-func (e *employee) processCommandPauseTryValidator(updateCtx *telegramUpdateCtx, valoper string, duration *time.Duration, ultimatePause bool) (found bool, err error) {
+func (e *employee) processCommandPauseTryValidator(updateCtx *telegramUpdateCtx, valoper string, duration *time.Duration, ultimatePause bool, reason string) (found bool, err error) {
 	var chainName string
+	var watchersIdentity []string
 	var granted bool
 
 	granted = updateCtx.isRootUser
@@ -137,6 +171,7 @@ func (e *employee) processCommandPauseTryValidator(updateCtx *telegramUpdateCtx,
 
 			foundVal = true
 			chainName = chain.GetChainName()
+			watchersIdentity = val.WatchersIdentity
 
 			if granted {
 				break
@@ -159,31 +194,68 @@ func (e *employee) processCommandPauseTryValidator(updateCtx *telegramUpdateCtx,
 	}
 
 	if duration == nil {
-		chainreg.UnpauseValidatorWL(valoper)
-		e.enqueueToAllRootUsers(
-			updateCtx,
-			fmt.Sprintf("%s (%s) has unpaused validator [%s] on [%s]", updateCtx.identity, updateCtx.username, valoper, chainName),
-			false,
-		)
+		entry, wasPaused := chainreg.UnpauseValidatorWL(valoper)
+		if !wasPaused {
+			return true, e.sendResponse(updateCtx, fmt.Sprintf("Validator [%s] on [%s] was not paused", valoper, chainName))
+		}
+		composeMessage := func(formatTime func(time.Time) string) string {
+			return fmt.Sprintf("%s (%s) has unpaused validator [%s] on [%s], %s", updateCtx.identity, updateCtx.username, valoper, chainName, describePauseEntry(entry, formatTime))
+		}
+		e.enqueueComposedToAllRootUsers(updateCtx, composeMessage, tptypes.SeverityInfo)
+		e.enqueueToOtherWatchers(updateCtx, watchersIdentity, composeMessage, tptypes.SeverityInfo)
 		return true, e.sendResponse(updateCtx, fmt.Sprintf("Validator [%s] on [%s] has unpaused", valoper, chainName))
 	}
 
-	expiry := chainreg.PauseValidatorWL(valoper, *duration)
-	e.enqueueToAllRootUsers(
-		updateCtx,
-		fmt.Sprintf("%s (%s) has PAUSED validator [%s] on %s %s", updateCtx.identity, updateCtx.username, valoper, chainName, func() string {
-			if ultimatePause {
-				return "without release date"
-			} else {
-				return fmt.Sprintf("for %s, until %s", duration.String(), expiry.Format(time.DateTime))
-			}
-		}()),
-		true,
-	)
+	entry := chainreg.PauseValidatorWL(valoper, *duration, updateCtx.identity, reason)
+	composeMessage := func(formatTime func(time.Time) string) string {
+		return fmt.Sprintf("%s (%s) has PAUSED validator [%s] on %s %s%s", updateCtx.identity, updateCtx.username, valoper, chainName, describePauseDuration(duration, entry.Expiry, ultimatePause, formatTime), describeReason(reason))
+	}
+	e.enqueueComposedToAllRootUsers(updateCtx, composeMessage, tptypes.SeverityWarning)
+	e.enqueueToOtherWatchers(updateCtx, watchersIdentity, composeMessage, tptypes.SeverityWarning)
 
 	if ultimatePause {
 		return true, e.sendResponse(updateCtx, fmt.Sprintf("Validator [%s] on %s has been PAUSED without release date", valoper, chainName))
 	} else {
-		return true, e.sendResponse(updateCtx, fmt.Sprintf("Validator [%s] on %s has been PAUSED for %s, until %s", valoper, chainName, duration.String(), expiry.Format(time.DateTime)))
+		return true, e.sendResponse(updateCtx, fmt.Sprintf("Validator [%s] on %s has been PAUSED for %s, until %s", valoper, chainName, duration.String(), updateCtx.formatTime(entry.Expiry)))
+	}
+}
+
+// enqueueToOtherWatchers enqueues the message to the watchers of the validator,
+// except the user performing the command and root users which are notified separately.
+func (e *employee) enqueueToOtherWatchers(updateCtx *telegramUpdateCtx, watchersIdentity []string, composeMessage composeMessageFunc, severity tptypes.Severity) {
+	for _, watcherIdentity := range watchersIdentity {
+		if watcherIdentity == updateCtx.identity {
+			continue
+		}
+
+		userRecord, found := usereg.GetUserRecordByIdentityRL(watcherIdentity)
+		if !found || userRecord.Root || userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
+			continue
+		}
+
+		tpsvc.EnqueueMessageWL(tptypes.QueueMessage{
+			ReceiverID: userRecord.TelegramConfig.UserId,
+			Severity:   severity,
+			Message:    composeMessage(formatTimeInLocationOf(userRecord)),
+		})
+	}
+}
+
+func describePauseDuration(duration *time.Duration, expiry time.Time, ultimatePause bool, formatTime func(time.Time) string) string {
+	if ultimatePause {
+		return "without release date"
 	}
+	return fmt.Sprintf("for %s, until %s", duration.String(), formatTime(expiry))
+}
+
+func describeReason(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return fmt.Sprintf(", reason: %s", reason)
+}
+
+// describePauseEntry describes who paused, when and why
+func describePauseEntry(entry chainreg.PauseEntry, formatTime func(time.Time) string) string {
+	return fmt.Sprintf("paused by %s at %s%s", entry.PausedBy, formatTime(entry.CreatedAt), describeReason(entry.Reason))
 }
@@ -0,0 +1,92 @@
+package telegram_call_center_svc
+
+import (
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	rpcreg "github.com/bcdevtools/validator-health-check/registry/rpc_client_registry"
+	hcw "github.com/bcdevtools/validator-health-check/work/health_check_worker"
+	"strings"
+	"time"
+)
+
+// maxRpcLastErrorLength is the maximum length of the last error of an RPC to be shown
+const maxRpcLastErrorLength = 200
+
+// processCommandRpc processes command /rpc <chain>, root only because endpoints are exposed.
+// Shows the RPCs of the chain, ranked by the RPC pool, and the managed RPCs with their latest health-check result.
+func (e *employee) processCommandRpc(updateCtx *telegramUpdateCtx) error {
+	if !updateCtx.isRootUser {
+		return e.sendRootOnlyResponse(updateCtx)
+	}
+
+	args := strings.Fields(updateCtx.commandArgs())
+	if len(args) != 1 {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Usage: /%s <chain>", constants.CommandRpc))
+	}
+
+	chain, found := chainreg.GetChainConfigRL(args[0])
+	if !found {
+		return e.sendResponse(updateCtx, fmt.Sprintf("No chain found with the provided name!\nSee the list at /%s", constants.CommandChains))
+	}
+	chainName := chain.GetChainName()
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[%s] RPCs ranking:", chainName))
+
+	if rpcPool, found := rpcreg.GetRpcPoolByChainRL(chainName); !found {
+		sb.WriteString(" not health-checked yet")
+	} else {
+		for i, stats := range rpcPool.GetRankedEndpointsStats() {
+			sb.WriteString(fmt.Sprintf("\n\n%d. %s", i+1, stats.Endpoint))
+			if i == 0 && stats.IsPreferable() {
+				sb.WriteString(" (preferred)")
+			}
+			describeRpcStatus(&sb, updateCtx, stats.LatestBlockHeight, stats.LatestBlockTime, stats.LatencyEwma, stats.NetworkMismatch, stats.LastError, stats.LastErrorTime)
+			if stats.TotalRequests > 0 {
+				sb.WriteString(fmt.Sprintf("\nError rate: %.0f%%, failed %d/%d requests", stats.ErrorRate*100, stats.TotalFailures, stats.TotalRequests))
+			}
+			if stats.CircuitOpen {
+				sb.WriteString(fmt.Sprintf("\nCircuit breaker opened until %s", updateCtx.formatTime(stats.CircuitOpenUntil)))
+			}
+		}
+	}
+
+	if managedRPCs := chain.GetHealthCheckRPCs(); len(managedRPCs) > 0 {
+		sb.WriteString("\n\nManaged RPCs:")
+		for _, managedRPC := range managedRPCs {
+			sb.WriteString(fmt.Sprintf("\n\n- %s", managedRPC))
+			status, found := hcw.GetCacheManagedRpcStatusRL(chainName, managedRPC)
+			if !found {
+				sb.WriteString("\nNot health-checked yet")
+				continue
+			}
+			describeRpcStatus(&sb, updateCtx, status.LatestBlockHeight, status.LatestBlockTime, status.Latency, status.NetworkMismatch, status.LastError, status.LastErrorTime)
+			sb.WriteString(fmt.Sprintf("\nChecked: %s", updateCtx.formatTime(status.LastCheckTime)))
+		}
+	}
+
+	return e.sendLongResponse(updateCtx, sb.String())
+}
+
+// describeRpcStatus writes the latest block, latency, network id match and last error of an RPC
+func describeRpcStatus(sb *strings.Builder, updateCtx *telegramUpdateCtx, latestBlockHeight int64, latestBlockTime time.Time, latency time.Duration, networkMismatch bool, lastError string, lastErrorTime time.Time) {
+	if latestBlockHeight > 0 {
+		sb.WriteString(fmt.Sprintf("\nHeight: %d, block age: %s", latestBlockHeight, time.Since(latestBlockTime).Truncate(time.Second)))
+		sb.WriteString(fmt.Sprintf("\nLatency: %s", latency.Truncate(time.Millisecond)))
+		if networkMismatch {
+			sb.WriteString("\nNetwork: MISMATCH")
+		} else {
+			sb.WriteString("\nNetwork: matched")
+		}
+	} else {
+		sb.WriteString("\nHeight: unknown")
+	}
+
+	if lastError != "" {
+		if len(lastError) > maxRpcLastErrorLength {
+			lastError = lastError[:maxRpcLastErrorLength] + "..."
+		}
+		sb.WriteString(fmt.Sprintf("\nLast error at %s: %s", updateCtx.formatTime(lastErrorTime), lastError))
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"time"
 )
 
-// processCommandSilent processes command /silent
+// processCommandSilent processes command /silent <duration> <pattern> [| reason]
 func (e *employee) processCommandSilent(updateCtx *telegramUpdateCtx) error {
 	var sb strings.Builder
 
@@ -19,22 +19,29 @@ func (e *employee) processCommandSilent(updateCtx *telegramUpdateCtx) error {
 			sb.WriteString("(none)")
 		} else {
 			sb.WriteString("Current effective patterns:")
-			for pattern, expiry := range existingPatterns {
-				sb.WriteString(fmt.Sprintf("\n\n- [%s] %s", expiry.Format(time.DateTime), pattern))
+			for pattern, entry := range existingPatterns {
+				sb.WriteString(fmt.Sprintf("\n\n- [%s] %s", updateCtx.formatTime(entry.Expiry), pattern))
+				sb.WriteString(fmt.Sprintf("\nby %s at %s%s", entry.SilencedBy, updateCtx.formatTime(entry.CreatedAt), describeReason(entry.Reason)))
 			}
 		}
 	} else {
 		var duration *time.Duration
-		var pattern string
+		var pattern, reason string
 
 		spl := strings.SplitN(args, " ", 2)
 		if len(spl) != 2 {
 			sb.WriteString("Invalid arguments!")
-			sb.WriteString(fmt.Sprintf("\n\nUsage: /%s <duration> <pattern>", constants.CommandSilent))
+			sb.WriteString(fmt.Sprintf("\n\nUsage: /%s <duration> <pattern> [| reason]", constants.CommandSilent))
 			return e.sendResponse(updateCtx, sb.String())
 		}
 
-		pattern = strings.TrimSpace(spl[1])
+		pattern, reason, _ = strings.Cut(spl[1], "|")
+		pattern = strings.TrimSpace(pattern)
+		reason = strings.TrimSpace(reason)
+		if len(reason) > maxPauseReasonLength {
+			sb.WriteString(fmt.Sprintf("Reason must be at most %d characters!", maxPauseReasonLength))
+			return e.sendResponse(updateCtx, sb.String())
+		}
 
 		part := spl[0]
 		switch part {
@@ -54,25 +61,43 @@ func (e *employee) processCommandSilent(updateCtx *telegramUpdateCtx) error {
 		}
 
 		if duration == nil {
-			err := tpsvc.RemoveSilencePatternWL(updateCtx.chatId(), pattern)
-			if err != nil {
-				sb.WriteString("Failed to remove the silent pattern:")
-				sb.WriteString(fmt.Sprintf("\n\n%s", err.Error()))
-			} else {
-				sb.WriteString("Removed the silent pattern")
-			}
+			return e.removeSilencePattern(updateCtx, pattern)
+		}
+
+		updated, err := tpsvc.SetSilencePatternWL(updateCtx.chatId(), pattern, *duration, updateCtx.identity, reason)
+		if err != nil {
+			sb.WriteString("Failed to set the silent pattern:")
+			sb.WriteString(fmt.Sprintf("\n\n%s", err.Error()))
+		} else if updated {
+			sb.WriteString("Successfully updated expiration for the silent pattern")
 		} else {
-			updated, err := tpsvc.SetSilencePatternWL(updateCtx.chatId(), pattern, *duration)
-			if err != nil {
-				sb.WriteString("Failed to set the silent pattern:")
-				sb.WriteString(fmt.Sprintf("\n\n%s", err.Error()))
-			} else if updated {
-				sb.WriteString("Successfully updated expiration for the silent pattern")
-			} else {
-				sb.WriteString("Successfully set new silent pattern")
-			}
+			sb.WriteString("Successfully set new silent pattern")
 		}
 	}
 
 	return e.sendResponse(updateCtx, sb.String())
 }
+
+// processCommandUnsilence processes command /unsilence <pattern>
+func (e *employee) processCommandUnsilence(updateCtx *telegramUpdateCtx) error {
+	pattern := strings.TrimSpace(updateCtx.commandArgs())
+	if len(pattern) == 0 {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Usage: /%s <pattern>\nSee the current patterns at /%s", constants.CommandUnsilence, constants.CommandSilent))
+	}
+
+	return e.removeSilencePattern(updateCtx, pattern)
+}
+
+func (e *employee) removeSilencePattern(updateCtx *telegramUpdateCtx, pattern string) error {
+	var sb strings.Builder
+
+	err := tpsvc.RemoveSilencePatternWL(updateCtx.chatId(), pattern)
+	if err != nil {
+		sb.WriteString("Failed to remove the silent pattern:")
+		sb.WriteString(fmt.Sprintf("\n\n%s", err.Error()))
+	} else {
+		sb.WriteString("Removed the silent pattern")
+	}
+
+	return e.sendResponse(updateCtx, sb.String())
+}
@@ -3,26 +3,25 @@ package telegram_call_center_svc
 import (
 	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
 	"strings"
-	"time"
 )
 
 // processCommandStatus processes command /status
 func (e *employee) processCommandStatus(updateCtx *telegramUpdateCtx) error {
 	var sb strings.Builder
 
-	pausedChainsSubscribed := make(map[string]time.Time)
-	pausedChainsNotSubscribed := make(map[string]time.Time)
-	pausedValidatorsSubscribed := make(map[string]time.Time)
-	pausedValidatorsNotSubscribed := make(map[string]time.Time)
+	pausedChainsSubscribed := make(map[string]chainreg.PauseEntry)
+	pausedChainsNotSubscribed := make(map[string]chainreg.PauseEntry)
+	pausedValidatorsSubscribed := make(map[string]chainreg.PauseEntry)
+	pausedValidatorsNotSubscribed := make(map[string]chainreg.PauseEntry)
 
 	allChains := chainreg.GetCopyAllChainConfigsRL()
 	for _, chain := range allChains {
 		chainName := chain.GetChainName()
-		pausedChain, expiryC := chainreg.IsChainPausedRL(chainName)
+		entryC, pausedChain := chainreg.GetChainPauseEntryRL(chainName)
 		var subscribedChain bool
 
 		for _, val := range chain.GetValidators() {
-			pausedValidator, expiryV := chainreg.IsValidatorPausedRL(val.ValidatorOperatorAddress)
+			entryV, pausedValidator := chainreg.GetValidatorPauseEntryRL(val.ValidatorOperatorAddress)
 
 			var subscribedValidator bool
 			for _, watcherIdentity := range val.WatchersIdentity {
@@ -35,70 +34,52 @@ func (e *employee) processCommandStatus(updateCtx *telegramUpdateCtx) error {
 
 			if pausedValidator {
 				if subscribedValidator {
-					pausedValidatorsSubscribed[val.ValidatorOperatorAddress] = expiryV
+					pausedValidatorsSubscribed[val.ValidatorOperatorAddress] = entryV
 				} else {
-					pausedValidatorsNotSubscribed[val.ValidatorOperatorAddress] = expiryV
+					pausedValidatorsNotSubscribed[val.ValidatorOperatorAddress] = entryV
 				}
 			}
 		}
 
 		if pausedChain {
 			if subscribedChain {
-				pausedChainsSubscribed[chainName] = expiryC
+				pausedChainsSubscribed[chainName] = entryC
 			} else {
-				pausedChainsNotSubscribed[chainName] = expiryC
+				pausedChainsNotSubscribed[chainName] = entryC
 			}
 		}
 	}
 
-	sb.WriteString("Paused chains you subscribed:")
-	if len(pausedChainsSubscribed) == 0 {
-		sb.WriteString(" None")
-	} else {
-		for chainName, expiry := range pausedChainsSubscribed {
+	writePaused := func(paused map[string]chainreg.PauseEntry) {
+		if len(paused) == 0 {
+			sb.WriteString(" None")
+			return
+		}
+		for target, entry := range paused {
 			sb.WriteString("\n- ")
-			sb.WriteString(chainName)
+			sb.WriteString(target)
 			sb.WriteString(" until ")
-			sb.WriteString(expiry.Format(time.DateTime))
+			sb.WriteString(updateCtx.formatTime(entry.Expiry))
+			sb.WriteString("\n  by ")
+			sb.WriteString(entry.PausedBy)
+			sb.WriteString(" at ")
+			sb.WriteString(updateCtx.formatTime(entry.CreatedAt))
+			sb.WriteString(describeReason(entry.Reason))
 		}
 	}
 
+	sb.WriteString("Paused chains you subscribed:")
+	writePaused(pausedChainsSubscribed)
+
 	sb.WriteString("\n\nPaused validators you subscribed:")
-	if len(pausedValidatorsSubscribed) == 0 {
-		sb.WriteString(" None")
-	} else {
-		for valoper, expiry := range pausedValidatorsSubscribed {
-			sb.WriteString("\n- ")
-			sb.WriteString(valoper)
-			sb.WriteString(" until ")
-			sb.WriteString(expiry.Format(time.DateTime))
-		}
-	}
+	writePaused(pausedValidatorsSubscribed)
 
 	if updateCtx.isRootUser {
 		sb.WriteString("\n\n(Root) Paused chains you not subscribed:")
-		if len(pausedChainsNotSubscribed) == 0 {
-			sb.WriteString(" None")
-		} else {
-			for chainName, expiry := range pausedChainsNotSubscribed {
-				sb.WriteString("\n- ")
-				sb.WriteString(chainName)
-				sb.WriteString(" until ")
-				sb.WriteString(expiry.Format(time.DateTime))
-			}
-		}
+		writePaused(pausedChainsNotSubscribed)
 
 		sb.WriteString("\n\n(Root) Paused validators you not subscribed:")
-		if len(pausedValidatorsNotSubscribed) == 0 {
-			sb.WriteString(" None")
-		} else {
-			for valoper, expiry := range pausedValidatorsNotSubscribed {
-				sb.WriteString("\n- ")
-				sb.WriteString(valoper)
-				sb.WriteString(" until ")
-				sb.WriteString(expiry.Format(time.DateTime))
-			}
-		}
+		writePaused(pausedValidatorsNotSubscribed)
 	}
 
 	return e.sendResponse(updateCtx, sb.String())
@@ -0,0 +1,244 @@
+package telegram_call_center_svc
+
+import (
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	usereg "github.com/bcdevtools/validator-health-check/registry/user_registry"
+	tpsvc "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"github.com/bcdevtools/validator-health-check/utils"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// subscriptionsMutex serializes load-modify-save of the subscriptions file across employees
+var subscriptionsMutex sync.Mutex
+
+// processCommandSubscribe processes command /subscribe <valoper> [chain].
+// The request is queued for root approval, subscriptions requested by root users are approved immediately.
+func (e *employee) processCommandSubscribe(updateCtx *telegramUpdateCtx) error {
+	args := strings.Fields(updateCtx.commandArgs())
+	if len(args) < 1 || len(args) > 2 {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Usage: /%s <valoper> [chain]\nChain is required if the validator is not registered yet, see the list at /%s", constants.CommandSubscribe, constants.CommandChains))
+	}
+
+	valoper := args[0]
+	if !utils.IsValoperAddressFormat(valoper) {
+		return e.sendResponse(updateCtx, "Invalid validator operator address!")
+	}
+
+	var chainName string
+	if len(args) > 1 {
+		chainName = args[1]
+		if !chainreg.HasChainRL(chainName) {
+			return e.sendResponse(updateCtx, fmt.Sprintf("No chain found with the provided name!\nSee the list at /%s", constants.CommandChains))
+		}
+	}
+
+	for _, chain := range chainreg.GetCopyAllChainConfigsRL() {
+		if chainName != "" && chain.GetChainName() != chainName {
+			continue
+		}
+		for _, validator := range chain.GetValidators() {
+			if validator.ValidatorOperatorAddress != valoper {
+				continue
+			}
+			if utils.Contains(validator.WatchersIdentity, updateCtx.identity) {
+				return e.sendResponse(updateCtx, fmt.Sprintf("You are already watching validator [%s] on [%s]", valoper, chain.GetChainName()))
+			}
+			chainName = chain.GetChainName()
+		}
+	}
+
+	if chainName == "" {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Validator is not registered yet, please provide the chain: /%s %s <chain>\nSee the list at /%s", constants.CommandSubscribe, valoper, constants.CommandChains))
+	}
+
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	subscriptionsConfig, err := config.LoadSubscriptionsConfig(e.subscriptionsFile)
+	if err != nil {
+		_ = e.sendResponse(updateCtx, "Failed to load subscriptions, please try again later")
+		return err
+	}
+
+	if subscriptionsConfig.IsSubscribed(updateCtx.identity, chainName, valoper) {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Subscription to validator [%s] on [%s] has been approved, it takes effect at the next config reload", valoper, chainName))
+	}
+
+	request, duplicated := subscriptionsConfig.Request(updateCtx.identity, chainName, valoper, time.Now().UTC())
+	if duplicated {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Your request #%d to subscribe validator [%s] on [%s] is waiting for approval", request.Id, valoper, chainName))
+	}
+
+	if updateCtx.isRootUser {
+		subscriptionsConfig.Approve(request.Id, updateCtx.identity, time.Now().UTC())
+	}
+
+	if err := subscriptionsConfig.Save(e.subscriptionsFile); err != nil {
+		_ = e.sendResponse(updateCtx, "Failed to save subscriptions, please try again later")
+		return err
+	}
+
+	if updateCtx.isRootUser {
+		e.enqueueToAllRootUsers(
+			updateCtx,
+			fmt.Sprintf("%s (%s) has subscribed validator [%s] on [%s]", updateCtx.identity, updateCtx.username, valoper, chainName),
+			tptypes.SeverityInfo,
+		)
+		return e.sendResponse(updateCtx, fmt.Sprintf("Subscribed validator [%s] on [%s], it takes effect at the next config reload", valoper, chainName))
+	}
+
+	e.enqueueToAllRootUsers(
+		updateCtx,
+		fmt.Sprintf(
+			"%s (%s) requested to subscribe validator [%s] on [%s]\n/%s %d or /%s %d",
+			updateCtx.identity, updateCtx.username, valoper, chainName,
+			constants.CommandApprove, request.Id, constants.CommandDeny, request.Id,
+		),
+		tptypes.SeverityWarning,
+	)
+	return e.sendResponse(updateCtx, fmt.Sprintf("Your request #%d to subscribe validator [%s] on [%s] has been sent to root users for approval", request.Id, valoper, chainName))
+}
+
+// processCommandUnsubscribe processes command /unsubscribe <valoper>.
+// Only subscriptions made via Telegram can be removed, watchers declared in chain config files are managed by root users.
+func (e *employee) processCommandUnsubscribe(updateCtx *telegramUpdateCtx) error {
+	args := strings.Fields(updateCtx.commandArgs())
+	if len(args) != 1 {
+		return e.sendResponse(updateCtx, fmt.Sprintf("Usage: /%s <valoper>", constants.CommandUnsubscribe))
+	}
+	valoper := args[0]
+
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	subscriptionsConfig, err := config.LoadSubscriptionsConfig(e.subscriptionsFile)
+	if err != nil {
+		_ = e.sendResponse(updateCtx, "Failed to load subscriptions, please try again later")
+		return err
+	}
+
+	removed, cancelled := subscriptionsConfig.Unsubscribe(updateCtx.identity, valoper)
+	if removed == 0 && cancelled == 0 {
+		for _, chain := range chainreg.GetCopyAllChainConfigsRL() {
+			for _, validator := range chain.GetValidators() {
+				if validator.ValidatorOperatorAddress == valoper && utils.Contains(validator.WatchersIdentity, updateCtx.identity) {
+					return e.sendResponse(updateCtx, fmt.Sprintf("You are watching validator [%s] on [%s] via chain config file, please ask a root user to remove", valoper, chain.GetChainName()))
+				}
+			}
+		}
+		return e.sendResponse(updateCtx, fmt.Sprintf("You did not subscribe validator [%s]", valoper))
+	}
+
+	if err := subscriptionsConfig.Save(e.subscriptionsFile); err != nil {
+		_ = e.sendResponse(updateCtx, "Failed to save subscriptions, please try again later")
+		return err
+	}
+
+	if removed > 0 {
+		e.enqueueToAllRootUsers(
+			updateCtx,
+			fmt.Sprintf("%s (%s) has unsubscribed validator [%s]", updateCtx.identity, updateCtx.username, valoper),
+			tptypes.SeverityInfo,
+		)
+		return e.sendResponse(updateCtx, fmt.Sprintf("Unsubscribed validator [%s], it takes effect at the next config reload", valoper))
+	}
+
+	return e.sendResponse(updateCtx, fmt.Sprintf("Cancelled your pending request to subscribe validator [%s]", valoper))
+}
+
+// processCommandApprove processes command /approve [request id], root only.
+// Without request id, the pending requests are listed.
+func (e *employee) processCommandApprove(updateCtx *telegramUpdateCtx) error {
+	return e.processCommandReviewSubscription(updateCtx, true)
+}
+
+// processCommandDeny processes command /deny <request id>, root only
+func (e *employee) processCommandDeny(updateCtx *telegramUpdateCtx) error {
+	return e.processCommandReviewSubscription(updateCtx, false)
+}
+
+func (e *employee) processCommandReviewSubscription(updateCtx *telegramUpdateCtx, approve bool) error {
+	if !updateCtx.isRootUser {
+		return e.sendRootOnlyResponse(updateCtx)
+	}
+
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	subscriptionsConfig, err := config.LoadSubscriptionsConfig(e.subscriptionsFile)
+	if err != nil {
+		_ = e.sendResponse(updateCtx, "Failed to load subscriptions, please try again later")
+		return err
+	}
+
+	args := strings.Fields(updateCtx.commandArgs())
+	if len(args) == 0 {
+		var sb strings.Builder
+		sb.WriteString("Pending subscription requests:")
+		if len(subscriptionsConfig.Pending) == 0 {
+			sb.WriteString(" None")
+		}
+		for _, request := range subscriptionsConfig.Pending {
+			sb.WriteString(fmt.Sprintf(
+				"\n#%d %s requested validator [%s] on [%s] at %s",
+				request.Id, request.Identity, request.Valoper, request.Chain, updateCtx.formatTime(request.RequestedAt),
+			))
+		}
+		sb.WriteString(fmt.Sprintf("\n\nUsage: /%s <id> or /%s <id>", constants.CommandApprove, constants.CommandDeny))
+		return e.sendResponse(updateCtx, sb.String())
+	}
+
+	requestId, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return e.sendResponse(updateCtx, "Invalid request id!")
+	}
+
+	var request config.SubscriptionRequest
+	var found bool
+	if approve {
+		request, found = subscriptionsConfig.Approve(requestId, updateCtx.identity, time.Now().UTC())
+	} else {
+		request, found = subscriptionsConfig.Deny(requestId)
+	}
+	if !found {
+		return e.sendResponse(updateCtx, fmt.Sprintf("No pending request #%d, it may have been reviewed by another root user", requestId))
+	}
+
+	if err := subscriptionsConfig.Save(e.subscriptionsFile); err != nil {
+		_ = e.sendResponse(updateCtx, "Failed to save subscriptions, please try again later")
+		return err
+	}
+
+	action := "DENIED"
+	if approve {
+		action = "approved"
+	}
+
+	e.enqueueToAllRootUsers(
+		updateCtx,
+		fmt.Sprintf("%s (%s) has %s request #%d of %s to subscribe validator [%s] on [%s]", updateCtx.identity, updateCtx.username, action, request.Id, request.Identity, request.Valoper, request.Chain),
+		tptypes.SeverityInfo,
+	)
+
+	if requester, found := usereg.GetUserRecordByIdentityRL(request.Identity); found && !requester.TelegramConfig.IsEmptyOrIncompleteConfig() {
+		tpsvc.EnqueueMessageWL(tptypes.QueueMessage{
+			ReceiverID: requester.TelegramConfig.UserId,
+			Severity:   tptypes.SeverityInfo,
+			Message: fmt.Sprintf("Your request #%d to subscribe validator [%s] on [%s] has been %s%s", request.Id, request.Valoper, request.Chain, action, func() string {
+				if approve {
+					return ", it takes effect at the next config reload"
+				}
+				return ""
+			}()),
+		})
+	}
+
+	return e.sendResponse(updateCtx, fmt.Sprintf("Request #%d has been %s", request.Id, action))
+}
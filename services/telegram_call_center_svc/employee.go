@@ -7,24 +7,31 @@ import (
 	"github.com/bcdevtools/validator-health-check/constants"
 	tbotreg "github.com/bcdevtools/validator-health-check/registry/telegram_bot_registry"
 	usereg "github.com/bcdevtools/validator-health-check/registry/user_registry"
+	lesvc "github.com/bcdevtools/validator-health-check/services/leader_election_svc"
 	tcctypes "github.com/bcdevtools/validator-health-check/services/telegram_call_center_svc/types"
 	tpsvc "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc"
 	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"github.com/bcdevtools/validator-health-check/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/pkg/errors"
 	"time"
 )
 
 type employee struct {
-	appCtx      config.AppContext
-	telegramBot tbotreg.TelegramBot
-	rateLimiter tcctypes.RateLimiter
+	appCtx            config.AppContext
+	telegramBot       tbotreg.TelegramBot
+	rateLimiter       tcctypes.RateLimiter
+	auditLog          tcctypes.AuditLog
+	subscriptionsFile string
 }
 
-func newEmployee(appCtx config.AppContext, newBot tbotreg.TelegramBot, rateLimiter tcctypes.RateLimiter) *employee {
+func newEmployee(appCtx config.AppContext, newBot tbotreg.TelegramBot, rateLimiter tcctypes.RateLimiter, auditLog tcctypes.AuditLog, subscriptionsFile string) *employee {
 	return &employee{
-		appCtx:      appCtx,
-		telegramBot: newBot,
-		rateLimiter: rateLimiter,
+		appCtx:            appCtx,
+		telegramBot:       newBot,
+		rateLimiter:       rateLimiter,
+		auditLog:          auditLog,
+		subscriptionsFile: subscriptionsFile,
 	}
 }
 
@@ -34,37 +41,96 @@ func (e *employee) start() {
 
 	employeeID := e.telegramBot.BotID()
 
-	logger.Info("starting new call center employee", "id", employeeID)
+	for {
+		// only the leader receives updates, Telegram does not allow multiple instances polling updates of the same bot
+		lesvc.WaitUntilLeader()
 
-	for update := range e.telegramBot.GetUpdatesChannel() {
-		if update.Message == nil { // ignore any non-Message updates
-			continue
-		}
+		logger.Info("call center employee starts receiving updates", "id", employeeID)
 
-		if !update.Message.IsCommand() { // ignore any non-command Messages
-			continue
+		if !e.receiveUpdatesWhileLeader() {
+			logger.Info("call center employee stopped receiving updates", "id", employeeID)
+			return
 		}
 
-		logger.Info("new telegram command", "employee", employeeID, "from", update.Message.From.ID, "msg", update.Message.Text)
-		updateCtx := newTelegramUpdateCtx(update)
-		err := e.processUpdate(updateCtx)
-		if err != nil {
-			logger.Error("error occurs during employee processing update", "error", err.Error(), "employee", employeeID, "from", update.Message.From.ID)
+		logger.Info("call center employee suspended receiving updates due to lost leadership", "id", employeeID)
+	}
+}
+
+// receiveUpdatesWhileLeader processes the updates until losing leadership or receiving updates is stopped.
+// Returns true if suspended due to lost leadership, false if stopped.
+func (e *employee) receiveUpdatesWhileLeader() (suspended bool) {
+	updates := e.telegramBot.GetUpdatesChannel()
+
+	lostLeadership := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if !lesvc.IsLeaderRL() {
+					close(lostLeadership)
+					e.telegramBot.SuspendReceivingUpdates() // the updates channel will be closed
+					return
+				}
+			}
 		}
+	}()
+
+	// the updates received are processed even tho leadership was lost,
+	// they were taken by this instance so will not be delivered to the new leader
+	for update := range updates {
+		e.handleUpdate(update)
+	}
+
+	select {
+	case <-lostLeadership:
+		return true
+	default:
+		return false
 	}
 }
 
+func (e *employee) handleUpdate(update tgbotapi.Update) {
+	logger := e.appCtx.Logger
+	employeeID := e.telegramBot.BotID()
+
+	if update.Message == nil { // ignore any non-Message updates
+		return
+	}
+
+	if !update.Message.IsCommand() { // ignore any non-command Messages
+		return
+	}
+
+	logger.Info("new telegram command", "employee", employeeID, "from", update.Message.From.ID, "msg", update.Message.Text)
+	updateCtx := newTelegramUpdateCtx(update)
+	err := e.processUpdate(updateCtx)
+	if err != nil {
+		logger.Error("error occurs during employee processing update", "error", err.Error(), "employee", employeeID, "from", update.Message.From.ID)
+	}
+	e.audit(updateCtx, err)
+}
+
 func (e *employee) processUpdate(updateCtx *telegramUpdateCtx) error {
 	userRecord, found := usereg.GetUserRecordByTelegramUserIdRL(updateCtx.userId())
 	if !found || userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
+		updateCtx.auditOutcome = tcctypes.AuditOutcomeDeniedUnknownUser
 		e.sendResponse(updateCtx, fmt.Sprintf("Hey %d, you are not allowed to use this bot", updateCtx.userId()))
 		return fmt.Errorf("forbidden access, user-id: %d", updateCtx.userId())
 	}
 	updateCtx.identity = userRecord.Identity
 	updateCtx.username = userRecord.TelegramConfig.Username
 	updateCtx.isRootUser = userRecord.Root
+	updateCtx.location = userRecord.GetLocation()
 
 	if !e.rateLimiter.Request(fmt.Sprintf("%d", updateCtx.userId()), 3*time.Second) {
+		updateCtx.auditOutcome = tcctypes.AuditOutcomeDeniedRateLimited
 		return e.sendResponse(updateCtx, "Rate limit exceeded, please try again later")
 	}
 
@@ -77,6 +143,8 @@ func (e *employee) processUpdate(updateCtx *telegramUpdateCtx) error {
 		return e.processCommandValidators(updateCtx)
 	case constants.CommandPause:
 		return e.processCommandPause(updateCtx)
+	case constants.CommandUnpause:
+		return e.processCommandUnpause(updateCtx)
 	case constants.CommandStatus:
 		return e.processCommandStatus(updateCtx)
 	case constants.CommandLast:
@@ -85,6 +153,26 @@ func (e *employee) processUpdate(updateCtx *telegramUpdateCtx) error {
 		return e.processCommandSearch(updateCtx)
 	case constants.CommandSilent:
 		return e.processCommandSilent(updateCtx)
+	case constants.CommandUnsilence:
+		return e.processCommandUnsilence(updateCtx)
+	case constants.CommandOutbox:
+		return e.processCommandOutbox(updateCtx)
+	case constants.CommandGov:
+		return e.processCommandGov(updateCtx)
+	case constants.CommandRpc:
+		return e.processCommandRpc(updateCtx)
+	case constants.CommandCheck:
+		return e.processCommandCheck(updateCtx)
+	case constants.CommandSubscribe:
+		return e.processCommandSubscribe(updateCtx)
+	case constants.CommandUnsubscribe:
+		return e.processCommandUnsubscribe(updateCtx)
+	case constants.CommandApprove:
+		return e.processCommandApprove(updateCtx)
+	case constants.CommandDeny:
+		return e.processCommandDeny(updateCtx)
+	case constants.CommandAudit:
+		return e.processCommandAudit(updateCtx)
 	case constants.CommandHelp:
 		return e.processCommandHelp(updateCtx)
 	default:
@@ -93,11 +181,34 @@ func (e *employee) processUpdate(updateCtx *telegramUpdateCtx) error {
 }
 
 func (e *employee) sendResponse(updateCtx *telegramUpdateCtx, msg string) error {
-	_, err := e.telegramBot.GetInnerTelegramBot().SendMessage(msg, updateCtx.chatId())
+	if updateCtx.firstResponse == "" {
+		updateCtx.firstResponse = msg
+	}
+	_, err := tpsvc.SendMessage(e.telegramBot, updateCtx.chatId(), msg)
 	return errors.Wrap(err, "failed to send response")
 }
 
-func (e *employee) enqueueToAllRootUsers(_ *telegramUpdateCtx, msg string, fatal bool) {
+// sendLongResponse sends the response in multiple messages if exceeding the Telegram message length limit
+func (e *employee) sendLongResponse(updateCtx *telegramUpdateCtx, msg string) error {
+	for _, chunk := range utils.SplitTextByLines(msg, constants.TELEGRAM_MESSAGE_MAX_LENGTH) {
+		if err := e.sendResponse(updateCtx, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// composeMessageFunc composes the message for a receiver, times are formatted in time zone of the receiver by formatTime
+type composeMessageFunc func(formatTime func(time.Time) string) string
+
+func (e *employee) enqueueToAllRootUsers(updateCtx *telegramUpdateCtx, msg string, severity tptypes.Severity) {
+	e.enqueueComposedToAllRootUsers(updateCtx, func(func(time.Time) string) string {
+		return msg
+	}, severity)
+}
+
+// enqueueComposedToAllRootUsers is the same as enqueueToAllRootUsers, but the message is composed per root user
+func (e *employee) enqueueComposedToAllRootUsers(_ *telegramUpdateCtx, composeMessage composeMessageFunc, severity tptypes.Severity) {
 	for _, userRecord := range usereg.GetRootUsersIdentityRL() {
 		userRecord, found := usereg.GetUserRecordByIdentityRL(userRecord)
 		if !found || userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
@@ -107,8 +218,44 @@ func (e *employee) enqueueToAllRootUsers(_ *telegramUpdateCtx, msg string, fatal
 		tpsvc.EnqueueMessageWL(tptypes.QueueMessage{
 			ReceiverID: userRecord.TelegramConfig.UserId,
 			Priority:   true,
-			Fatal:      fatal,
-			Message:    msg,
+			Severity:   severity,
+			Message:    composeMessage(formatTimeInLocationOf(userRecord)),
 		})
 	}
 }
+
+// formatTimeInLocationOf returns the function formatting time in time zone of the user
+func formatTimeInLocationOf(userRecord config.UserRecord) func(time.Time) string {
+	location := userRecord.GetLocation()
+	return func(t time.Time) string {
+		return formatTimeIn(t, location)
+	}
+}
+
+// sendRootOnlyResponse denies the command of non-root user
+func (e *employee) sendRootOnlyResponse(updateCtx *telegramUpdateCtx) error {
+	updateCtx.auditOutcome = tcctypes.AuditOutcomeDeniedRootOnly
+	return e.sendResponse(updateCtx, "This command is only available for root users")
+}
+
+// audit records the command and its outcome into the audit log
+func (e *employee) audit(updateCtx *telegramUpdateCtx, errProcess error) {
+	entry := tcctypes.AuditEntry{
+		Time:     time.Now().UTC(),
+		UserId:   updateCtx.userId(),
+		Username: updateCtx.update.Message.From.UserName,
+		Identity: updateCtx.identity,
+		ChatId:   updateCtx.chatId(),
+		Command:  updateCtx.command(),
+		Args:     summarizeAuditText(updateCtx.commandArgs(), maxAuditArgsLength),
+		Outcome:  updateCtx.auditOutcome,
+		Response: summarizeAuditText(updateCtx.firstResponse, maxAuditResponseLength),
+	}
+	if errProcess != nil {
+		entry.Error = errProcess.Error()
+	}
+
+	if err := e.auditLog.Append(entry); err != nil {
+		e.appCtx.Logger.Error("failed to append audit log", "error", err.Error(), "from", entry.UserId, "command", entry.Command)
+	}
+}
@@ -13,14 +13,19 @@ type telegramCallCenter struct {
 	sync.Mutex
 	appCtx        config.AppContext
 	rateLimiter   tcctypes.RateLimiter
+	auditLog      tcctypes.AuditLog
 	uniqueTracker map[string]bool
+
+	subscriptionsFile string // managed overlay of watchers, maintained via /subscribe and /unsubscribe commands
 }
 
-func StartTelegramCallCenterService(appCtx config.AppContext) {
+func StartTelegramCallCenterService(appCtx config.AppContext, auditLog tcctypes.AuditLog, subscriptionsFile string) {
 	callCenter := &telegramCallCenter{
-		appCtx:        appCtx,
-		rateLimiter:   tcctypes.NewRateLimiter(),
-		uniqueTracker: make(map[string]bool),
+		appCtx:            appCtx,
+		rateLimiter:       tcctypes.NewRateLimiter(),
+		auditLog:          auditLog,
+		uniqueTracker:     make(map[string]bool),
+		subscriptionsFile: subscriptionsFile,
 	}
 	go callCenter.start(appCtx)
 }
@@ -48,7 +53,7 @@ func (cc *telegramCallCenter) start(appCtx config.AppContext) {
 			continue
 		}
 
-		employee := newEmployee(appCtx, newBot, cc.rateLimiter)
+		employee := newEmployee(appCtx, newBot, cc.rateLimiter, cc.auditLog, cc.subscriptionsFile)
 		go employee.start()
 	}
 }
@@ -1,17 +1,26 @@
 package telegram_call_center_svc
 
-import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+import (
+	tcctypes "github.com/bcdevtools/validator-health-check/services/telegram_call_center_svc/types"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"time"
+)
 
 type telegramUpdateCtx struct {
 	update     tgbotapi.Update
 	identity   string
 	username   string
 	isRootUser bool
+	location   *time.Location // time zone of the user
+
+	auditOutcome  string // recorded into the audit log
+	firstResponse string // recorded into the audit log, summarized
 }
 
 func newTelegramUpdateCtx(update tgbotapi.Update) *telegramUpdateCtx {
 	return &telegramUpdateCtx{
-		update: update,
+		update:       update,
+		auditOutcome: tcctypes.AuditOutcomeAllowed,
 	}
 }
 
@@ -30,3 +39,16 @@ func (c *telegramUpdateCtx) command() string {
 func (c *telegramUpdateCtx) commandArgs() string {
 	return c.update.Message.CommandArguments()
 }
+
+// formatTime formats the time in time zone of the user
+func (c *telegramUpdateCtx) formatTime(t time.Time) string {
+	return formatTimeIn(t, c.location)
+}
+
+// formatTimeIn formats the time in the given time zone, UTC if nil
+func formatTimeIn(t time.Time, location *time.Location) string {
+	if location == nil {
+		location = time.UTC
+	}
+	return t.In(location).Format(time.DateTime + " MST")
+}
@@ -0,0 +1,174 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+	"github.com/pkg/errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// outcomes of the commands recorded into the audit log
+const (
+	AuditOutcomeAllowed           = "allowed"
+	AuditOutcomeDeniedUnknownUser = "denied-unknown-user"
+	AuditOutcomeDeniedRateLimited = "denied-rate-limited"
+	AuditOutcomeDeniedRootOnly    = "denied-root-only"
+)
+
+// AuditEntry is the record of a Telegram command received by the call center
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	UserId   int64     `json:"user-id"`
+	Username string    `json:"username,omitempty"` // Telegram username, as provided by Telegram
+	Identity string    `json:"identity,omitempty"` // empty if the user is unknown
+	ChatId   int64     `json:"chat-id"`
+	Command  string    `json:"command"`
+	Args     string    `json:"args,omitempty"`
+	Outcome  string    `json:"outcome"`
+	Response string    `json:"response,omitempty"` // summary of the response
+	Error    string    `json:"error,omitempty"`
+}
+
+// AuditLog is the append-only log of the Telegram commands received
+type AuditLog interface {
+	Append(entry AuditEntry) error
+	// Recent returns the latest entries, ordered from the oldest to the newest
+	Recent(n int) ([]AuditEntry, error)
+}
+
+var _ AuditLog = &fileAuditLog{}
+
+// fileAuditLog stores the entries as JSON lines, the file is rotated when reaching the maximum size
+type fileAuditLog struct {
+	sync.Mutex
+
+	file       string
+	maxSize    int64
+	maxBackups int
+}
+
+// NewFileAuditLog creates audit log backed by the given file,
+// rotated files are suffixed by .1 (the newest) to .<max backups> (the oldest).
+func NewFileAuditLog(file string, maxSize int64, maxBackups int) AuditLog {
+	return &fileAuditLog{
+		file:       file,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+}
+
+func (l *fileAuditLog) Append(entry AuditEntry) error {
+	bz, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode audit entry")
+	}
+	bz = append(bz, '\n')
+
+	l.Lock()
+	defer l.Unlock()
+
+	if err := l.rotateIfNeeded(int64(len(bz))); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.FILE_PERMISSION)
+	if err != nil {
+		return errors.Wrap(err, "failed to open audit log file")
+	}
+
+	_, err = file.Write(bz)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	return errors.Wrap(err, "failed to write audit log file")
+}
+
+// rotateIfNeeded rotates the audit log file if appending the given number of bytes exceeds the maximum size
+func (l *fileAuditLog) rotateIfNeeded(appendSize int64) error {
+	fileInfo, err := os.Stat(l.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to stat audit log file")
+	}
+
+	if fileInfo.Size() == 0 || fileInfo.Size()+appendSize <= l.maxSize {
+		return nil
+	}
+
+	if l.maxBackups < 1 {
+		return errors.Wrap(os.Remove(l.file), "failed to remove audit log file")
+	}
+
+	for i := l.maxBackups; i > 0; i-- {
+		src := l.fileName(i - 1)
+		if _, err := os.Stat(src); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return errors.Wrap(err, "failed to stat audit log file")
+		}
+
+		if err := os.Rename(src, l.fileName(i)); err != nil {
+			return errors.Wrap(err, "failed to rotate audit log file")
+		}
+	}
+
+	return nil
+}
+
+func (l *fileAuditLog) Recent(n int) ([]AuditEntry, error) {
+	if n < 1 {
+		return nil, nil
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	var entries []AuditEntry
+	for i := 0; i <= l.maxBackups && len(entries) < n; i++ {
+		bz, err := os.ReadFile(l.fileName(i))
+		if err != nil {
+			if os.IsNotExist(err) {
+				break
+			}
+			return nil, errors.Wrap(err, "failed to read audit log file")
+		}
+
+		var fileEntries []AuditEntry
+		scanner := bufio.NewScanner(bytes.NewReader(bz))
+		scanner.Buffer(make([]byte, 0, 64*1024), int(l.maxSize)+1)
+		for scanner.Scan() {
+			var entry AuditEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				continue // partially written line
+			}
+			fileEntries = append(fileEntries, entry)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, errors.Wrap(err, "failed to scan audit log file")
+		}
+
+		entries = append(fileEntries, entries...)
+	}
+
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+
+	return entries, nil
+}
+
+// fileName returns name of the audit log file, index 0 is the current file, other indexes are the rotated files
+func (l *fileAuditLog) fileName(index int) string {
+	if index == 0 {
+		return l.file
+	}
+	return fmt.Sprintf("%s.%d", l.file, index)
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileAuditLog(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit.log")
+	newEntry := func(i int) AuditEntry {
+		return AuditEntry{
+			Time:    time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC),
+			UserId:  int64(i),
+			ChatId:  int64(i),
+			Command: "status",
+			Args:    fmt.Sprintf("arg-%d", i),
+			Outcome: AuditOutcomeAllowed,
+		}
+	}
+
+	auditLog := NewFileAuditLog(file, 250, 2) // roughly 2 entries per file
+
+	entries, err := auditLog.Recent(10)
+	require.NoError(t, err)
+	require.Empty(t, entries, "no file yet")
+
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, auditLog.Append(newEntry(i)))
+	}
+
+	fileInfo, err := os.Stat(file)
+	require.NoError(t, err)
+	require.LessOrEqual(t, fileInfo.Size(), int64(250))
+	require.Equal(t, os.FileMode(0o600), fileInfo.Mode().Perm())
+	require.FileExists(t, file+".1")
+	require.FileExists(t, file+".2")
+	require.NoFileExists(t, file+".3", "oldest rotated file must be removed")
+
+	entries, err = auditLog.Recent(3)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, newEntry(8), entries[0])
+	require.Equal(t, newEntry(10), entries[2], "ordered from the oldest to the newest")
+
+	entries, err = auditLog.Recent(100)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	require.Less(t, len(entries), 10, "entries of the removed files are lost")
+	require.Equal(t, newEntry(10), entries[len(entries)-1])
+
+	// partially written line is skipped
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"time":"2024-`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	entries, err = auditLog.Recent(1)
+	require.NoError(t, err)
+	require.Equal(t, newEntry(10), entries[0])
+}
@@ -0,0 +1,310 @@
+package telegram_push_message_svc
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	libapp "github.com/EscanBE/go-lib/app"
+	"github.com/bcdevtools/validator-health-check/constants"
+	"github.com/bcdevtools/validator-health-check/registry/user_registry"
+	lesvc "github.com/bcdevtools/validator-health-check/services/leader_election_svc"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"sort"
+	"sync"
+	"time"
+)
+
+// outboxPersistInterval is the interval to persist the outbox if changed
+const outboxPersistInterval = 1 * time.Second
+
+var outboxPersistMutex sync.Mutex
+
+// FlushOutboxWL persists the outbox immediately, to be called on shutdown
+func FlushOutboxWL() {
+	if telePusherSvc == nil {
+		return
+	}
+
+	telePusherSvc.persistOutbox()
+}
+
+// GetDeadLettersRL returns the dead letters, latest first
+func GetDeadLettersRL() []tptypes.DeadLetter {
+	telePusherSvc.RLock()
+	defer telePusherSvc.RUnlock()
+
+	deadLetters := make([]tptypes.DeadLetter, len(telePusherSvc.deadLetters))
+	for i, deadLetter := range telePusherSvc.deadLetters {
+		deadLetters[len(deadLetters)-1-i] = deadLetter
+	}
+	return deadLetters
+}
+
+// RetryDeadLettersWL moves all dead letters back to the queues with attempts reset, returns the number of messages re-enqueued
+func RetryDeadLettersWL() int {
+	tp := telePusherSvc
+
+	tp.Lock()
+	deadLetters := tp.deadLetters
+	tp.deadLetters = nil
+	tp.Unlock()
+
+	nowUTC := time.Now().UTC()
+	for _, deadLetter := range deadLetters {
+		message := deadLetter.Message
+		message.Attempts = 0
+		message.LastError = ""
+		message.EnqueueTimeUTC = nowUTC // restart max age
+		tp.enqueueMessageWL(message)
+	}
+
+	return len(deadLetters)
+}
+
+// GetDeliveryReceiptsRL returns the receipts of the latest deliveries, latest first
+func GetDeliveryReceiptsRL() []tptypes.DeliveryReceipt {
+	telePusherSvc.RLock()
+	defer telePusherSvc.RUnlock()
+
+	receipts := make([]tptypes.DeliveryReceipt, len(telePusherSvc.receipts))
+	for i, receipt := range telePusherSvc.receipts {
+		receipts[len(receipts)-1-i] = receipt
+	}
+	return receipts
+}
+
+// GetPendingMessagesCountRL returns the number of pending messages per receiver, including messages being delivered
+func GetPendingMessagesCountRL() map[int64]int {
+	telePusherSvc.RLock()
+	defer telePusherSvc.RUnlock()
+
+	pendingCount := make(map[int64]int)
+	for _, message := range telePusherSvc.getPendingMessagesRL() {
+		pendingCount[message.ReceiverID]++
+	}
+	return pendingCount
+}
+
+// restoreOutboxWL loads the persisted outbox into the queues, once, when this instance is the leader
+func (tp *telegramPusher) restoreOutboxWL() {
+	tp.RLock()
+	restored := tp.outboxRestored
+	tp.RUnlock()
+
+	if restored {
+		return
+	}
+
+	logger := tp.appCtx.Logger
+
+	var snapshot tptypes.OutboxSnapshot
+	if tp.outboxStore != nil {
+		var err error
+		snapshot, err = tp.outboxStore.Load()
+		if err != nil {
+			logger.Error("failed to restore outbox, starting with empty outbox", "error", err.Error())
+		}
+	}
+
+	tp.Lock()
+	tp.outboxRestored = true
+	tp.deadLetters = append(snapshot.DeadLetters, tp.deadLetters...)
+	tp.receipts = append(snapshot.Receipts, tp.receipts...)
+	tp.Unlock()
+
+	sort.SliceStable(snapshot.Pending, func(i, j int) bool {
+		return snapshot.Pending[i].EnqueueTimeUTC.Before(snapshot.Pending[j].EnqueueTimeUTC)
+	})
+	for _, message := range snapshot.Pending {
+		tp.enqueueMessageWL(message)
+	}
+
+	if len(snapshot.Pending) > 0 || len(snapshot.DeadLetters) > 0 {
+		logger.Info("restored outbox", "pending", len(snapshot.Pending), "dead-letters", len(snapshot.DeadLetters))
+	}
+}
+
+// dropOutboxWL drops the in-memory outbox when losing leadership, the new leader delivers the messages persisted.
+// The outbox is restored from the store again when re-elected.
+func (tp *telegramPusher) dropOutboxWL() {
+	tp.Lock()
+	defer tp.Unlock()
+
+	if !tp.outboxRestored {
+		return
+	}
+
+	tp.outboxRestored = false
+	tp.outboxDirty = false
+	tp.queuesReceiverBased = make(map[int64]ReceiverBasedQueue)
+	tp.priorityQueue = nil
+	tp.nonPriorityQueue = nil
+	tp.heldForQuietHours = make(map[int64]bool)
+	tp.inFlight = make(map[int64][]tptypes.QueueMessage)
+	tp.deadLetters = nil
+	tp.receipts = nil
+
+	tp.appCtx.Logger.Info("lost leadership, dropped outbox")
+}
+
+// routinePersistOutbox persists the outbox periodically if changed
+func (tp *telegramPusher) routinePersistOutbox() {
+	defer libapp.TryRecoverAndExecuteExitFunctionIfRecovered(tp.appCtx.Logger)
+
+	if tp.outboxStore == nil {
+		return
+	}
+
+	for {
+		time.Sleep(outboxPersistInterval)
+
+		tp.RLock()
+		dirty := tp.outboxDirty
+		tp.RUnlock()
+
+		if dirty {
+			tp.persistOutbox()
+		}
+	}
+}
+
+// persistOutbox saves the outbox via the outbox store.
+// Only the leader persists, standby must not overwrite the outbox shared with the leader.
+// The outbox dropped when losing leadership is not persisted until restored again, see dropOutboxWL.
+func (tp *telegramPusher) persistOutbox() {
+	if tp.outboxStore == nil || !lesvc.IsLeaderRL() {
+		return
+	}
+
+	outboxPersistMutex.Lock()
+	defer outboxPersistMutex.Unlock()
+
+	tp.Lock()
+	if !tp.outboxRestored {
+		// do not overwrite the persisted outbox before restoring it
+		tp.Unlock()
+		return
+	}
+	snapshot := tptypes.OutboxSnapshot{
+		Pending:     tp.getPendingMessagesRL(),
+		DeadLetters: append([]tptypes.DeadLetter{}, tp.deadLetters...),
+		Receipts:    append([]tptypes.DeliveryReceipt{}, tp.receipts...),
+	}
+	tp.outboxDirty = false
+	tp.Unlock()
+
+	if err := tp.outboxStore.Save(snapshot); err != nil {
+		tp.appCtx.Logger.Error("failed to persist outbox", "error", err.Error())
+		tp.markOutboxDirtyWL()
+	}
+}
+
+// getPendingMessagesRL returns the enqueued messages and the messages being delivered, caller must hold the lock
+func (tp *telegramPusher) getPendingMessagesRL() []tptypes.QueueMessage {
+	var pending []tptypes.QueueMessage
+	for _, queue := range tp.queuesReceiverBased {
+		pending = append(pending, queue.GetAllMessagesRL()...)
+	}
+	for _, messages := range tp.inFlight {
+		pending = append(pending, messages...)
+	}
+	return pending
+}
+
+func (tp *telegramPusher) markOutboxDirtyWL() {
+	tp.Lock()
+	defer tp.Unlock()
+
+	tp.outboxDirty = true
+}
+
+func (tp *telegramPusher) setInFlightWL(receiverId int64, messages []tptypes.QueueMessage) {
+	tp.Lock()
+	defer tp.Unlock()
+
+	if len(messages) == 0 {
+		delete(tp.inFlight, receiverId)
+	} else {
+		tp.inFlight[receiverId] = messages
+	}
+	tp.outboxDirty = true
+}
+
+func (tp *telegramPusher) addDeliveryReceiptWL(receiverId int64, messages []tptypes.QueueMessage, telegramMessageId int) {
+	messageIds := make([]string, len(messages))
+	for i, message := range messages {
+		messageIds[i] = message.ID
+	}
+
+	tp.Lock()
+	defer tp.Unlock()
+
+	tp.receipts = append(tp.receipts, tptypes.DeliveryReceipt{
+		MessageIDs:        messageIds,
+		ReceiverID:        receiverId,
+		TelegramMessageID: telegramMessageId,
+		DeliveredAtUTC:    time.Now().UTC(),
+	})
+	if len(tp.receipts) > constants.OUTBOX_MAX_RECEIPTS {
+		tp.receipts = tp.receipts[len(tp.receipts)-constants.OUTBOX_MAX_RECEIPTS:]
+	}
+	tp.outboxDirty = true
+}
+
+// moveToDeadLetterWL moves the messages to dead-letter and informs root users
+func (tp *telegramPusher) moveToDeadLetterWL(messages []tptypes.QueueMessage, reason string) {
+	if len(messages) == 0 {
+		return
+	}
+
+	receiverId := messages[0].ReceiverID
+	tp.appCtx.Logger.Error("moved messages to dead-letter", "receiver", receiverId, "count", len(messages), "reason", reason)
+
+	func() {
+		tp.Lock()
+		defer tp.Unlock()
+
+		nowUTC := time.Now().UTC()
+		for _, message := range messages {
+			tp.deadLetters = append(tp.deadLetters, tptypes.DeadLetter{
+				Message:   message,
+				Reason:    reason,
+				DeadAtUTC: nowUTC,
+			})
+		}
+		if len(tp.deadLetters) > constants.OUTBOX_MAX_DEAD_LETTERS {
+			tp.deadLetters = tp.deadLetters[len(tp.deadLetters)-constants.OUTBOX_MAX_DEAD_LETTERS:]
+		}
+		tp.outboxDirty = true
+	}()
+
+	receiver := fmt.Sprintf("%d", receiverId)
+	if userRecord, found := user_registry.GetUserRecordByTelegramUserIdRL(receiverId); found {
+		receiver = userRecord.Identity
+	}
+
+	for _, identity := range user_registry.GetRootUsersIdentityRL() {
+		userRecord, found := user_registry.GetUserRecordByIdentityRL(identity)
+		if !found || userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() || userRecord.TelegramConfig.UserId == receiverId {
+			continue
+		}
+
+		tp.enqueueMessageWL(tptypes.QueueMessage{
+			ReceiverID: userRecord.TelegramConfig.UserId,
+			Priority:   true,
+			Severity:   tptypes.SeverityWarning,
+			Message:    fmt.Sprintf("[outbox] %d message(s) to %s moved to dead-letter: %s. Use /%s to inspect", len(messages), receiver, reason, constants.CommandOutbox),
+		})
+	}
+}
+
+// getOutboxRetryBackoff returns the duration to defer delivery after the given number of failed attempts, doubled each attempt
+func getOutboxRetryBackoff(attempts int) time.Duration {
+	backoff := constants.MINIMUM_BETWEEN_TELEGRAM_PUSH_SAME_USER
+	for i := 1; i < attempts && backoff < constants.OUTBOX_MAX_RETRY_BACKOFF; i++ {
+		backoff *= 2
+	}
+	if backoff > constants.OUTBOX_MAX_RETRY_BACKOFF {
+		backoff = constants.OUTBOX_MAX_RETRY_BACKOFF
+	}
+	return backoff
+}
@@ -0,0 +1,78 @@
+package telegram_push_message_svc
+
+import (
+	"encoding/json"
+	"github.com/bcdevtools/validator-health-check/constants"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"github.com/pkg/errors"
+	"os"
+	"path"
+)
+
+// OutboxStore persists the outbound messages, so messages queued during a Telegram outage survive restart
+type OutboxStore interface {
+	Load() (tptypes.OutboxSnapshot, error)
+	Save(snapshot tptypes.OutboxSnapshot) error
+}
+
+var _ OutboxStore = &fileOutboxStore{}
+
+// fileOutboxStore stores the outbox as JSON file, replaced atomically on each save
+type fileOutboxStore struct {
+	file string
+}
+
+// NewFileOutboxStore creates outbox store backed by the given file
+func NewFileOutboxStore(file string) OutboxStore {
+	return &fileOutboxStore{
+		file: file,
+	}
+}
+
+func (s *fileOutboxStore) Load() (tptypes.OutboxSnapshot, error) {
+	bz, err := os.ReadFile(s.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tptypes.OutboxSnapshot{}, nil
+		}
+		return tptypes.OutboxSnapshot{}, errors.Wrap(err, "failed to read outbox file")
+	}
+
+	var snapshot tptypes.OutboxSnapshot
+	if err := json.Unmarshal(bz, &snapshot); err != nil {
+		return tptypes.OutboxSnapshot{}, errors.Wrap(err, "failed to decode outbox file")
+	}
+	return snapshot, nil
+}
+
+func (s *fileOutboxStore) Save(snapshot tptypes.OutboxSnapshot) error {
+	bz, err := json.Marshal(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode outbox")
+	}
+
+	tmpFile, err := os.CreateTemp(path.Dir(s.file), path.Base(s.file)+".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp outbox file")
+	}
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	_, err = tmpFile.Write(bz)
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	if errClose := tmpFile.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to write temp outbox file")
+	}
+
+	if err := os.Chmod(tmpFile.Name(), constants.FILE_PERMISSION); err != nil {
+		return errors.Wrap(err, "failed to set permission of temp outbox file")
+	}
+
+	return errors.Wrap(os.Rename(tmpFile.Name(), s.file), "failed to replace outbox file")
+}
@@ -0,0 +1,110 @@
+package telegram_push_message_svc
+
+import (
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"github.com/stretchr/testify/require"
+	"path"
+	"testing"
+	"time"
+)
+
+func Test_outbox_persistAndRestore(t *testing.T) {
+	store := NewFileOutboxStore(path.Join(t.TempDir(), constants.OUTBOX_FILE_NAME))
+	appCtx := config.AppContext{Logger: logging.NewDefaultLogger()}
+
+	tp := newTelegramPusher(appCtx, store)
+	tp.restoreOutboxWL()
+
+	tp.enqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "first"})
+	tp.enqueueMessageWL(tptypes.QueueMessage{ReceiverID: 2, Message: "second", Priority: true})
+	tp.setInFlightWL(3, []tptypes.QueueMessage{{ID: "in-flight", ReceiverID: 3, Message: "being delivered", Attempts: 2}})
+	tp.addDeliveryReceiptWL(4, []tptypes.QueueMessage{{ID: "delivered"}}, 123)
+	tp.moveToDeadLetterWL([]tptypes.QueueMessage{{ID: "dead", ReceiverID: 5, Message: "dead"}}, "test")
+	require.True(t, tp.outboxDirty)
+
+	tp.persistOutbox()
+	require.False(t, tp.outboxDirty)
+
+	// restart
+	restarted := newTelegramPusher(appCtx, store)
+	restarted.restoreOutboxWL()
+
+	pending := make(map[string]tptypes.QueueMessage)
+	for _, message := range restarted.getPendingMessagesRL() {
+		require.NotEmpty(t, message.ID, "message ID should be assigned on enqueue")
+		pending[message.Message] = message
+	}
+	require.Len(t, pending, 3, "enqueued and in-flight messages should be restored")
+	require.Equal(t, 2, pending["being delivered"].Attempts, "attempts should be kept")
+	require.True(t, pending["second"].Priority)
+
+	require.Len(t, restarted.priorityQueue, 1)
+	require.Len(t, restarted.nonPriorityQueue, 2)
+
+	require.Len(t, restarted.receipts, 1)
+	require.Equal(t, 123, restarted.receipts[0].TelegramMessageID)
+	require.Equal(t, []string{"delivered"}, restarted.receipts[0].MessageIDs)
+
+	require.Len(t, restarted.deadLetters, 1)
+	require.Equal(t, "dead", restarted.deadLetters[0].Message.ID)
+	require.Equal(t, "test", restarted.deadLetters[0].Reason)
+}
+
+func Test_outbox_notPersistedBeforeRestored(t *testing.T) {
+	store := NewFileOutboxStore(path.Join(t.TempDir(), constants.OUTBOX_FILE_NAME))
+	require.NoError(t, store.Save(tptypes.OutboxSnapshot{
+		Pending: []tptypes.QueueMessage{{ID: "persisted", ReceiverID: 1, Message: "persisted"}},
+	}))
+
+	tp := newTelegramPusher(config.AppContext{Logger: logging.NewDefaultLogger()}, store)
+	tp.persistOutbox()
+
+	snapshot, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, snapshot.Pending, 1, "persisted outbox should not be overwritten before restored")
+}
+
+func Test_outbox_dropOnLosingLeadership(t *testing.T) {
+	store := NewFileOutboxStore(path.Join(t.TempDir(), constants.OUTBOX_FILE_NAME))
+	tp := newTelegramPusher(config.AppContext{Logger: logging.NewDefaultLogger()}, store)
+	tp.restoreOutboxWL()
+
+	tp.enqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "before step-down"})
+	tp.persistOutbox()
+
+	// lost leadership, the new leader delivered the message and persisted its outbox
+	tp.dropOutboxWL()
+	require.False(t, tp.outboxRestored)
+	require.Empty(t, tp.getPendingMessagesRL())
+	require.Empty(t, tp.priorityQueue)
+	require.Empty(t, tp.nonPriorityQueue)
+
+	require.NoError(t, store.Save(tptypes.OutboxSnapshot{
+		Pending: []tptypes.QueueMessage{{ID: "new-leader", ReceiverID: 2, Message: "enqueued by new leader"}},
+	}))
+
+	// delivery completed after step-down must not overwrite the outbox of the new leader
+	tp.setInFlightWL(1, nil)
+	tp.persistOutbox()
+	snapshot, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, snapshot.Pending, 1)
+	require.Equal(t, "new-leader", snapshot.Pending[0].ID)
+
+	// re-elected, the outbox is restored from the store
+	tp.restoreOutboxWL()
+	pending := tp.getPendingMessagesRL()
+	require.Len(t, pending, 1)
+	require.Equal(t, "new-leader", pending[0].ID)
+}
+
+func Test_getOutboxRetryBackoff(t *testing.T) {
+	require.Equal(t, constants.MINIMUM_BETWEEN_TELEGRAM_PUSH_SAME_USER, getOutboxRetryBackoff(1))
+	require.Equal(t, 2*constants.MINIMUM_BETWEEN_TELEGRAM_PUSH_SAME_USER, getOutboxRetryBackoff(2))
+	require.Equal(t, 4*constants.MINIMUM_BETWEEN_TELEGRAM_PUSH_SAME_USER, getOutboxRetryBackoff(3))
+	require.Equal(t, constants.OUTBOX_MAX_RETRY_BACKOFF, getOutboxRetryBackoff(constants.OUTBOX_MAX_ATTEMPTS))
+	require.LessOrEqual(t, getOutboxRetryBackoff(100), time.Hour)
+}
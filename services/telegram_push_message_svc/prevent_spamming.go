@@ -1,6 +1,7 @@
 package telegram_push_message_svc
 
 import (
+	lesvc "github.com/bcdevtools/validator-health-check/services/leader_election_svc"
 	"sync"
 	"time"
 )
@@ -15,12 +16,29 @@ const (
 	PreventSpammingCaseDirectHealthCheckOptionalRPC
 	PreventSpammingCaseHealthCheckManagedRPC
 	PreventSpammingCaseNotVotedGovernance
+	PreventSpammingCaseHealthCheckManagedEvmRPC
+	PreventSpammingCaseHealthCheckManagedGrpc
+	PreventSpammingCaseHealthCheckManagedRest
+	PreventSpammingCaseDirectHealthCheckLowPeers
+	PreventSpammingCaseDirectHealthCheckVersionDrift
+	PreventSpammingCaseManagedEndpointTLS
 )
 
 var mutexRwPreventSpamming sync.RWMutex
 var globalPreventSpamming map[PreventSpammingCase]map[string]time.Time
 
 func ShouldSendMessageWL(_case PreventSpammingCase, identities []string, ignoreIfLastSentLessThan time.Duration) (shouldSendToIdentities []string) {
+	return ShouldSendMessageForSubjectWL(_case, "", identities, ignoreIfLastSentLessThan)
+}
+
+// ShouldSendMessageForSubjectWL is the same as ShouldSendMessageWL, but tracked separately per subject of the case,
+// e.g. alerts of each managed endpoint or reminders of each proposal not voted.
+// Nothing is sent nor recorded on standby instance, so the new leader alerts right after taking over.
+func ShouldSendMessageForSubjectWL(_case PreventSpammingCase, subject string, identities []string, ignoreIfLastSentLessThan time.Duration) (shouldSendToIdentities []string) {
+	if !lesvc.IsLeaderRL() {
+		return nil
+	}
+
 	mutexRwPreventSpamming.Lock()
 	defer mutexRwPreventSpamming.Unlock()
 
@@ -31,12 +49,17 @@ func ShouldSendMessageWL(_case PreventSpammingCase, identities []string, ignoreI
 	}
 
 	for _, identity := range identities {
-		lastSent, found := perCaseRegistry[identity]
+		key := identity
+		if subject != "" {
+			key = identity + "/" + subject
+		}
+
+		lastSent, found := perCaseRegistry[key]
 		if found && time.Since(lastSent) < ignoreIfLastSentLessThan {
 			// ignore
 		} else {
 			shouldSendToIdentities = append(shouldSendToIdentities, identity)
-			perCaseRegistry[identity] = time.Now().UTC()
+			perCaseRegistry[key] = time.Now().UTC()
 		}
 	}
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/bcdevtools/validator-health-check/constants"
 	"github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"sort"
 	"sync"
 	"time"
 )
@@ -12,10 +13,18 @@ type ReceiverBasedQueue interface {
 	EnqueueMessageWL(types.QueueMessage)
 	AnyPendingMessageRL() bool
 	GetQueueInfoRL() (receiver int64, isReceiverPriority bool, size int, lastEnqueueUTC time.Time)
+	GetHighestSeverityRL() types.Severity
 	DequeueMessagesWL(size int) []types.QueueMessage
+	DequeueMessagesOfSeverityWL(size int, minSeverity types.Severity, lengthOf MessagesLengthFunc) []types.QueueMessage
 	GetReceiverId() int64
+	GetAllMessagesRL() []types.QueueMessage
+	DeferWL(until time.Time)
+	GetDeferredUntilRL() time.Time
 }
 
+// MessagesLengthFunc returns the length of the messages once combined into a single Telegram message
+type MessagesLengthFunc func(messages []types.QueueMessage) int
+
 var _ ReceiverBasedQueue = &receiverBasedQueue{}
 
 type receiverBasedQueue struct {
@@ -23,6 +32,7 @@ type receiverBasedQueue struct {
 	receiverId       int64
 	isPriority       bool
 	lastEnqueueUTC   time.Time
+	deferredUntil    time.Time // delivery is deferred until this time, after failed attempt
 	enqueuedMessages []types.QueueMessage
 }
 
@@ -64,51 +74,98 @@ func (r *receiverBasedQueue) GetQueueInfoRL() (receiver int64, isReceiverPriorit
 	return r.receiverId, r.isPriority, len(r.enqueuedMessages), r.lastEnqueueUTC
 }
 
-func (r *receiverBasedQueue) DequeueMessagesWL(size int) (result []types.QueueMessage) {
+// GetHighestSeverityRL returns the highest severity among the enqueued messages
+func (r *receiverBasedQueue) GetHighestSeverityRL() types.Severity {
+	r.RLock()
+	defer r.RUnlock()
+
+	var highestSeverity types.Severity
+	for _, message := range r.enqueuedMessages {
+		if message.Severity > highestSeverity {
+			highestSeverity = message.Severity
+		}
+	}
+	return highestSeverity
+}
+
+// DequeueMessagesWL dequeues messages, the most severe messages first
+func (r *receiverBasedQueue) DequeueMessagesWL(size int) []types.QueueMessage {
+	return r.DequeueMessagesOfSeverityWL(size, types.SeverityInfo, nil)
+}
+
+// DequeueMessagesOfSeverityWL dequeues messages having severity at least the given one, the most severe messages first.
+// The dequeued messages are limited so that the combined message, measured by lengthOf, fits into a Telegram message.
+// The first message is always dequeued, even if it is too long by itself, so the queue is not blocked.
+// If lengthOf is nil, the messages are measured as joined by the batch divider.
+func (r *receiverBasedQueue) DequeueMessagesOfSeverityWL(size int, minSeverity types.Severity, lengthOf MessagesLengthFunc) []types.QueueMessage {
 	r.Lock()
 	defer r.Unlock()
 
-	const maximumTelegramMessageLength = 4096
+	if lengthOf == nil {
+		lengthOf = joinedMessagesLength
+	}
+
+	sort.SliceStable(r.enqueuedMessages, func(i, j int) bool {
+		return r.enqueuedMessages[i].Severity > r.enqueuedMessages[j].Severity
+	})
 
-	if size >= len(r.enqueuedMessages) {
-		// take all
-		result = r.enqueuedMessages[:]
-		r.enqueuedMessages = make([]types.QueueMessage, 0)
-	} else {
-		result = r.enqueuedMessages[:size]
-		r.enqueuedMessages = r.enqueuedMessages[size:]
+	// sorted, messages of the given severity are at the head of the queue
+	var countQualified int
+	for countQualified < len(r.enqueuedMessages) && r.enqueuedMessages[countQualified].Severity >= minSeverity {
+		countQualified++
+	}
+	if size > countQualified {
+		size = countQualified
 	}
 
-	defer func() {
-		r.lastEnqueueUTC = time.Now().UTC() // prevent multi push that can reach telegram API limit
-	}()
+	var count int
+	for count < size && (count == 0 || lengthOf(r.enqueuedMessages[:count+1]) <= constants.TELEGRAM_MESSAGE_MAX_LENGTH) {
+		count++
+	}
+
+	result := append([]types.QueueMessage{}, r.enqueuedMessages[:count]...)
+	r.enqueuedMessages = append(make([]types.QueueMessage, 0, len(r.enqueuedMessages)-count), r.enqueuedMessages[count:]...)
+
+	r.lastEnqueueUTC = time.Now().UTC() // prevent multi push that can reach telegram API limit
+
+	return result
+}
 
-	var resultWithCheckLimitMessageSize []types.QueueMessage
-	var cumulativeMessageLength int
-	for i, message := range result {
-		newCumulativeMessageLength := cumulativeMessageLength + len(message.Message)
+// joinedMessagesLength returns the length of the messages joined by the batch divider
+func joinedMessagesLength(messages []types.QueueMessage) int {
+	var length int
+	for i, message := range messages {
 		if i > 0 {
-			newCumulativeMessageLength += len(constants.BATCH_MESSAGES_LINE_DIVIDER)
+			length += len(constants.BATCH_MESSAGES_LINE_DIVIDER)
 		}
+		length += len(message.Message)
+	}
+	return length
+}
 
-		if newCumulativeMessageLength >= maximumTelegramMessageLength {
-			break // do not enqueue
-		}
+func (r *receiverBasedQueue) GetReceiverId() int64 {
+	return r.receiverId
+}
 
-		cumulativeMessageLength = newCumulativeMessageLength
-		resultWithCheckLimitMessageSize = append(resultWithCheckLimitMessageSize, message)
-	}
+// GetAllMessagesRL returns a copy of all enqueued messages
+func (r *receiverBasedQueue) GetAllMessagesRL() []types.QueueMessage {
+	r.RLock()
+	defer r.RUnlock()
 
-	if len(resultWithCheckLimitMessageSize) < len(result) {
-		// threshold reached
+	return append([]types.QueueMessage{}, r.enqueuedMessages...)
+}
 
-		// put the rest back to queue
-		r.enqueuedMessages = append(result[len(resultWithCheckLimitMessageSize):], r.enqueuedMessages...)
-	}
+// DeferWL defers delivery of the queue until the given time
+func (r *receiverBasedQueue) DeferWL(until time.Time) {
+	r.Lock()
+	defer r.Unlock()
 
-	return
+	r.deferredUntil = until
 }
 
-func (r *receiverBasedQueue) GetReceiverId() int64 {
-	return r.receiverId
+func (r *receiverBasedQueue) GetDeferredUntilRL() time.Time {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.deferredUntil
 }
@@ -9,9 +9,17 @@ import (
 )
 
 var mutexSilencer sync.RWMutex
-var silencePatternByChatID = make(map[int64]map[string]time.Time)
+var silencePatternByChatID = make(map[int64]map[string]SilenceEntry)
+
+// SilenceEntry records who silenced a pattern, why and until when
+type SilenceEntry struct {
+	Expiry     time.Time
+	SilencedBy string // identity of the user
+	Reason     string // optional
+	CreatedAt  time.Time
+}
 
-func SetSilencePatternWL(chatID int64, pattern string, duration time.Duration) (update bool, err error) {
+func SetSilencePatternWL(chatID int64, pattern string, duration time.Duration, silencedBy, reason string) (update bool, err error) {
 	pattern = strings.TrimSpace(pattern)
 	if len(pattern) < constants.SILENT_PATTERN_MINIMUM_LENGTH {
 		return false, fmt.Errorf(
@@ -29,8 +37,8 @@ func SetSilencePatternWL(chatID int64, pattern string, duration time.Duration) (
 	silencePatternsOfChatID, existsChatId := silencePatternByChatID[chatID]
 	if existsChatId {
 		// remove expired patterns
-		for existingPattern, expiry := range silencePatternsOfChatID {
-			if expiry.Before(nowUTC) {
+		for existingPattern, entry := range silencePatternsOfChatID {
+			if entry.Expiry.Before(nowUTC) {
 				delete(silencePatternsOfChatID, existingPattern)
 			}
 		}
@@ -41,14 +49,19 @@ func SetSilencePatternWL(chatID int64, pattern string, duration time.Duration) (
 			return false, fmt.Errorf("maximum %d patterns are allowed per chat, please wait expiry first", maximumPatternsAllowedPerChatID)
 		}
 	} else {
-		silencePatternsOfChatID = make(map[string]time.Time)
+		silencePatternsOfChatID = make(map[string]SilenceEntry)
 		silencePatternByChatID[chatID] = silencePatternsOfChatID
 	}
 
-	currentExpiry, exists := silencePatternsOfChatID[pattern]
-	exists = exists && currentExpiry.After(nowUTC)
+	currentEntry, exists := silencePatternsOfChatID[pattern]
+	exists = exists && currentEntry.Expiry.After(nowUTC)
 
-	silencePatternsOfChatID[pattern] = nowUTC.Add(duration)
+	silencePatternsOfChatID[pattern] = SilenceEntry{
+		Expiry:     nowUTC.Add(duration),
+		SilencedBy: silencedBy,
+		Reason:     reason,
+		CreatedAt:  nowUTC,
+	}
 	return exists, nil
 }
 
@@ -74,8 +87,8 @@ func RemoveSilencePatternWL(chatID int64, pattern string) error {
 	return nil
 }
 
-func GetSilentPatternsByChatIdRL(chatID int64) map[string]time.Time {
-	copied := make(map[string]time.Time)
+func GetSilentPatternsByChatIdRL(chatID int64) map[string]SilenceEntry {
+	copied := make(map[string]SilenceEntry)
 
 	mutexSilencer.RLock()
 	defer mutexSilencer.RUnlock()
@@ -83,15 +96,15 @@ func GetSilentPatternsByChatIdRL(chatID int64) map[string]time.Time {
 	silencePatternsOfChatID, existsChatId := silencePatternByChatID[chatID]
 	if existsChatId {
 		nowUTC := time.Now().UTC()
-		for pattern, expiry := range silencePatternsOfChatID {
-			if expiry.Before(nowUTC) {
+		for pattern, entry := range silencePatternsOfChatID {
+			if entry.Expiry.Before(nowUTC) {
 				continue
 			}
-			copied[pattern] = expiry
+			copied[pattern] = entry
 		}
 	}
 
-	return silencePatternsOfChatID
+	return copied
 }
 
 func shouldSilentByChatIdRWL(chatID int64, message string) bool {
@@ -104,8 +117,8 @@ func shouldSilentByChatIdRWL(chatID int64, message string) bool {
 			defer mutexSilencer.Unlock()
 
 			silencePatternsOfChatID := silencePatternByChatID[chatID]
-			for pattern, expiry := range silencePatternsOfChatID {
-				if expiry.Before(nowUTC) {
+			for pattern, entry := range silencePatternsOfChatID {
+				if entry.Expiry.Before(nowUTC) {
 					delete(silencePatternsOfChatID, pattern)
 				}
 			}
@@ -124,8 +137,8 @@ func shouldSilentByChatIdRWL(chatID int64, message string) bool {
 		return false
 	}
 
-	for pattern, expiry := range patterns {
-		if expiry.Before(nowUTC) {
+	for pattern, entry := range patterns {
+		if entry.Expiry.Before(nowUTC) {
 			needCleanup = true
 			continue
 		}
@@ -0,0 +1,36 @@
+package telegram_push_message_svc
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSetSilencePatternWL(t *testing.T) {
+	const chatID int64 = 8026
+
+	updated, err := SetSilencePatternWL(chatID, "block outdated", time.Hour, "alice", "node upgrade")
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	patterns := GetSilentPatternsByChatIdRL(chatID)
+	require.Len(t, patterns, 1)
+	entry := patterns["block outdated"]
+	require.Equal(t, "alice", entry.SilencedBy)
+	require.Equal(t, "node upgrade", entry.Reason)
+	require.Equal(t, time.Hour, entry.Expiry.Sub(entry.CreatedAt))
+	require.True(t, shouldSilentByChatIdRWL(chatID, "latest block outdated 5m"))
+
+	updated, err = SetSilencePatternWL(chatID, "block outdated", 2*time.Hour, "bob", "")
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, "bob", GetSilentPatternsByChatIdRL(chatID)["block outdated"].SilencedBy)
+
+	// returned patterns is a copy
+	delete(patterns, "block outdated")
+	require.Len(t, GetSilentPatternsByChatIdRL(chatID), 1)
+
+	require.NoError(t, RemoveSilencePatternWL(chatID, "block outdated"))
+	require.Empty(t, GetSilentPatternsByChatIdRL(chatID))
+	require.False(t, shouldSilentByChatIdRWL(chatID, "latest block outdated 5m"))
+}
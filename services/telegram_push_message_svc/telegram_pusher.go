@@ -8,8 +8,10 @@ import (
 	"github.com/bcdevtools/validator-health-check/constants"
 	"github.com/bcdevtools/validator-health-check/registry/telegram_bot_registry"
 	"github.com/bcdevtools/validator-health-check/registry/user_registry"
+	lesvc "github.com/bcdevtools/validator-health-check/services/leader_election_svc"
+	"github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/templates"
 	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
-	"github.com/bcdevtools/validator-health-check/utils"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"sort"
 	"strings"
@@ -25,25 +27,76 @@ type telegramPusher struct {
 	queuesReceiverBased map[int64]ReceiverBasedQueue
 	priorityQueue       []ReceiverBasedQueue
 	nonPriorityQueue    []ReceiverBasedQueue
+	draining            map[int64]bool // receivers having queue being drained
+	heldForQuietHours   map[int64]bool // receivers having messages held during quiet hours, delivered as a digest when the window ends
+
+	outboxStore    OutboxStore // nil if not persisted
+	outboxRestored bool
+	outboxDirty    bool
+	inFlight       map[int64][]tptypes.QueueMessage // messages being delivered, by receiver
+	deadLetters    []tptypes.DeadLetter
+	receipts       []tptypes.DeliveryReceipt
 }
 
-func StartTelegramPusherService(appCtx config.AppContext) {
+// StartTelegramPusherService starts the service pushing enqueued messages to Telegram.
+// Outbound messages are persisted via the outbox store, if provided.
+func StartTelegramPusherService(appCtx config.AppContext, outboxStore OutboxStore) {
 	if telePusherSvc != nil {
 		panic("to prevent API limit issue, only one instance of Telegram Pusher is allowed")
 	}
 
-	telePusherSvc = &telegramPusher{
+	telePusherSvc = newTelegramPusher(appCtx, outboxStore)
+
+	go telePusherSvc.start()
+	go telePusherSvc.routinePersistOutbox()
+}
+
+func newTelegramPusher(appCtx config.AppContext, outboxStore OutboxStore) *telegramPusher {
+	return &telegramPusher{
 		appCtx:              appCtx,
 		queuesReceiverBased: make(map[int64]ReceiverBasedQueue),
+		draining:            make(map[int64]bool),
+		heldForQuietHours:   make(map[int64]bool),
+		outboxStore:         outboxStore,
+		inFlight:            make(map[int64][]tptypes.QueueMessage),
 	}
-
-	go telePusherSvc.start()
 }
 
+// EnqueueMessageWL enqueues the message to be pushed to the receiver.
+// Messages are discarded on standby instance, only the leader sends alerts.
+// Messages having severity lower than the minimum severity configured by the receiver are discarded, except scheduled reports.
 func EnqueueMessageWL(message tptypes.QueueMessage) {
+	if !lesvc.IsLeaderRL() {
+		telePusherSvc.appCtx.Logger.Debug("standby instance, discarded message", "receiver-id", message.ReceiverID, "message", message.Message)
+		return
+	}
+
+	if userRecord, found := user_registry.GetUserRecordByTelegramUserIdRL(message.ReceiverID); found && !message.Report {
+		if minSeverity := getMinSeverityOfReceiver(userRecord, message.Chain); message.Severity < minSeverity {
+			telePusherSvc.appCtx.Logger.Debug("message severity lower than minimum severity of receiver, discarded message", "receiver-id", message.ReceiverID, "severity", message.Severity, "min-severity", minSeverity, "message", message.Message)
+			return
+		}
+	}
+
 	telePusherSvc.enqueueMessageWL(message)
 }
 
+// getMinSeverityOfReceiver returns the minimum severity of messages to be sent to the user, the stricter one among the Telegram channel and the chain
+func getMinSeverityOfReceiver(userRecord config.UserRecord, chainName string) tptypes.Severity {
+	minSeverity, err := tptypes.ParseSeverity(userRecord.TelegramConfig.GetMinSeverity())
+	if err != nil {
+		minSeverity = tptypes.SeverityInfo // validated when loading config
+	}
+
+	if chainName != "" {
+		if chainMinSeverity, err := tptypes.ParseSeverity(userRecord.GetMinSeverityOfChainName(chainName)); err == nil && chainMinSeverity > minSeverity {
+			minSeverity = chainMinSeverity
+		}
+	}
+
+	return minSeverity
+}
+
 func (tp *telegramPusher) enqueueMessageWL(message tptypes.QueueMessage) {
 	tp.Lock()
 	defer tp.Unlock()
@@ -60,33 +113,35 @@ func (tp *telegramPusher) enqueueMessageWL(message tptypes.QueueMessage) {
 		}
 	}
 
+	if message.ID == "" {
+		message.ID = uuid.New().String()
+	}
 	if message.EnqueueTimeUTC == (time.Time{}) {
 		message.EnqueueTimeUTC = time.Now().UTC()
 	}
 	existingQueue.EnqueueMessageWL(message)
+	tp.outboxDirty = true
 }
 
 func (tp *telegramPusher) start() {
 	logger := tp.appCtx.Logger
 	defer libapp.TryRecoverAndExecuteExitFunctionIfRecovered(logger)
 
-	var pushedPreviousTurn bool
+	// limit the number of queues draining concurrently, the rate is limited by the Telegram sender
+	semaphore := make(chan struct{}, constants.TELEGRAM_PUSH_CONCURRENCY)
+
 	for {
 		time.Sleep(300 * time.Millisecond)
 
-		if pushedPreviousTurn {
-			// sleep for a while to prevent API limit
-			time.Sleep(10 * time.Second)
-		}
-		pushedPreviousTurn = false
-
-		allQueues := tp.getAllQueuesRL()
-		if len(allQueues) == 0 {
+		if !lesvc.IsLeaderRL() {
+			// standby, messages persisted by the leader are restored on taking over
+			tp.dropOutboxWL()
 			continue
 		}
 
-		var firstNonEmptyQueue ReceiverBasedQueue
-		for _, queue := range allQueues {
+		tp.restoreOutboxWL()
+
+		for _, queue := range tp.getAllQueuesRL() { // queues having the most severe messages first
 			if !queue.AnyPendingMessageRL() {
 				continue
 			}
@@ -96,105 +151,254 @@ func (tp *telegramPusher) start() {
 				continue
 			}
 
-			if time.Since(lastEnqueueTime) < constants.MINIMUM_BETWEEN_TELEGRAM_PUSH_SAME_USER {
+			if time.Since(lastEnqueueTime) < constants.MINIMUM_BETWEEN_TELEGRAM_PUSH_SAME_USER && !queue.GetHighestSeverityRL().IsUrgent() {
+				// wait for more messages to be batched
 				continue
 			}
 
-			firstNonEmptyQueue = queue
+			if time.Now().UTC().Before(queue.GetDeferredUntilRL()) {
+				// back-off after failed attempt
+				continue
+			}
+
+			receiverId := queue.GetReceiverId()
+
+			// during quiet hours of the receiver, only fatal messages are delivered, the others are held
+			minSeverity := tptypes.SeverityInfo
+			digest := false
+			if userRecord, found := user_registry.GetUserRecordByTelegramUserIdRL(receiverId); found && userRecord.IsQuietHoursAt(time.Now()) {
+				if queue.GetHighestSeverityRL() < tptypes.SeverityFatal {
+					tp.setHeldForQuietHoursWL(receiverId, true)
+					continue
+				}
+				minSeverity = tptypes.SeverityFatal
+			} else {
+				digest = tp.isHeldForQuietHoursRL(receiverId)
+			}
+
+			if !tp.markDrainingWL(receiverId) {
+				continue
+			}
+
+			semaphore <- struct{}{}
+			go func(queue ReceiverBasedQueue, minSeverity tptypes.Severity, digest bool) {
+				defer libapp.TryRecoverAndExecuteExitFunctionIfRecovered(logger)
+				defer func() {
+					<-semaphore
+					tp.unmarkDrainingWL(queue.GetReceiverId())
+				}()
+
+				tp.drain(queue, minSeverity, digest)
+			}(queue, minSeverity, digest)
 		}
+	}
+}
+
+// drain pushes a batch of messages, having severity at least the given one, of the queue to the receiver.
+// When digest, messages held during quiet hours are delivered in a bigger batch with a digest header.
+// Messages failed to send are re-enqueued with back-off, until reaching maximum attempts or age, then moved to dead-letter.
+func (tp *telegramPusher) drain(queue ReceiverBasedQueue, minSeverity tptypes.Severity, digest bool) {
+	logger := tp.appCtx.Logger
 
-		if firstNonEmptyQueue == nil {
-			// all cool-down
+	receiverId := queue.GetReceiverId()
+	batchSize := constants.BATCH_SIZE_TELEGRAM_PUSH_PER_USER
+	if digest {
+		batchSize = constants.BATCH_SIZE_TELEGRAM_QUIET_HOURS_DIGEST
+		defer func() {
+			if !queue.AnyPendingMessageRL() {
+				tp.setHeldForQuietHoursWL(receiverId, false)
+			}
+		}()
+	}
+	dequeuedMessages := queue.DequeueMessagesOfSeverityWL(batchSize, minSeverity, func(messages []tptypes.QueueMessage) int {
+		composedMessage, _ := composeMessage(messages, digest)
+		return len(composedMessage)
+	})
+	if len(dequeuedMessages) < 1 {
+		logger.Error("unexpected no message", "receiver-id", receiverId)
+		return
+	}
+
+	nowUTC := time.Now().UTC()
+	var messages, expiredMessages []tptypes.QueueMessage
+
+	for _, message := range dequeuedMessages {
+		if shouldSilentByChatIdRWL(receiverId, message.Message) {
+			logger.Info("silenced message", "receiver-id", receiverId, "message", message.Message)
 			continue
 		}
-
-		receiverId := firstNonEmptyQueue.GetReceiverId()
-		dequeuedMessages := firstNonEmptyQueue.DequeueMessagesWL(constants.BATCH_SIZE_TELEGRAM_PUSH_PER_USER)
-		if len(dequeuedMessages) < 1 {
-			logger.Error("unexpected no message", "receiver-id", receiverId)
+		if nowUTC.Sub(message.EnqueueTimeUTC) > constants.OUTBOX_MESSAGE_MAX_AGE {
+			expiredMessages = append(expiredMessages, message)
 			continue
 		}
+		messages = append(messages, message)
+	}
 
-		var messages []tptypes.QueueMessage
+	if len(expiredMessages) > 0 {
+		tp.moveToDeadLetterWL(expiredMessages, fmt.Sprintf("expired, not delivered within %s", constants.OUTBOX_MESSAGE_MAX_AGE))
+	}
 
-		for _, message := range dequeuedMessages {
-			if shouldSilentByChatIdRWL(receiverId, message.Message) {
-				logger.Info("silenced message", "receiver-id", receiverId, "message", message.Message)
-				continue
-			}
-			messages = append(messages, message)
+	if len(messages) < 1 {
+		tp.markOutboxDirtyWL()
+		return
+	}
+
+	tp.setInFlightWL(receiverId, messages)
+	defer tp.setInFlightWL(receiverId, nil)
+
+	sort.Slice(messages, func(i, j int) bool {
+		left := messages[i]
+		right := messages[j]
+
+		if left.Severity != right.Severity {
+			return left.Severity > right.Severity
 		}
 
-		if len(messages) < 1 {
+		return left.EnqueueTimeUTC.Before(right.EnqueueTimeUTC)
+	})
+	combinedMessage, parseMode := composeMessage(messages, digest)
+
+	telegramMessageId, err := func(receiverId int64, messageContent string, parseMode string) (int, error) {
+		userRecord, found := user_registry.GetUserRecordByTelegramUserIdRL(receiverId)
+		if !found {
+			return 0, fmt.Errorf("user record not found for receiver id %d", receiverId)
+		}
+
+		if userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
+			return 0, fmt.Errorf("telegram config is incomplete for user identity %s", userRecord.Identity)
+		}
+
+		bot, err := telegram_bot_registry.GetTelegramBotByTokenWL(userRecord.TelegramConfig.Token, logger)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to get telegram bot for user identity %s", userRecord.Identity)
+		}
+
+		telegramMessageId, err := SendMessageWithParseMode(bot, receiverId, messageContent, parseMode)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to send message to user identity %s", userRecord.Identity)
+		}
+
+		return telegramMessageId, nil
+	}(receiverId, combinedMessage, parseMode)
+
+	if err == nil {
+		tp.addDeliveryReceiptWL(receiverId, messages, telegramMessageId)
+		return
+	}
+
+	logger.Error("failed to push telegram message", "receiver", receiverId, "message-size", len(combinedMessage), "messages-count", len(messages), "error", err)
+
+	var retryMessages, deadMessages []tptypes.QueueMessage
+	var maxAttempts int
+	for _, message := range messages {
+		message.Attempts++
+		message.LastError = err.Error()
+		if message.Attempts >= constants.OUTBOX_MAX_ATTEMPTS {
+			deadMessages = append(deadMessages, message)
 			continue
 		}
+		if message.Attempts > maxAttempts {
+			maxAttempts = message.Attempts
+		}
+		retryMessages = append(retryMessages, message)
+	}
 
-		sort.Slice(messages, func(i, j int) bool {
-			left := messages[i]
-			right := messages[j]
+	if len(deadMessages) > 0 {
+		tp.moveToDeadLetterWL(deadMessages, fmt.Sprintf("failed to deliver after %d attempts", constants.OUTBOX_MAX_ATTEMPTS))
+	}
 
-			if left.Fatal != right.Fatal {
-				if left.Fatal {
-					return true
-				} else {
-					return false
-				}
-			}
+	if len(retryMessages) > 0 {
+		for _, message := range retryMessages {
+			tp.enqueueMessageWL(message)
+		}
+		queue.DeferWL(time.Now().UTC().Add(getOutboxRetryBackoff(maxAttempts)))
+	}
+}
+
+// composeMessage composes the single message to be sent from the messages, with the digest header if digest.
+func composeMessage(messages []tptypes.QueueMessage, digest bool) (composedMessage string, parseMode string) {
+	composedMessage, parseMode = combineMessages(messages)
+	if digest {
+		composedMessage = templates.Escape(parseMode, fmt.Sprintf("🌙 Digest of %d message(s) held during quiet hours:\n\n", len(messages))) + composedMessage
+	}
+	return
+}
+
+// combineMessages joins the messages into a single message.
+// If any message is formatted, the combined message uses its parse mode and the plain text messages are escaped.
+func combineMessages(messages []tptypes.QueueMessage) (combinedMessage string, parseMode string) {
+	for _, message := range messages {
+		if message.ParseMode != "" {
+			parseMode = message.ParseMode
+			break
+		}
+	}
 
-			return left.EnqueueTimeUTC.Before(right.EnqueueTimeUTC)
-		})
-		messagesContent := make([]string, len(messages))
-		for i, message := range messages {
+	messagesContent := make([]string, len(messages))
+	for i, message := range messages {
+		if message.ParseMode == parseMode {
 			messagesContent[i] = message.Message
+		} else {
+			messagesContent[i] = templates.Escape(parseMode, message.Message)
 		}
-		combinedMessage := strings.Join(messagesContent, constants.BATCH_MESSAGES_LINE_DIVIDER)
+	}
 
-		err := func(receiverId int64, messageContent string, messages []tptypes.QueueMessage) error {
-			var sent bool
-			defer func() {
-				if !sent {
-					// re-enqueue
-					for _, message := range messages {
-						tp.enqueueMessageWL(message)
-					}
-				}
-			}()
+	return strings.Join(messagesContent, templates.Escape(parseMode, constants.BATCH_MESSAGES_LINE_DIVIDER)), parseMode
+}
 
-			userRecord, found := user_registry.GetUserRecordByTelegramUserIdRL(receiverId)
-			if !found {
-				return fmt.Errorf("user record not found for receiver id %d", receiverId)
-			}
+func (tp *telegramPusher) setHeldForQuietHoursWL(receiverId int64, held bool) {
+	tp.Lock()
+	defer tp.Unlock()
 
-			if userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
-				return fmt.Errorf("telegram config is incomplete for user identity %s", userRecord.Identity)
-			}
+	if held {
+		tp.heldForQuietHours[receiverId] = true
+	} else {
+		delete(tp.heldForQuietHours, receiverId)
+	}
+}
 
-			bot, err := telegram_bot_registry.GetTelegramBotByTokenWL(userRecord.TelegramConfig.Token, logger)
-			if err != nil {
-				return errors.Wrapf(err, "failed to get telegram bot for user identity %s", userRecord.Identity)
-			}
+func (tp *telegramPusher) isHeldForQuietHoursRL(receiverId int64) bool {
+	tp.RLock()
+	defer tp.RUnlock()
 
-			_, err = utils.Retry[string](func() (string, error) {
-				_, err := bot.GetInnerTelegramBot().SendMessage(messageContent, receiverId)
-				return "", err
-			})
-			if err != nil {
-				return errors.Wrapf(err, "failed to send message to user identity %s", userRecord.Identity)
-			}
+	return tp.heldForQuietHours[receiverId]
+}
 
-			sent = true
-			return nil
-		}(receiverId, combinedMessage, messages)
+// markDrainingWL marks the queue of the receiver as draining, returns false if it is already draining
+func (tp *telegramPusher) markDrainingWL(receiverId int64) bool {
+	tp.Lock()
+	defer tp.Unlock()
 
-		if err != nil {
-			logger.Error("failed to push telegram message", "receiver", receiverId, "message-size", len(combinedMessage), "messages-count", len(messages), "error", err)
-		}
+	if tp.draining[receiverId] {
+		return false
 	}
+	tp.draining[receiverId] = true
+	return true
 }
 
+func (tp *telegramPusher) unmarkDrainingWL(receiverId int64) {
+	tp.Lock()
+	defer tp.Unlock()
+
+	delete(tp.draining, receiverId)
+}
+
+// getAllQueuesRL returns all the queues, sorted by the highest severity of the pending messages, then priority queues first
 func (tp *telegramPusher) getAllQueuesRL() []ReceiverBasedQueue {
 	tp.RLock()
 	defer tp.RUnlock()
 
-	return append(tp.priorityQueue, tp.nonPriorityQueue...)
+	allQueues := make([]ReceiverBasedQueue, 0, len(tp.priorityQueue)+len(tp.nonPriorityQueue))
+	allQueues = append(allQueues, tp.priorityQueue...)
+	allQueues = append(allQueues, tp.nonPriorityQueue...)
+
+	highestSeverities := make(map[int64]tptypes.Severity, len(allQueues))
+	for _, queue := range allQueues {
+		highestSeverities[queue.GetReceiverId()] = queue.GetHighestSeverityRL()
+	}
+	sort.SliceStable(allQueues, func(i, j int) bool {
+		return highestSeverities[allQueues[i].GetReceiverId()] > highestSeverities[allQueues[j].GetReceiverId()]
+	})
+
+	return allQueues
 }
@@ -0,0 +1,119 @@
+package telegram_push_message_svc
+
+import (
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_combineMessages(t *testing.T) {
+	t.Run("plain messages", func(t *testing.T) {
+		combined, parseMode := combineMessages([]tptypes.QueueMessage{
+			{Message: "a < b"},
+			{Message: "c"},
+		})
+		require.Empty(t, parseMode)
+		require.Equal(t, "a < b\n---\nc", combined)
+	})
+
+	t.Run("plain messages are escaped when mixed with formatted messages", func(t *testing.T) {
+		combined, parseMode := combineMessages([]tptypes.QueueMessage{
+			{Message: "a < b"},
+			{Message: "<b>c</b>", ParseMode: constants.PARSE_MODE_HTML},
+		})
+		require.Equal(t, constants.PARSE_MODE_HTML, parseMode)
+		require.Equal(t, "a &lt; b\n---\n<b>c</b>", combined)
+
+		combined, parseMode = combineMessages([]tptypes.QueueMessage{
+			{Message: "*c*", ParseMode: constants.PARSE_MODE_MARKDOWNV2},
+			{Message: "1.5"},
+		})
+		require.Equal(t, constants.PARSE_MODE_MARKDOWNV2, parseMode)
+		require.Equal(t, "*c*\n\\-\\-\\-\n1\\.5", combined)
+	})
+}
+
+func Test_getMinSeverityOfReceiver(t *testing.T) {
+	userRecord := config.UserRecord{
+		Identity: "user",
+		TelegramConfig: &config.UserTelegramConfig{
+			Username: "user",
+			UserId:   1,
+			Token:    "token",
+		},
+	}
+	require.Equal(t, tptypes.SeverityInfo, getMinSeverityOfReceiver(userRecord, ""))
+	require.Equal(t, tptypes.SeverityInfo, getMinSeverityOfReceiver(userRecord, "cosmoshub-4"))
+
+	userRecord.TelegramConfig.MinSeverity = constants.SEVERITY_WARNING
+	userRecord.MinSeverityPerChain = map[string]string{
+		"cosmoshub-4": constants.SEVERITY_FATAL,
+		"osmosis-1":   constants.SEVERITY_INFO,
+	}
+	require.Equal(t, tptypes.SeverityWarning, getMinSeverityOfReceiver(userRecord, ""))
+	require.Equal(t, tptypes.SeverityFatal, getMinSeverityOfReceiver(userRecord, "cosmoshub-4"))
+	require.Equal(t, tptypes.SeverityFatal, getMinSeverityOfReceiver(userRecord, "CosmosHub-4"), "chain names are case-insensitive")
+	require.Equal(t, tptypes.SeverityWarning, getMinSeverityOfReceiver(userRecord, "osmosis-1"), "the stricter one takes effect")
+}
+
+func Test_receiverBasedQueue_severity(t *testing.T) {
+	queue := newReceiverBasedQueue(1, false)
+	queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "info"})
+	queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "warning 1", Severity: tptypes.SeverityWarning})
+	queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "fatal", Severity: tptypes.SeverityFatal})
+	queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "warning 2", Severity: tptypes.SeverityWarning})
+	require.Equal(t, tptypes.SeverityFatal, queue.GetHighestSeverityRL())
+
+	var dequeued []string
+	for _, message := range queue.DequeueMessagesWL(3) {
+		dequeued = append(dequeued, message.Message)
+	}
+	require.Equal(t, []string{"fatal", "warning 1", "warning 2"}, dequeued, "the most severe first, keep the enqueue order")
+	require.Equal(t, tptypes.SeverityInfo, queue.GetHighestSeverityRL())
+}
+
+func Test_receiverBasedQueue_dequeueOfSeverity(t *testing.T) {
+	queue := newReceiverBasedQueue(1, false)
+	queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "warning", Severity: tptypes.SeverityWarning})
+	queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "fatal 1", Severity: tptypes.SeverityFatal})
+	queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "fatal 2", Severity: tptypes.SeverityFatal})
+
+	dequeued := queue.DequeueMessagesOfSeverityWL(10, tptypes.SeverityFatal, nil)
+	require.Len(t, dequeued, 2, "only fatal messages are dequeued, eg: during quiet hours")
+	require.Empty(t, queue.DequeueMessagesOfSeverityWL(10, tptypes.SeverityFatal, nil))
+
+	remaining := queue.GetAllMessagesRL()
+	require.Len(t, remaining, 1, "the others are held")
+	require.Equal(t, "warning", remaining[0].Message)
+}
+
+func Test_receiverBasedQueue_dequeueLimitedByMessageLength(t *testing.T) {
+	queue := newReceiverBasedQueue(1, false)
+	queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "<b>formatted</b>", ParseMode: constants.PARSE_MODE_HTML})
+	for i := 0; i < 100; i++ {
+		// plain text is escaped when combined with formatted message, each '<' takes 4 characters
+		queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: strings.Repeat("<", 100)})
+	}
+
+	lengthOf := func(messages []tptypes.QueueMessage) int {
+		composedMessage, _ := composeMessage(messages, true)
+		return len(composedMessage)
+	}
+
+	var totalDequeued int
+	for queue.AnyPendingMessageRL() {
+		dequeued := queue.DequeueMessagesOfSeverityWL(constants.BATCH_SIZE_TELEGRAM_QUIET_HOURS_DIGEST, tptypes.SeverityInfo, lengthOf)
+		require.NotEmpty(t, dequeued)
+		require.LessOrEqual(t, lengthOf(dequeued), constants.TELEGRAM_MESSAGE_MAX_LENGTH, "composed message must fit into a Telegram message")
+		totalDequeued += len(dequeued)
+	}
+	require.Equal(t, 101, totalDequeued, "each message is dequeued exactly once")
+
+	queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: strings.Repeat("x", constants.TELEGRAM_MESSAGE_MAX_LENGTH+1)})
+	queue.EnqueueMessageWL(tptypes.QueueMessage{ReceiverID: 1, Message: "next"})
+	require.Len(t, queue.DequeueMessagesWL(10), 1, "too long message is dequeued alone, not blocking the queue")
+	require.Len(t, queue.DequeueMessagesWL(10), 1)
+}
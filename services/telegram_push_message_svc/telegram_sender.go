@@ -0,0 +1,155 @@
+package telegram_push_message_svc
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+	tbotreg "github.com/bcdevtools/validator-health-check/registry/telegram_bot_registry"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var globalTelegramSender *telegramSender
+
+// telegramSender sends Telegram messages within the API limits: per bot (global) and per chat,
+// using token buckets. Telegram `retry_after` instructions on 429 responses are honoured exactly.
+type telegramSender struct {
+	sync.Mutex
+	botBuckets  map[string]*tokenBucket // bot id -> bucket
+	chatBuckets map[string]*tokenBucket // bot id + chat id -> bucket
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func newTelegramSender() *telegramSender {
+	return &telegramSender{
+		botBuckets:  make(map[string]*tokenBucket),
+		chatBuckets: make(map[string]*tokenBucket),
+		now:         time.Now,
+		sleep:       time.Sleep,
+	}
+}
+
+// SendMessage sends the plain text message to the chat via the bot, waits for its turn within the Telegram API limits
+// and retries transient failures. Returns the ID of the sent message.
+func SendMessage(bot tbotreg.TelegramBot, chatId int64, message string) (messageId int, err error) {
+	return SendMessageWithParseMode(bot, chatId, message, "")
+}
+
+// SendMessageWithParseMode is the same as SendMessage, but the message is formatted in the given parse mode: HTML or MarkdownV2
+func SendMessageWithParseMode(bot tbotreg.TelegramBot, chatId int64, message string, parseMode string) (messageId int, err error) {
+	return globalTelegramSender.send(bot.BotID(), chatId, func() (tgbotapi.Message, error) {
+		msg := tgbotapi.NewMessage(chatId, message)
+		msg.ParseMode = parseMode
+		msg.DisableWebPagePreview = parseMode != ""
+		return bot.GetInnerTelegramBot().Send(msg)
+	})
+}
+
+func (s *telegramSender) send(botId string, chatId int64, send func() (tgbotapi.Message, error)) (messageId int, err error) {
+	backoff := constants.TELEGRAM_SEND_MIN_BACKOFF
+	for attempt := 1; ; attempt++ {
+		s.waitTurn(botId, chatId)
+
+		var sentMessage tgbotapi.Message
+		sentMessage, err = send()
+		if err == nil {
+			return sentMessage.MessageID, nil
+		}
+
+		if attempt >= constants.TELEGRAM_SEND_MAX_ATTEMPTS {
+			return 0, errors.Wrapf(err, "failed to send message after %d attempts", attempt)
+		}
+
+		retryAfter, retryable := getRetryInstruction(err)
+		if !retryable {
+			return 0, err
+		}
+
+		if retryAfter > 0 {
+			// flood control applies to the bot, block all of its chats
+			s.blockBot(botId, retryAfter)
+			continue
+		}
+
+		s.sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// waitTurn blocks until a token is available from both the bot and the chat buckets, then consumes them
+func (s *telegramSender) waitTurn(botId string, chatId int64) {
+	for {
+		wait := func() time.Duration {
+			s.Lock()
+			defer s.Unlock()
+
+			now := s.now()
+			botBucket := s.getBucket(s.botBuckets, botId, constants.TELEGRAM_BOT_MESSAGES_PER_SECOND, constants.TELEGRAM_BOT_MESSAGES_PER_SECOND, now)
+			chatBucket := s.getBucket(s.chatBuckets, fmt.Sprintf("%s/%d", botId, chatId), constants.TELEGRAM_CHAT_MESSAGES_PER_SECOND, 1, now)
+
+			wait := botBucket.waitDuration(now)
+			if chatWait := chatBucket.waitDuration(now); chatWait > wait {
+				wait = chatWait
+			}
+			if wait > 0 {
+				return wait
+			}
+
+			botBucket.take(now)
+			chatBucket.take(now)
+			return 0
+		}()
+
+		if wait <= 0 {
+			return
+		}
+		s.sleep(wait)
+	}
+}
+
+func (s *telegramSender) blockBot(botId string, retryAfter time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := s.now()
+	s.getBucket(s.botBuckets, botId, constants.TELEGRAM_BOT_MESSAGES_PER_SECOND, constants.TELEGRAM_BOT_MESSAGES_PER_SECOND, now).blockUntil(now.Add(retryAfter))
+}
+
+func (s *telegramSender) getBucket(buckets map[string]*tokenBucket, key string, rate float64, burst int, now time.Time) *tokenBucket {
+	bucket, found := buckets[key]
+	if !found {
+		bucket = newTokenBucket(rate, burst, now)
+		buckets[key] = bucket
+	}
+	return bucket
+}
+
+// getRetryInstruction parses the error returned from Telegram API.
+// Returns the duration instructed by Telegram to wait before retrying (429 Too Many Requests),
+// and whether the request should be retried: client errors like bot blocked or chat not found are not retried.
+func getRetryInstruction(err error) (retryAfter time.Duration, retryable bool) {
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) {
+		// network error or undecodable response
+		return 0, true
+	}
+
+	if tgErr.RetryAfter > 0 {
+		return time.Duration(tgErr.RetryAfter) * time.Second, true
+	}
+
+	if tgErr.Code == http.StatusTooManyRequests {
+		return constants.TELEGRAM_SEND_MIN_BACKOFF, true
+	}
+
+	return 0, tgErr.Code >= http.StatusInternalServerError || tgErr.Code == 0
+}
+
+func init() {
+	globalTelegramSender = newTelegramSender()
+}
@@ -0,0 +1,122 @@
+package telegram_push_message_svc
+
+import (
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newFakeClockTelegramSender() (sender *telegramSender, clock *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock = &now
+
+	sender = newTelegramSender()
+	sender.now = func() time.Time {
+		return *clock
+	}
+	sender.sleep = func(d time.Duration) {
+		*clock = clock.Add(d)
+	}
+	return
+}
+
+func Test_tokenBucket(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(2, 2, now)
+
+	for i := 0; i < 2; i++ {
+		require.Zero(t, bucket.waitDuration(now), "burst should be available")
+		bucket.take(now)
+	}
+	require.Equal(t, 500*time.Millisecond, bucket.waitDuration(now))
+	require.Zero(t, bucket.waitDuration(now.Add(500*time.Millisecond)))
+
+	bucket.blockUntil(now.Add(10 * time.Second))
+	require.Equal(t, 9*time.Second, bucket.waitDuration(now.Add(time.Second)))
+	require.Zero(t, bucket.waitDuration(now.Add(10*time.Second)))
+	bucket.take(now.Add(10 * time.Second))
+	require.Equal(t, 500*time.Millisecond, bucket.waitDuration(now.Add(10*time.Second)), "only a single token after blocked")
+}
+
+func Test_telegramSender_rateLimits(t *testing.T) {
+	sender, clock := newFakeClockTelegramSender()
+	start := *clock
+
+	sentAt := make(map[int64][]time.Time)
+	for i := 0; i < 3; i++ {
+		for chatId := int64(1); chatId <= 40; chatId++ {
+			chatId := chatId
+			_, err := sender.send("bot", chatId, func() (tgbotapi.Message, error) {
+				sentAt[chatId] = append(sentAt[chatId], *clock)
+				return tgbotapi.Message{MessageID: 1}, nil
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	for _, times := range sentAt {
+		for i := 1; i < len(times); i++ {
+			require.GreaterOrEqual(t, times[i].Sub(times[i-1]), time.Second, "per-chat limit")
+		}
+	}
+
+	// 120 messages at 30 msg/s, first 30 are burst
+	elapsed := clock.Sub(start)
+	require.GreaterOrEqual(t, elapsed, 3*time.Second-time.Millisecond, "per-bot limit")
+	require.Less(t, elapsed, 4*time.Second)
+}
+
+func Test_telegramSender_retryAfter(t *testing.T) {
+	sender, clock := newFakeClockTelegramSender()
+	start := *clock
+
+	var attempts int
+	messageId, err := sender.send("bot", 1, func() (tgbotapi.Message, error) {
+		attempts++
+		if attempts == 1 {
+			return tgbotapi.Message{}, &tgbotapi.Error{
+				Code:               http.StatusTooManyRequests,
+				Message:            "Too Many Requests: retry after 7",
+				ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 7},
+			}
+		}
+		return tgbotapi.Message{MessageID: 99}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 99, messageId)
+	require.Equal(t, 2, attempts)
+	require.Equal(t, 7*time.Second, clock.Sub(start), "should wait exactly as instructed")
+
+	// other chats of the same bot are blocked too
+	sender.blockBot("bot", 5*time.Second)
+	blockedAt := *clock
+	_, err = sender.send("bot", 2, func() (tgbotapi.Message, error) {
+		return tgbotapi.Message{}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, clock.Sub(blockedAt))
+}
+
+func Test_telegramSender_errors(t *testing.T) {
+	sender, _ := newFakeClockTelegramSender()
+
+	var attempts int
+	_, err := sender.send("bot", 1, func() (tgbotapi.Message, error) {
+		attempts++
+		return tgbotapi.Message{}, &tgbotapi.Error{Code: http.StatusForbidden, Message: "Forbidden: bot was blocked by the user"}
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, attempts, "client error should not be retried")
+
+	attempts = 0
+	_, err = sender.send("bot", 1, func() (tgbotapi.Message, error) {
+		attempts++
+		return tgbotapi.Message{}, fmt.Errorf("connection reset by peer")
+	})
+	require.Error(t, err)
+	require.Equal(t, constants.TELEGRAM_SEND_MAX_ATTEMPTS, attempts, "transient error should be retried")
+}
@@ -0,0 +1,178 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"github.com/pkg/errors"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Alert types, each can be rendered by its own template, overridden by file `<alert-type>.tmpl` in the templates directory.
+// File `alert.tmpl` overrides the default template of all alert types.
+//
+//goland:noinspection GoUnusedConst
+const (
+	AlertTypeDefault                    = "alert"
+	AlertTypeHealthCheckFailed          = "health-check-failed"
+	AlertTypeQueryFailed                = "query-failed"
+	AlertTypeBlockOutdated              = "block-outdated"
+	AlertTypeValidatorNotFound          = "validator-not-found"
+	AlertTypeValidatorUnbonded          = "validator-unbonded"
+	AlertTypeValidatorUnbonding         = "validator-unbonding"
+	AlertTypeValidatorBondStatus        = "validator-bond-status"
+	AlertTypeValidatorTombstoned        = "validator-tombstoned"
+	AlertTypeValidatorJailed            = "validator-jailed"
+	AlertTypeMissedBlocks               = "missed-blocks"
+	AlertTypeLowUptime                  = "low-uptime"
+	AlertTypeSigningInfoNotFound        = "signing-info-not-found"
+	AlertTypeConsensusAddressNotFound   = "consensus-address-not-found"
+	AlertTypeValidatorNode              = "validator-node"
+	AlertTypeManagedEndpoint            = "managed-endpoint"
+	AlertTypeManagedEndpointCertificate = "managed-endpoint-certificate"
+	AlertTypeGovernance                 = "governance"
+)
+
+// defaultAlertTemplate renders like: 🚨 [chain][valoper] message
+const defaultAlertTemplate = `{{.Emoji}} {{bold (printf "[%s]" .Chain)}}{{if .Validator}}{{link (printf "[%s]" .Validator) .ValidatorUrl}}{{end}} {{escape .Message}}{{if .TxHash}}
+{{link .TxHash .TxUrl}}{{end}}`
+
+// AlertData is the data available to alert templates
+type AlertData struct {
+	Type         string
+	Severity     tptypes.Severity
+	Emoji        string // severity emoji
+	Chain        string
+	Validator    string // valoper, empty for chain-level alerts
+	ValidatorUrl string // explorer link of the validator, empty if not configured
+	TxHash       string
+	TxUrl        string // explorer link of the tx, empty if not configured
+	Message      string // plain text, must be escaped via `escape`
+}
+
+var mutex sync.RWMutex
+var globalParseMode string
+var globalAlertTemplates map[string]*template.Template // alert type -> template
+
+// LoadTemplatesWL sets the parse mode and loads the alert templates, user templates are loaded from the given directory if exists.
+// Invalid user templates are skipped and reported via the returned error, the default template is used instead.
+func LoadTemplatesWL(parseMode string, templatesDir string) error {
+	alertTemplates := map[string]*template.Template{
+		AlertTypeDefault: template.Must(newTemplate(parseMode, AlertTypeDefault).Parse(defaultAlertTemplate)),
+	}
+
+	var errs []string
+	entries, err := os.ReadDir(templatesDir)
+	if err != nil && !os.IsNotExist(err) {
+		errs = append(errs, errors.Wrap(err, "failed to read templates directory").Error())
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tmpl") {
+			continue
+		}
+
+		alertType := strings.TrimSuffix(entry.Name(), ".tmpl")
+		bz, err := os.ReadFile(path.Join(templatesDir, entry.Name()))
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to read template %s", entry.Name()).Error())
+			continue
+		}
+
+		tmpl, err := newTemplate(parseMode, alertType).Parse(string(bz))
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to parse template %s", entry.Name()).Error())
+			continue
+		}
+
+		alertTemplates[alertType] = tmpl
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	globalParseMode = parseMode
+	globalAlertTemplates = alertTemplates
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// GetParseModeRL returns the parse mode of rendered alerts
+func GetParseModeRL() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	return globalParseMode
+}
+
+// RenderAlertRL renders the alert using the template of its type, or the default template.
+// Falls back to the built-in template if the user template fails to execute.
+func RenderAlertRL(data AlertData) (text string, parseMode string) {
+	mutex.RLock()
+	parseMode = globalParseMode
+	tmpl, found := globalAlertTemplates[data.Type]
+	if !found {
+		tmpl, found = globalAlertTemplates[AlertTypeDefault]
+	}
+	mutex.RUnlock()
+
+	if data.Emoji == "" {
+		data.Emoji = GetSeverityEmoji(data.Severity)
+	}
+
+	if found {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err == nil {
+			return buf.String(), parseMode
+		}
+	}
+
+	var buf bytes.Buffer
+	_ = template.Must(newTemplate(parseMode, AlertTypeDefault).Parse(defaultAlertTemplate)).Execute(&buf, data)
+	return buf.String(), parseMode
+}
+
+// GetSeverityEmoji returns the emoji representing the severity of the alert
+func GetSeverityEmoji(severity tptypes.Severity) string {
+	switch severity {
+	case tptypes.SeverityInfo:
+		return "ℹ️"
+	case tptypes.SeverityWarning:
+		return "⚠️"
+	case tptypes.SeverityCritical:
+		return "❗"
+	default:
+		return "🚨"
+	}
+}
+
+func newTemplate(parseMode, name string) *template.Template {
+	return template.New(name).Option("missingkey=zero").Funcs(template.FuncMap{
+		"escape": func(text string) string {
+			return Escape(parseMode, text)
+		},
+		"bold": func(text string) string {
+			return Bold(parseMode, text)
+		},
+		"italic": func(text string) string {
+			return Italic(parseMode, text)
+		},
+		"code": func(text string) string {
+			return Code(parseMode, text)
+		},
+		"link": func(text, url string) string {
+			return Link(parseMode, text, url)
+		},
+	})
+}
+
+func init() {
+	_ = LoadTemplatesWL(constants.PARSE_MODE_HTML, "")
+}
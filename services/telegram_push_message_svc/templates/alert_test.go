@@ -0,0 +1,72 @@
+package templates
+
+import (
+	"github.com/bcdevtools/validator-health-check/constants"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestRenderAlertRL(t *testing.T) {
+	defer func() {
+		_ = LoadTemplatesWL(constants.PARSE_MODE_HTML, "")
+	}()
+
+	data := AlertData{
+		Type:         AlertTypeMissedBlocks,
+		Severity:     tptypes.SeverityFatal,
+		Chain:        "cosmoshub-4",
+		Validator:    "cosmosvaloper1abc",
+		ValidatorUrl: "https://explorer.io/validators/cosmosvaloper1abc",
+		Message:      "missed <10> blocks & more",
+	}
+
+	t.Run("default HTML", func(t *testing.T) {
+		require.NoError(t, LoadTemplatesWL(constants.PARSE_MODE_HTML, ""))
+		text, parseMode := RenderAlertRL(data)
+		require.Equal(t, constants.PARSE_MODE_HTML, parseMode)
+		require.Equal(t, `🚨 <b>[cosmoshub-4]</b><a href="https://explorer.io/validators/cosmosvaloper1abc">[cosmosvaloper1abc]</a> missed &lt;10&gt; blocks &amp; more`, text)
+	})
+
+	t.Run("default MarkdownV2 without explorer", func(t *testing.T) {
+		require.NoError(t, LoadTemplatesWL(constants.PARSE_MODE_MARKDOWNV2, ""))
+		data := data
+		data.Severity = tptypes.SeverityWarning
+		data.ValidatorUrl = ""
+		text, parseMode := RenderAlertRL(data)
+		require.Equal(t, constants.PARSE_MODE_MARKDOWNV2, parseMode)
+		require.Equal(t, `⚠️ *\[cosmoshub\-4\]*\[cosmosvaloper1abc\] missed <10\> blocks & more`, text)
+	})
+
+	t.Run("user templates override by alert type", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(path.Join(dir, AlertTypeMissedBlocks+".tmpl"), []byte(`{{.Emoji}} {{code .Chain}}: {{escape .Message}}`), 0o644))
+		require.NoError(t, os.WriteFile(path.Join(dir, "readme.txt"), []byte(`{{`), 0o644))
+		require.NoError(t, LoadTemplatesWL(constants.PARSE_MODE_HTML, dir))
+
+		text, _ := RenderAlertRL(data)
+		require.Equal(t, `🚨 <code>cosmoshub-4</code>: missed &lt;10&gt; blocks &amp; more`, text)
+
+		data := data
+		data.Type = AlertTypeLowUptime
+		text, _ = RenderAlertRL(data)
+		require.Contains(t, text, "<b>[cosmoshub-4]</b>", "other types should use the default template")
+	})
+
+	t.Run("invalid user templates fall back to default", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(path.Join(dir, AlertTypeMissedBlocks+".tmpl"), []byte(`{{.Chain`), 0o644))
+		require.NoError(t, os.WriteFile(path.Join(dir, AlertTypeLowUptime+".tmpl"), []byte(`{{.Chain.Unknown}}`), 0o644))
+		require.Error(t, LoadTemplatesWL(constants.PARSE_MODE_HTML, dir))
+
+		text, _ := RenderAlertRL(data)
+		require.Contains(t, text, "<b>[cosmoshub-4]</b>", "parse error should fall back to default")
+
+		data := data
+		data.Type = AlertTypeLowUptime
+		text, _ = RenderAlertRL(data)
+		require.Contains(t, text, "<b>[cosmoshub-4]</b>", "execution error should fall back to default")
+	})
+}
@@ -0,0 +1,85 @@
+package templates
+
+import (
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+	"strings"
+)
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+var htmlAttributeEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+var markdownV2Escaper = func() *strings.Replacer {
+	var oldNew []string
+	for _, c := range `\_*[]()~` + "`" + `>#+-=|{}.!` {
+		oldNew = append(oldNew, string(c), `\`+string(c))
+	}
+	return strings.NewReplacer(oldNew...)
+}()
+
+var markdownV2UrlEscaper = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+
+// Escape escapes the plain text to be embedded into a message of the given parse mode
+func Escape(parseMode, text string) string {
+	switch parseMode {
+	case constants.PARSE_MODE_HTML:
+		return htmlEscaper.Replace(text)
+	case constants.PARSE_MODE_MARKDOWNV2:
+		return markdownV2Escaper.Replace(text)
+	default:
+		return text
+	}
+}
+
+// Bold renders the plain text as bold
+func Bold(parseMode, text string) string {
+	switch parseMode {
+	case constants.PARSE_MODE_HTML:
+		return "<b>" + Escape(parseMode, text) + "</b>"
+	case constants.PARSE_MODE_MARKDOWNV2:
+		return "*" + Escape(parseMode, text) + "*"
+	default:
+		return text
+	}
+}
+
+// Italic renders the plain text as italic
+func Italic(parseMode, text string) string {
+	switch parseMode {
+	case constants.PARSE_MODE_HTML:
+		return "<i>" + Escape(parseMode, text) + "</i>"
+	case constants.PARSE_MODE_MARKDOWNV2:
+		return "_" + Escape(parseMode, text) + "_"
+	default:
+		return text
+	}
+}
+
+// Code renders the plain text as inline code
+func Code(parseMode, text string) string {
+	switch parseMode {
+	case constants.PARSE_MODE_HTML:
+		return "<code>" + Escape(parseMode, text) + "</code>"
+	case constants.PARSE_MODE_MARKDOWNV2:
+		return "`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(text) + "`"
+	default:
+		return text
+	}
+}
+
+// Link renders the plain text as link to the URL, or just the text if the URL is empty
+func Link(parseMode, text, url string) string {
+	if url == "" {
+		return Escape(parseMode, text)
+	}
+
+	switch parseMode {
+	case constants.PARSE_MODE_HTML:
+		return fmt.Sprintf(`<a href="%s">%s</a>`, htmlAttributeEscaper.Replace(url), Escape(parseMode, text))
+	case constants.PARSE_MODE_MARKDOWNV2:
+		return fmt.Sprintf("[%s](%s)", Escape(parseMode, text), markdownV2UrlEscaper.Replace(url))
+	default:
+		return fmt.Sprintf("%s (%s)", text, url)
+	}
+}
@@ -0,0 +1,20 @@
+package templates
+
+import (
+	"github.com/bcdevtools/validator-health-check/constants"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	require.Equal(t, "a &lt;b&gt; &amp; c", Escape(constants.PARSE_MODE_HTML, "a <b> & c"))
+	require.Equal(t, `1\.5% \- \[x\] \(y\) \_z\_ \*w\*\!`, Escape(constants.PARSE_MODE_MARKDOWNV2, "1.5% - [x] (y) _z_ *w*!"))
+	require.Equal(t, "a <b> & c", Escape("", "a <b> & c"))
+}
+
+func TestLink(t *testing.T) {
+	require.Equal(t, `<a href="https://x.io/v?a=1&amp;b=&quot;2&quot;">&lt;val&gt;</a>`, Link(constants.PARSE_MODE_HTML, "<val>", `https://x.io/v?a=1&b="2"`))
+	require.Equal(t, `[val\.1](https://x.io/(v\))`, Link(constants.PARSE_MODE_MARKDOWNV2, "val.1", "https://x.io/(v)"))
+	require.Equal(t, "val (https://x.io/v)", Link("", "val", "https://x.io/v"))
+	require.Equal(t, "&lt;val&gt;", Link(constants.PARSE_MODE_HTML, "<val>", ""), "no URL, text only")
+}
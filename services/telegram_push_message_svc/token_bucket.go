@@ -0,0 +1,65 @@
+package telegram_push_message_svc
+
+import (
+	"time"
+)
+
+// tokenBucket is a token-bucket rate limiter, refilled continuously at the given rate up to the burst size.
+// Not thread-safe, guarded by the owner.
+type tokenBucket struct {
+	rate         float64 // tokens per second
+	burst        float64
+	tokens       float64
+	updatedAt    time.Time
+	blockedUntil time.Time // no token is given until this time, as instructed by Telegram `retry_after`
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:      rate,
+		burst:     float64(burst),
+		tokens:    float64(burst),
+		updatedAt: now,
+	}
+}
+
+// waitDuration returns the duration to wait until a token is available, zero if available now
+func (b *tokenBucket) waitDuration(now time.Time) time.Duration {
+	b.refill(now)
+
+	var wait time.Duration
+	if b.tokens < 1 {
+		wait = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	}
+	if blocked := b.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	return wait
+}
+
+// take consumes a token, must be called only when waitDuration returns zero
+func (b *tokenBucket) take(now time.Time) {
+	b.refill(now)
+	b.tokens--
+}
+
+// blockUntil stops giving tokens until the given time, only a single token is available right after that
+func (b *tokenBucket) blockUntil(until time.Time) {
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+	b.tokens = 1
+	b.updatedAt = b.blockedUntil
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if !now.After(b.updatedAt) {
+		return
+	}
+
+	b.tokens += now.Sub(b.updatedAt).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.updatedAt = now
+}
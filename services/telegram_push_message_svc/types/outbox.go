@@ -0,0 +1,25 @@
+package types
+
+import "time"
+
+// DeadLetter is a message given up delivering, kept for root users to inspect or retry
+type DeadLetter struct {
+	Message   QueueMessage `json:"message"`
+	Reason    string       `json:"reason"`
+	DeadAtUTC time.Time    `json:"dead-at"`
+}
+
+// DeliveryReceipt records a successful delivery of a batch of messages
+type DeliveryReceipt struct {
+	MessageIDs        []string  `json:"message-ids"`
+	ReceiverID        int64     `json:"receiver-id"`
+	TelegramMessageID int       `json:"telegram-message-id"`
+	DeliveredAtUTC    time.Time `json:"delivered-at"`
+}
+
+// OutboxSnapshot is the persisted state of the outbound messages
+type OutboxSnapshot struct {
+	Pending     []QueueMessage    `json:"pending"` // including messages being delivered
+	DeadLetters []DeadLetter      `json:"dead-letters"`
+	Receipts    []DeliveryReceipt `json:"receipts"`
+}
@@ -3,9 +3,15 @@ package types
 import "time"
 
 type QueueMessage struct {
-	ReceiverID     int64
-	Priority       bool
-	Fatal          bool
-	Message        string
-	EnqueueTimeUTC time.Time
+	ID             string    `json:"id"` // assigned on enqueue
+	ReceiverID     int64     `json:"receiver-id"`
+	Priority       bool      `json:"priority,omitempty"`
+	Severity       Severity  `json:"severity,omitempty"`
+	Chain          string    `json:"chain,omitempty"`  // chain the alert is about, empty for non chain-specific messages
+	Report         bool      `json:"report,omitempty"` // scheduled report, not subject to the minimum severity of the receiver
+	Message        string    `json:"message"`
+	ParseMode      string    `json:"parse-mode,omitempty"` // HTML or MarkdownV2, empty for plain text
+	EnqueueTimeUTC time.Time `json:"enqueue-time"`
+	Attempts       int       `json:"attempts,omitempty"` // number of failed delivery attempts
+	LastError      string    `json:"last-error,omitempty"`
 }
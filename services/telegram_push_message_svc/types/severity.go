@@ -0,0 +1,65 @@
+package types
+
+import (
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+)
+
+// Severity of an alert, ordered from the least to the most severe
+type Severity int8
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+	SeverityFatal
+)
+
+// ParseSeverity parses the severity from its name, as used in configuration
+func ParseSeverity(name string) (Severity, error) {
+	switch name {
+	case constants.SEVERITY_INFO:
+		return SeverityInfo, nil
+	case constants.SEVERITY_WARNING:
+		return SeverityWarning, nil
+	case constants.SEVERITY_CRITICAL:
+		return SeverityCritical, nil
+	case constants.SEVERITY_FATAL:
+		return SeverityFatal, nil
+	default:
+		return SeverityInfo, fmt.Errorf("unknown severity %s, must be one of: %s, %s, %s, %s", name, constants.SEVERITY_INFO, constants.SEVERITY_WARNING, constants.SEVERITY_CRITICAL, constants.SEVERITY_FATAL)
+	}
+}
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return constants.SEVERITY_INFO
+	case SeverityWarning:
+		return constants.SEVERITY_WARNING
+	case SeverityCritical:
+		return constants.SEVERITY_CRITICAL
+	case SeverityFatal:
+		return constants.SEVERITY_FATAL
+	default:
+		return fmt.Sprintf("severity(%d)", int8(s))
+	}
+}
+
+// IsUrgent returns true if the alert should be delivered without waiting to be batched with other alerts
+func (s Severity) IsUrgent() bool {
+	return s >= SeverityCritical
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
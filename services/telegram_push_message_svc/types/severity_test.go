@@ -0,0 +1,40 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	for _, severity := range []Severity{SeverityInfo, SeverityWarning, SeverityCritical, SeverityFatal} {
+		parsed, err := ParseSeverity(severity.String())
+		require.NoError(t, err)
+		require.Equal(t, severity, parsed)
+	}
+
+	_, err := ParseSeverity("error")
+	require.Error(t, err)
+	_, err = ParseSeverity("")
+	require.Error(t, err)
+
+	require.True(t, SeverityInfo < SeverityWarning && SeverityWarning < SeverityCritical && SeverityCritical < SeverityFatal)
+	require.False(t, SeverityWarning.IsUrgent())
+	require.True(t, SeverityCritical.IsUrgent())
+}
+
+func TestSeverity_JSON(t *testing.T) {
+	bz, err := json.Marshal(QueueMessage{Severity: SeverityCritical})
+	require.NoError(t, err)
+	require.Contains(t, string(bz), `"severity":"critical"`)
+
+	var message QueueMessage
+	require.NoError(t, json.Unmarshal(bz, &message))
+	require.Equal(t, SeverityCritical, message.Severity)
+
+	bz, err = json.Marshal(QueueMessage{Severity: SeverityInfo})
+	require.NoError(t, err)
+	require.NotContains(t, string(bz), "severity", "info is the default")
+
+	require.Error(t, json.Unmarshal([]byte(`{"severity":"unknown"}`), &message))
+}
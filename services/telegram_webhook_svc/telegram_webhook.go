@@ -0,0 +1,118 @@
+package telegram_webhook_svc
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	libapp "github.com/EscanBE/go-lib/app"
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	tbotreg "github.com/bcdevtools/validator-health-check/registry/telegram_bot_registry"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/pkg/errors"
+	"net"
+	"net/http"
+	"path"
+	"sync"
+)
+
+var serverMutex sync.Mutex
+var globalServer *http.Server
+
+// updateReceiver accepts updates received via webhook, implemented by tbotreg.TelegramBot
+type updateReceiver interface {
+	ReceiveWebhookUpdate(update tgbotapi.Update) bool
+}
+
+// StartTelegramWebhookService starts the HTTP listener receiving updates of the bots from Telegram.
+// Bots are switched to receive updates via webhook, so it must be started before any bot is created.
+func StartTelegramWebhookService(appCtx config.AppContext) error {
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+
+	if globalServer != nil {
+		panic("telegram webhook service already started")
+	}
+
+	webhookConfig := appCtx.AppConfig.TelegramWebhook
+	listener, err := net.Listen("tcp", webhookConfig.GetListenAddress())
+	if err != nil {
+		return errors.Wrap(err, "failed to listen for telegram webhook")
+	}
+
+	tbotreg.UseWebhookWL(webhookConfig.PublicUrl, webhookConfig.SecretToken)
+
+	globalServer = &http.Server{
+		Handler: newWebhookHandler(webhookConfig.SecretToken, func(webhookId string) (updateReceiver, bool) {
+			return tbotreg.GetTelegramBotByWebhookIdRL(webhookId)
+		}, appCtx.Logger),
+	}
+
+	go func(server *http.Server) {
+		logger := appCtx.Logger
+		defer libapp.TryRecoverAndExecuteExitFunctionIfRecovered(logger)
+
+		logger.Info("telegram webhook receiver listening", "address", listener.Addr().String())
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("telegram webhook receiver stopped", "error", err.Error())
+		}
+	}(globalServer)
+
+	return nil
+}
+
+// StopTelegramWebhookService stops the HTTP listener, in-flight updates are given time to complete.
+// Webhooks are deregistered when bots stop receiving updates.
+func StopTelegramWebhookService() {
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+
+	if globalServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.TELEGRAM_WEBHOOK_SHUTDOWN_TIMEOUT)
+	defer cancel()
+
+	_ = globalServer.Shutdown(ctx)
+	globalServer = nil
+}
+
+// newWebhookHandler creates the handler of updates delivered by Telegram,
+// routed to the bot by the last path segment of the URL after checking the secret token header.
+func newWebhookHandler(secretToken string, findReceiver func(webhookId string) (updateReceiver, bool), logger logging.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(constants.TELEGRAM_WEBHOOK_SECRET_TOKEN_HEADER)), []byte(secretToken)) != 1 {
+			logger.Error("rejected telegram webhook request having invalid secret token", "remote", r.RemoteAddr, "path", r.URL.Path)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		receiver, found := findReceiver(path.Base(r.URL.Path))
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var update tgbotapi.Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, constants.TELEGRAM_WEBHOOK_MAX_BODY_SIZE)).Decode(&update); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if !receiver.ReceiveWebhookUpdate(update) {
+			// Telegram re-delivers the update later
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,203 @@
+package telegram_webhook_svc
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/bcdevtools/validator-health-check/constants"
+	tbotreg "github.com/bcdevtools/validator-health-check/registry/telegram_bot_registry"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTelegramServer serves the Telegram Bot API methods used by the webhook mode
+type fakeTelegramServer struct {
+	sync.Mutex
+	requests map[string]url.Values // method -> params of the latest request
+	counts   map[string]int        // method -> number of requests
+}
+
+func (s *fakeTelegramServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	s.Lock()
+	s.requests[method] = r.PostForm
+	s.counts[method]++
+	s.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch method {
+	case "getMe":
+		// path: /bot<id>:<secret>/getMe
+		botId := strings.TrimPrefix(r.URL.Path[:strings.Index(r.URL.Path, ":")], "/bot")
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"id":` + botId + `,"is_bot":true,"first_name":"Test","username":"test_bot"}}`))
+	case "setWebhook", "deleteWebhook":
+		_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+	default:
+		_, _ = w.Write([]byte(`{"ok":false,"error_code":404,"description":"Not Found"}`))
+	}
+}
+
+func (s *fakeTelegramServer) getRequest(method string) (url.Values, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	params, found := s.requests[method]
+	return params, found
+}
+
+func (s *fakeTelegramServer) countRequests(method string) int {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.counts[method]
+}
+
+// redirectTransport redirects requests to Telegram Bot API to the fake server
+type redirectTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	return t.next.RoundTrip(r)
+}
+
+func TestWebhook(t *testing.T) {
+	fakeTelegram := &fakeTelegramServer{
+		requests: make(map[string]url.Values),
+		counts:   make(map[string]int),
+	}
+	telegramServer := httptest.NewServer(fakeTelegram)
+	defer telegramServer.Close()
+
+	telegramUrl, err := url.Parse(telegramServer.URL)
+	require.NoError(t, err)
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = redirectTransport{target: telegramUrl, next: defaultTransport}
+	defer func() {
+		http.DefaultTransport = defaultTransport
+	}()
+
+	const secretToken = "webhook_secret-token"
+	logger := logging.NewDefaultLogger()
+	tbotreg.UseWebhookWL("https://bot.example.com/telegram/", secretToken)
+
+	go func() {
+		<-tbotreg.ChannelNewBot
+	}()
+	bot, err := tbotreg.GetTelegramBotByTokenWL("8026:token", logger)
+	require.NoError(t, err)
+	require.Equal(t, "8026", bot.WebhookId())
+
+	receiver := httptest.NewServer(newWebhookHandler(secretToken, func(webhookId string) (updateReceiver, bool) {
+		return tbotreg.GetTelegramBotByWebhookIdRL(webhookId)
+	}, logger))
+	defer receiver.Close()
+
+	postUpdate := func(path, secretToken string, updateId int) int {
+		bz, err := json.Marshal(tgbotapi.Update{
+			UpdateID: updateId,
+			Message: &tgbotapi.Message{
+				Text: "/status",
+				Chat: &tgbotapi.Chat{ID: 1},
+			},
+		})
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, receiver.URL+path, bytes.NewReader(bz))
+		require.NoError(t, err)
+		if secretToken != "" {
+			req.Header.Set(constants.TELEGRAM_WEBHOOK_SECRET_TOKEN_HEADER, secretToken)
+		}
+
+		res, err := receiver.Client().Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		return res.StatusCode
+	}
+
+	require.Equal(t, http.StatusServiceUnavailable, postUpdate("/telegram/8026", secretToken, 1), "webhook not registered yet")
+
+	updates := bot.GetUpdatesChannel()
+	params, found := fakeTelegram.getRequest("setWebhook")
+	require.True(t, found, "webhook must be registered")
+	require.Equal(t, "https://bot.example.com/telegram/8026", params.Get("url"))
+	require.Equal(t, secretToken, params.Get("secret_token"))
+
+	require.Equal(t, http.StatusOK, postUpdate("/telegram/8026", secretToken, 2))
+	select {
+	case update := <-updates:
+		require.Equal(t, 2, update.UpdateID)
+		require.Equal(t, "/status", update.Message.Text)
+	case <-time.After(time.Second):
+		t.Fatal("update not delivered")
+	}
+
+	require.Equal(t, http.StatusUnauthorized, postUpdate("/telegram/8026", "", 3), "missing secret token")
+	require.Equal(t, http.StatusUnauthorized, postUpdate("/telegram/8026", "invalid", 3), "invalid secret token")
+	require.Equal(t, http.StatusNotFound, postUpdate("/telegram/1", secretToken, 3), "unknown bot")
+
+	res, err := receiver.Client().Get(receiver.URL + "/telegram/8026")
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+
+	// step down, the webhook is kept because it might have been registered by the new leader
+	bot.SuspendReceivingUpdates()
+	_, open := <-updates
+	require.False(t, open, "updates channel must be closed")
+	require.Equal(t, http.StatusServiceUnavailable, postUpdate("/telegram/8026", secretToken, 3))
+	require.Zero(t, fakeTelegram.countRequests("deleteWebhook"), "webhook must not be deregistered on step-down")
+
+	// re-elected
+	updates = bot.GetUpdatesChannel()
+	require.Equal(t, 2, fakeTelegram.countRequests("setWebhook"), "webhook must be registered again")
+	require.Equal(t, http.StatusOK, postUpdate("/telegram/8026", secretToken, 3))
+	select {
+	case update := <-updates:
+		require.Equal(t, 3, update.UpdateID)
+	case <-time.After(time.Second):
+		t.Fatal("update not delivered")
+	}
+
+	// stop receiving
+	_, found = fakeTelegram.getRequest("deleteWebhook")
+	require.False(t, found)
+
+	bot.StopReceivingUpdates()
+	_, found = fakeTelegram.getRequest("deleteWebhook")
+	require.True(t, found, "webhook must be deregistered")
+
+	_, open = <-updates
+	require.False(t, open, "updates channel must be closed")
+	require.Equal(t, http.StatusServiceUnavailable, postUpdate("/telegram/8026", secretToken, 4))
+
+	bot.StopReceivingUpdates() // no-op
+	require.Equal(t, 1, fakeTelegram.countRequests("deleteWebhook"))
+
+	// shutting down after step-down must not deregister the webhook registered by the new leader
+	go func() {
+		<-tbotreg.ChannelNewBot
+	}()
+	exLeaderBot, err := tbotreg.GetTelegramBotByTokenWL("8050:token", logger)
+	require.NoError(t, err)
+	_ = exLeaderBot.GetUpdatesChannel()
+	require.Equal(t, 3, fakeTelegram.countRequests("setWebhook"))
+
+	exLeaderBot.SuspendReceivingUpdates()
+	exLeaderBot.StopReceivingUpdates()
+	require.Equal(t, 1, fakeTelegram.countRequests("deleteWebhook"), "webhook of the new leader must not be deregistered")
+	require.Equal(t, http.StatusServiceUnavailable, postUpdate("/telegram/8050", secretToken, 5))
+}
@@ -72,3 +72,18 @@ func NormalizeRpcEndpoint(endpoint string) string {
 
 	return newEndpoint
 }
+
+// NormalizeGrpcEndpoint normalizes gRPC endpoint, so the same endpoint is always represented the same way.
+// Port 443 is added to TLS endpoint (scheme `https://` or `grpcs://`) without port,
+// plain-text endpoint is kept as is because there is no common default port.
+func NormalizeGrpcEndpoint(endpoint string) string {
+	endpoint = strings.TrimSuffix(strings.TrimSpace(endpoint), "/")
+
+	if strings.HasPrefix(endpoint, "https://") || strings.HasPrefix(endpoint, "grpcs://") {
+		if !endsWithPort.MatchString(endpoint) {
+			endpoint += ":443"
+		}
+	}
+
+	return endpoint
+}
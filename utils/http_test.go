@@ -135,3 +135,48 @@ func TestNormalizeRpcEndpoint(t *testing.T) {
 		})
 	}
 }
+
+//goland:noinspection HttpUrlsUsage
+func TestNormalizeGrpcEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		want     string
+	}{
+		{
+			name:     "plain-text with port",
+			endpoint: "localhost:9090",
+			want:     "localhost:9090",
+		},
+		{
+			name:     "plain-text with trailing slash",
+			endpoint: "grpc://localhost:9090/",
+			want:     "grpc://localhost:9090",
+		},
+		{
+			name:     "https without port",
+			endpoint: "https://grpc.example.com",
+			want:     "https://grpc.example.com:443",
+		},
+		{
+			name:     "grpcs without port, trailing slash",
+			endpoint: "grpcs://grpc.example.com/",
+			want:     "grpcs://grpc.example.com:443",
+		},
+		{
+			name:     "grpcs with port",
+			endpoint: "grpcs://grpc.example.com:9443",
+			want:     "grpcs://grpc.example.com:9443",
+		},
+		{
+			name:     "surrounding spaces",
+			endpoint: " localhost:9090 ",
+			want:     "localhost:9090",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NormalizeGrpcEndpoint(tt.endpoint))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"time"
 )
 
@@ -31,6 +32,15 @@ var defaultRetryOption = DefaultRetryOption()
 func Retry[T any](
 	f func() (T, error),
 	retryOption ...RetryOption,
+) (res T, err error) {
+	return RetryWithContext(context.Background(), f, retryOption...)
+}
+
+// RetryWithContext is the same as Retry, but stops retrying once the context is done.
+func RetryWithContext[T any](
+	ctx context.Context,
+	f func() (T, error),
+	retryOption ...RetryOption,
 ) (res T, err error) {
 	startTime := time.Now().UTC()
 	tryCount := -1
@@ -59,6 +69,10 @@ func Retry[T any](
 			firstErr = err
 		}
 
+		if ctx.Err() != nil {
+			break
+		}
+
 		if tryCount < minRetryCount {
 			continue
 		}
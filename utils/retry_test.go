@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRetryWithContext(t *testing.T) {
+	t.Run("stop retrying when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var count int
+		startTime := time.Now()
+		_, err := RetryWithContext(ctx, func() (int, error) {
+			count++
+			return 0, fmt.Errorf("error %d", count)
+		}, DefaultRetryOption().MinCount(10).MaxDuration(time.Minute))
+		require.Error(t, err)
+		require.Equal(t, "error 1", err.Error(), "first error should be returned")
+		require.Equal(t, 1, count)
+		require.Less(t, time.Since(startTime), time.Second)
+	})
+
+	t.Run("retry until success", func(t *testing.T) {
+		var count int
+		res, err := RetryWithContext(context.Background(), func() (int, error) {
+			count++
+			if count < 3 {
+				return 0, fmt.Errorf("error %d", count)
+			}
+			return count, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, res)
+	})
+}
@@ -13,3 +13,12 @@ func Collisions(slice1, slice2 []string) []string {
 	}
 	return result
 }
+
+func Contains(slice []string, element string) bool {
+	for _, s := range slice {
+		if s == element {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SplitTextByLines splits the text into chunks having length at most maxLength (bytes), at line breaks.
+// Lines longer than maxLength are split at rune boundary.
+// Line breaks surrounding a chunk are trimmed.
+func SplitTextByLines(text string, maxLength int) []string {
+	if maxLength < utf8.UTFMax {
+		panic("max length is too small")
+	}
+
+	if len(text) <= maxLength {
+		return []string{text}
+	}
+
+	var chunks []string
+	var sb strings.Builder
+	flush := func() {
+		if chunk := strings.Trim(sb.String(), "\n"); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		sb.Reset()
+	}
+
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			if sb.Len()+1 > maxLength {
+				flush()
+			}
+			sb.WriteString("\n")
+		}
+
+		for len(line) > 0 {
+			available := maxLength - sb.Len()
+			if len(line) <= available {
+				sb.WriteString(line)
+				break
+			}
+
+			if sb.Len() > 0 && strings.TrimLeft(sb.String(), "\n") != "" {
+				flush()
+				continue
+			}
+
+			cut := available
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			sb.WriteString(line[:cut])
+			line = line[cut:]
+			flush()
+		}
+	}
+	flush()
+
+	return chunks
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestSplitTextByLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		maxLength int
+		want      []string
+	}{
+		{
+			name:      "short text is kept as is",
+			text:      "line 1\n\nline 2",
+			maxLength: 100,
+			want:      []string{"line 1\n\nline 2"},
+		},
+		{
+			name:      "split at line breaks",
+			text:      "line 1\nline 2\nline 3",
+			maxLength: 14,
+			want:      []string{"line 1\nline 2", "line 3"},
+		},
+		{
+			name:      "line breaks surrounding chunk are trimmed",
+			text:      "line 1\n\nline 2\n\nline 3",
+			maxLength: 10,
+			want:      []string{"line 1", "line 2", "line 3"},
+		},
+		{
+			name:      "long line is split",
+			text:      "ab\n" + strings.Repeat("x", 12) + "\ncd",
+			maxLength: 5,
+			want:      []string{"ab", "xxxxx", "xxxxx", "xx\ncd"},
+		},
+		{
+			name:      "long line is split at rune boundary",
+			text:      strings.Repeat("ä", 5),
+			maxLength: 5,
+			want:      []string{"ää", "ää", "ä"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitTextByLines(tt.text, tt.maxLength)
+			require.Equal(t, tt.want, got)
+			for _, chunk := range got {
+				require.LessOrEqual(t, len(chunk), tt.maxLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/pkg/errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TLSCertificateInfo holds the information of the certificate chain presented by a TLS server
+type TLSCertificateInfo struct {
+	Subject  string
+	Issuer   string
+	NotAfter time.Time // the earliest expiry among the certificates of the chain
+	// VerifyErr is not nil when the certificate chain is untrusted or not valid for the hostname
+	VerifyErr error
+}
+
+// IsHttpsEndpoint returns true if the endpoint uses TLS, based on the scheme
+func IsHttpsEndpoint(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "https://") || strings.HasPrefix(endpoint, "wss://") || strings.HasPrefix(endpoint, "grpcs://")
+}
+
+// InspectTLSCertificate performs TLS handshake with the endpoint and inspects the peer certificate chain.
+// Verification is done separately so the certificate information is still returned when the chain is untrusted.
+// If rootCAs is nil, the system root CAs will be used.
+func InspectTLSCertificate(endpoint string, rootCAs *x509.CertPool, timeout time.Duration) (*TLSCertificateInfo, error) {
+	hostPort, hostname, err := getTLSHostPort(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+	}
+	conn, err := tls.DialWithDialer(dialer, "tcp", hostPort, &tls.Config{
+		ServerName:         hostname,
+		InsecureSkipVerify: true, //nolint:gosec // verification is done below, to be able to inspect untrusted chains
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to perform TLS handshake with %s", hostPort)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	peerCertificates := conn.ConnectionState().PeerCertificates
+	if len(peerCertificates) == 0 {
+		return nil, fmt.Errorf("no peer certificate presented by %s", hostPort)
+	}
+
+	leaf := peerCertificates[0]
+	info := &TLSCertificateInfo{
+		Subject:  leaf.Subject.String(),
+		Issuer:   leaf.Issuer.String(),
+		NotAfter: leaf.NotAfter,
+	}
+	for _, certificate := range peerCertificates[1:] {
+		if certificate.NotAfter.Before(info.NotAfter) {
+			info.NotAfter = certificate.NotAfter
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range peerCertificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+	_, info.VerifyErr = leaf.Verify(x509.VerifyOptions{
+		DNSName:       hostname,
+		Roots:         rootCAs,
+		Intermediates: intermediates,
+	})
+
+	return info, nil
+}
+
+func getTLSHostPort(endpoint string) (hostPort string, hostname string, err error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	parsedUrl, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to parse endpoint %s", endpoint)
+	}
+
+	hostname = parsedUrl.Hostname()
+	if hostname == "" {
+		return "", "", fmt.Errorf("missing host in endpoint %s", endpoint)
+	}
+
+	port := parsedUrl.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	return net.JoinHostPort(hostname, port), hostname, nil
+}
+
+// ParseDurationWithDays parses duration like time.ParseDuration, with additional support of day unit, e.g. `14d`
+func ParseDurationWithDays(duration string) (time.Duration, error) {
+	if strings.HasSuffix(duration, "d") {
+		days, err := time.ParseDuration(strings.TrimSuffix(duration, "d") + "h")
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %s", duration)
+		}
+		return days * 24, nil
+	}
+
+	return time.ParseDuration(duration)
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"crypto/x509"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInspectTLSCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	trustedRoots := x509.NewCertPool()
+	trustedRoots.AddCert(server.Certificate())
+
+	t.Run("trusted", func(t *testing.T) {
+		info, err := InspectTLSCertificate(server.URL, trustedRoots, 5*time.Second)
+		require.NoError(t, err)
+		require.NoError(t, info.VerifyErr)
+		require.Equal(t, server.Certificate().NotAfter, info.NotAfter)
+	})
+
+	t.Run("untrusted chain", func(t *testing.T) {
+		info, err := InspectTLSCertificate(server.URL, x509.NewCertPool(), 5*time.Second)
+		require.NoError(t, err)
+		require.Error(t, info.VerifyErr)
+		require.ErrorAs(t, info.VerifyErr, &x509.UnknownAuthorityError{})
+	})
+
+	t.Run("hostname mismatch", func(t *testing.T) {
+		// certificate of the test server is issued for example.com and loopback IPs only
+		info, err := InspectTLSCertificate(strings.Replace(server.URL, "127.0.0.1", "localhost", 1), trustedRoots, 5*time.Second)
+		require.NoError(t, err)
+		require.Error(t, info.VerifyErr)
+		require.ErrorAs(t, info.VerifyErr, &x509.HostnameError{})
+	})
+
+	t.Run("not a TLS server", func(t *testing.T) {
+		plainServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer plainServer.Close()
+
+		_, err := InspectTLSCertificate(strings.Replace(plainServer.URL, "http://", "https://", 1), trustedRoots, 5*time.Second)
+		require.Error(t, err)
+	})
+
+}
+
+func TestParseDurationWithDays(t *testing.T) {
+	duration, err := ParseDurationWithDays("14d")
+	require.NoError(t, err)
+	require.Equal(t, 14*24*time.Hour, duration)
+
+	duration, err = ParseDurationWithDays("36h")
+	require.NoError(t, err)
+	require.Equal(t, 36*time.Hour, duration)
+
+	_, err = ParseDurationWithDays("xd")
+	require.Error(t, err)
+}
+
+func TestIsHttpsEndpoint(t *testing.T) {
+	require.True(t, IsHttpsEndpoint("https://rpc.example.com:443"))
+	require.True(t, IsHttpsEndpoint("grpcs://grpc.example.com:443"))
+	require.False(t, IsHttpsEndpoint("http://rpc.example.com:80"))
+	require.False(t, IsHttpsEndpoint("grpc.example.com:9090"))
+}
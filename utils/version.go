@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var regexpMajorMinorVersion = regexp.MustCompile(`^v?(\d+)\.(\d+)(\.\d+)?([-+].*)?$`)
+
+// ParseMajorMinorVersion parses the major and minor parts of a semantic version like `v0.47.5`, `0.50.1-rc.0` or `v0.46`
+func ParseMajorMinorVersion(version string) (major, minor int, success bool) {
+	matches := regexpMajorMinorVersion.FindStringSubmatch(version)
+	if len(matches) < 3 {
+		return 0, 0, false
+	}
+
+	var err error
+	major, err = strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err = strconv.Atoi(matches[2])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	success = true
+	return
+}
+
+// IsVersionAtLeast returns true if the version is greater than or equals to the provided major.minor
+func IsVersionAtLeast(version string, major, minor int) bool {
+	vMajor, vMinor, success := ParseMajorMinorVersion(version)
+	if !success {
+		return false
+	}
+	if vMajor != major {
+		return vMajor > major
+	}
+	return vMinor >= minor
+}
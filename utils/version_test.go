@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseMajorMinorVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		wantMajor   int
+		wantMinor   int
+		wantSuccess bool
+	}{
+		{
+			name:        "normal",
+			version:     "v0.47.5",
+			wantMajor:   0,
+			wantMinor:   47,
+			wantSuccess: true,
+		},
+		{
+			name:        "without v prefix",
+			version:     "0.50.1",
+			wantMajor:   0,
+			wantMinor:   50,
+			wantSuccess: true,
+		},
+		{
+			name:        "without patch",
+			version:     "v0.46",
+			wantMajor:   0,
+			wantMinor:   46,
+			wantSuccess: true,
+		},
+		{
+			name:        "pre-release",
+			version:     "v0.50.0-rc.1",
+			wantMajor:   0,
+			wantMinor:   50,
+			wantSuccess: true,
+		},
+		{
+			name:        "build metadata",
+			version:     "v0.46.15+evmos",
+			wantMajor:   0,
+			wantMinor:   46,
+			wantSuccess: true,
+		},
+		{
+			name:        "empty",
+			version:     "",
+			wantSuccess: false,
+		},
+		{
+			name:        "not a version",
+			version:     "latest",
+			wantSuccess: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMajor, gotMinor, gotSuccess := ParseMajorMinorVersion(tt.version)
+			require.Equal(t, tt.wantSuccess, gotSuccess)
+			require.Equal(t, tt.wantMajor, gotMajor)
+			require.Equal(t, tt.wantMinor, gotMinor)
+		})
+	}
+}
+
+func TestIsVersionAtLeast(t *testing.T) {
+	require.True(t, IsVersionAtLeast("v0.47.5", 0, 47))
+	require.True(t, IsVersionAtLeast("v0.50.1", 0, 47))
+	require.True(t, IsVersionAtLeast("v1.0.0", 0, 50))
+	require.False(t, IsVersionAtLeast("v0.46.16", 0, 47))
+	require.False(t, IsVersionAtLeast("invalid", 0, 0))
+}
@@ -0,0 +1,47 @@
+package health_check_worker
+
+import (
+	"sync"
+	"time"
+)
+
+var cacheChainProfileMutex sync.RWMutex
+var cacheChainQueryProfile map[string]chainQueryProfile
+
+// chainQueryProfile holds the detected versions of a chain, used to decide how to query and decode the responses.
+type chainQueryProfile struct {
+	SdkVersion string // version of Cosmos-SDK, from GetNodeInfo or config override
+	GovVersion string // v1 or v1beta1
+	DetectedAt time.Time
+}
+
+func putCacheChainQueryProfileWL(chainName string, profile chainQueryProfile) {
+	cacheChainProfileMutex.Lock()
+	defer cacheChainProfileMutex.Unlock()
+
+	cacheChainQueryProfile[chainName] = profile
+}
+
+func getCacheChainQueryProfileRL(chainName string) (chainQueryProfile, bool) {
+	cacheChainProfileMutex.RLock()
+	defer cacheChainProfileMutex.RUnlock()
+
+	profile, found := cacheChainQueryProfile[chainName]
+	return profile, found
+}
+
+func updateCacheChainGovVersionWL(chainName string, govVersion string) {
+	cacheChainProfileMutex.Lock()
+	defer cacheChainProfileMutex.Unlock()
+
+	profile, found := cacheChainQueryProfile[chainName]
+	if !found {
+		return
+	}
+	profile.GovVersion = govVersion
+	cacheChainQueryProfile[chainName] = profile
+}
+
+func init() {
+	cacheChainQueryProfile = make(map[string]chainQueryProfile)
+}
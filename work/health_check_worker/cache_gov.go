@@ -1,13 +1,37 @@
 package health_check_worker
 
 import (
+	"fmt"
+	lesvc "github.com/bcdevtools/validator-health-check/services/leader_election_svc"
+	"strings"
 	"sync"
 	"time"
 )
 
 var cacheGovMutex sync.RWMutex
 var lastCheckGovByChain map[string]time.Time
-var cacheVotedGov map[string]uint64
+var cacheGovVotes map[string]string                                 // chain/proposal/valoper -> vote option, only voted are cached
+var cacheGovSnapshotByChain map[string]GovSnapshot                  // chain name -> proposals on voting period and votes of the validators
+var cacheGovVoteEscalatedLeadTime map[string]govVoteEscalationState // chain/proposal/valoper -> escalation state
+
+// GovSnapshot holds the proposals on voting period of a chain and the votes of the validators, for /gov command
+type GovSnapshot struct {
+	Proposals []GovProposalSnapshot
+	UpdatedAt time.Time
+}
+
+type GovProposalSnapshot struct {
+	Id            uint64
+	Title         string
+	VotingEndTime *time.Time
+	Votes         map[string]string // valoper -> vote option like YES or "YES 0.7, NO 0.3", empty if not voted, not exists if unknown
+}
+
+// govVoteEscalationState holds the smallest lead time escalated for a proposal not voted by a validator
+type govVoteEscalationState struct {
+	VotingEndTime time.Time
+	LeadTime      time.Duration
+}
 
 func putCacheLastCheckGovByChainWL(chainName string) {
 	cacheGovMutex.Lock()
@@ -16,6 +40,14 @@ func putCacheLastCheckGovByChainWL(chainName string) {
 	lastCheckGovByChain[chainName] = time.Now().UTC()
 }
 
+// resetCacheLastCheckGovByChainWL forces the next health-check to check gov, e.g. when new proposal is seen via streaming
+func resetCacheLastCheckGovByChainWL(chainName string) {
+	cacheGovMutex.Lock()
+	defer cacheGovMutex.Unlock()
+
+	delete(lastCheckGovByChain, chainName)
+}
+
 func getLastCheckGovByChainRL(chainName string) time.Time {
 	cacheGovMutex.RLock()
 	defer cacheGovMutex.RUnlock()
@@ -24,28 +56,158 @@ func getLastCheckGovByChainRL(chainName string) time.Time {
 	return time
 }
 
-func putCacheVotedGovWL(valoper string, proposalId uint64) {
+// putCacheGovVoteWL caches the vote of the validator on the proposal, not queried again until the proposal ends voting period.
+// Votes changed after being cached are not reflected.
+func putCacheGovVoteWL(chainName string, proposalId uint64, valoper string, vote string) {
 	cacheGovMutex.Lock()
 	defer cacheGovMutex.Unlock()
 
-	if cachedValue, found := cacheVotedGov[valoper]; found && cachedValue >= proposalId {
-		return
+	cacheGovVotes[govVoteKey(chainName, proposalId, valoper)] = vote
+}
+
+// getCacheGovVoteRL returns the cached vote of the validator on the proposal, false if not voted or unknown
+func getCacheGovVoteRL(chainName string, proposalId uint64, valoper string) (string, bool) {
+	cacheGovMutex.RLock()
+	defer cacheGovMutex.RUnlock()
+
+	vote, found := cacheGovVotes[govVoteKey(chainName, proposalId, valoper)]
+	return vote, found
+}
+
+// pruneCacheGovVotesWL removes the cached votes on the proposals of the chain which are no longer on voting period
+func pruneCacheGovVotesWL(chainName string, proposalsOnVotingPeriod []uint64) {
+	cacheGovMutex.Lock()
+	defer cacheGovMutex.Unlock()
+
+	onVotingPeriod := make(map[string]bool, len(proposalsOnVotingPeriod))
+	for _, proposalId := range proposalsOnVotingPeriod {
+		onVotingPeriod[fmt.Sprintf("%s/%d/", chainName, proposalId)] = true
+	}
+
+	for key := range cacheGovVotes {
+		if !strings.HasPrefix(key, chainName+"/") {
+			continue
+		}
+		if onVotingPeriod[key[:strings.LastIndex(key, "/")+1]] {
+			continue
+		}
+		delete(cacheGovVotes, key)
 	}
-	cacheVotedGov[valoper] = proposalId
 }
 
-func isVotedGovLessThan(valoper string, proposalId uint64) bool {
+func putCacheGovSnapshotWL(chainName string, snapshot GovSnapshot) {
+	cacheGovMutex.Lock()
+	defer cacheGovMutex.Unlock()
+
+	cacheGovSnapshotByChain[chainName] = snapshot
+}
+
+// GetCacheGovSnapshotRL returns the proposals on voting period of the chain and the votes of the validators
+func GetCacheGovSnapshotRL(chainName string) (GovSnapshot, bool) {
+	cacheGovMutex.RLock()
+	defer cacheGovMutex.RUnlock()
+
+	snapshot, found := cacheGovSnapshotByChain[chainName]
+	return snapshot, found
+}
+
+// isGovVoteEscalationDueRL returns true if any proposal not voted by a validator has reached a lead time not escalated yet.
+// Used to check gov sooner than the regular interval as voting end approaches. Lead times must be sorted descending.
+func isGovVoteEscalationDueRL(chainName string, leadTimes []time.Duration, now time.Time) bool {
 	cacheGovMutex.RLock()
 	defer cacheGovMutex.RUnlock()
 
-	cachedValue, found := cacheVotedGov[valoper]
+	snapshot, found := cacheGovSnapshotByChain[chainName]
 	if !found {
-		return true
+		return false
+	}
+
+	for _, proposal := range snapshot.Proposals {
+		if proposal.VotingEndTime == nil {
+			continue
+		}
+		leadTime, reached := getGovVoteEscalationLeadTime(*proposal.VotingEndTime, leadTimes, now)
+		if !reached {
+			continue
+		}
+		for valoper, vote := range proposal.Votes {
+			if vote != "" {
+				continue
+			}
+			state, found := cacheGovVoteEscalatedLeadTime[govVoteKey(chainName, proposal.Id, valoper)]
+			if !found || state.LeadTime > leadTime {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// shouldEscalateGovVoteWL returns the lead time reached by the remaining voting period of the proposal,
+// if it was not escalated yet for the validator. Lead times must be sorted descending.
+// Nothing is recorded on standby instance, so the new leader escalates right after taking over.
+func shouldEscalateGovVoteWL(chainName string, proposalId uint64, valoper string, votingEndTime time.Time, leadTimes []time.Duration, now time.Time) (leadTime time.Duration, escalate bool) {
+	if !lesvc.IsLeaderRL() {
+		return 0, false
+	}
+
+	cacheGovMutex.Lock()
+	defer cacheGovMutex.Unlock()
+
+	leadTime, escalate = getGovVoteEscalationLeadTime(votingEndTime, leadTimes, now)
+	if !escalate {
+		return
 	}
-	return cachedValue < proposalId
+
+	key := govVoteKey(chainName, proposalId, valoper)
+	state, found := cacheGovVoteEscalatedLeadTime[key]
+	if found && state.VotingEndTime.Equal(votingEndTime) && state.LeadTime <= leadTime {
+		return 0, false
+	}
+
+	cacheGovVoteEscalatedLeadTime[key] = govVoteEscalationState{
+		VotingEndTime: votingEndTime,
+		LeadTime:      leadTime,
+	}
+	return
+}
+
+// pruneGovVoteEscalationStatesWL removes the escalation states of the proposals ended voting period
+func pruneGovVoteEscalationStatesWL(now time.Time) {
+	cacheGovMutex.Lock()
+	defer cacheGovMutex.Unlock()
+
+	for key, state := range cacheGovVoteEscalatedLeadTime {
+		if state.VotingEndTime.Before(now) {
+			delete(cacheGovVoteEscalatedLeadTime, key)
+		}
+	}
+}
+
+// getGovVoteEscalationLeadTime returns the smallest lead time reached by the remaining voting period
+func getGovVoteEscalationLeadTime(votingEndTime time.Time, leadTimes []time.Duration, now time.Time) (leadTime time.Duration, reached bool) {
+	remaining := votingEndTime.Sub(now)
+	if remaining <= 0 {
+		return 0, false
+	}
+	for _, lt := range leadTimes {
+		if remaining <= lt {
+			leadTime = lt
+			reached = true
+		}
+	}
+	return
+}
+
+// govVoteKey is the key of the vote of the validator on the proposal of the chain
+func govVoteKey(chainName string, proposalId uint64, valoper string) string {
+	return fmt.Sprintf("%s/%d/%s", chainName, proposalId, valoper)
 }
 
 func init() {
 	lastCheckGovByChain = make(map[string]time.Time)
-	cacheVotedGov = make(map[string]uint64)
+	cacheGovVotes = make(map[string]string)
+	cacheGovSnapshotByChain = make(map[string]GovSnapshot)
+	cacheGovVoteEscalatedLeadTime = make(map[string]govVoteEscalationState)
 }
@@ -0,0 +1,72 @@
+package health_check_worker
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_shouldEscalateGovVoteWL(t *testing.T) {
+	const chainName = "chain_gov_escalation"
+	const valoper = "valoper_gov_escalation"
+	leadTimes := []time.Duration{48 * time.Hour, 12 * time.Hour, 2 * time.Hour}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	votingEndTime := now.Add(72 * time.Hour)
+
+	_, escalate := shouldEscalateGovVoteWL(chainName, 1, valoper, votingEndTime, leadTimes, now)
+	require.False(t, escalate, "not yet reached any lead time")
+
+	putCacheGovSnapshotWL(chainName, GovSnapshot{
+		Proposals: []GovProposalSnapshot{{
+			Id:            1,
+			VotingEndTime: &votingEndTime,
+			Votes:         map[string]string{valoper: "", "voted": "YES"},
+		}},
+	})
+	require.False(t, isGovVoteEscalationDueRL(chainName, leadTimes, now))
+	require.True(t, isGovVoteEscalationDueRL(chainName, leadTimes, now.Add(25*time.Hour)))
+
+	leadTime, escalate := shouldEscalateGovVoteWL(chainName, 1, valoper, votingEndTime, leadTimes, now.Add(25*time.Hour))
+	require.True(t, escalate)
+	require.Equal(t, 48*time.Hour, leadTime)
+	require.False(t, isGovVoteEscalationDueRL(chainName, leadTimes, now.Add(25*time.Hour)))
+
+	_, escalate = shouldEscalateGovVoteWL(chainName, 1, valoper, votingEndTime, leadTimes, now.Add(26*time.Hour))
+	require.False(t, escalate, "same lead time must not be escalated twice")
+
+	leadTime, escalate = shouldEscalateGovVoteWL(chainName, 1, valoper, votingEndTime, leadTimes, now.Add(71*time.Hour))
+	require.True(t, escalate)
+	require.Equal(t, 2*time.Hour, leadTime)
+
+	_, escalate = shouldEscalateGovVoteWL(chainName, 1, valoper, votingEndTime, leadTimes, now.Add(73*time.Hour))
+	require.False(t, escalate, "voting period ended")
+
+	pruneGovVoteEscalationStatesWL(now.Add(73 * time.Hour))
+	require.Empty(t, cacheGovVoteEscalatedLeadTime)
+}
+
+func Test_pruneCacheGovVotesWL(t *testing.T) {
+	const chainName = "chain_gov_votes"
+	const otherChainName = "chain_gov_votes_other"
+	const valoper = "valoper_gov_votes"
+
+	putCacheGovVoteWL(chainName, 101, valoper, "YES")
+	putCacheGovVoteWL(chainName, 102, valoper, "NO")
+	putCacheGovVoteWL(otherChainName, 101, valoper, "ABSTAIN")
+
+	vote, found := getCacheGovVoteRL(chainName, 102, valoper)
+	require.True(t, found)
+	require.Equal(t, "NO", vote)
+
+	_, found = getCacheGovVoteRL(chainName, 103, valoper)
+	require.False(t, found, "votes are tracked per proposal")
+
+	pruneCacheGovVotesWL(chainName, []uint64{102})
+
+	_, found = getCacheGovVoteRL(chainName, 101, valoper)
+	require.False(t, found, "proposal no longer on voting period must be pruned")
+	_, found = getCacheGovVoteRL(chainName, 102, valoper)
+	require.True(t, found)
+	_, found = getCacheGovVoteRL(otherChainName, 101, valoper)
+	require.True(t, found, "other chain must not be pruned")
+}
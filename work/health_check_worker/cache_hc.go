@@ -10,6 +10,7 @@ var cacheHcMutex sync.RWMutex
 var cacheValidatorHealthCheck map[string]CacheValidatorHealthCheck
 
 type CacheValidatorHealthCheck struct {
+	ChainName                        string
 	Valoper                          string
 	Valcons                          string
 	Moniker                          string
@@ -22,6 +23,16 @@ type CacheValidatorHealthCheck struct {
 	DowntimeSlashingWhenMissedExcess *int64
 	Uptime                           *float64
 
+	// direct health-check via the optional health-check RPC of the validator
+	NodeLatestHeight  *int64
+	NodeLagBlocks     *int64
+	NodeCatchingUp    *bool
+	NodeInboundPeers  *int
+	NodeOutboundPeers *int
+	NodeVersion       string
+	NodeAppVersion    string
+	NodeError         string // finding of the direct health-check, empty if healthy
+
 	TimeOccurs time.Time
 }
 
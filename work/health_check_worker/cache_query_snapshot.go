@@ -0,0 +1,85 @@
+package health_check_worker
+
+import (
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"sync"
+	"time"
+)
+
+var cacheQuerySnapshotMutex sync.RWMutex
+var cacheChainQuerySnapshot map[string]ChainQuerySnapshot
+
+// slashingParamsCacheDuration is the duration to reuse slashing params, regardless of height
+const slashingParamsCacheDuration = time.Hour
+
+// ChainQuerySnapshot holds the queried state of a chain at a height.
+// Health-check passes at the same or a few blocks later height reuse it instead of querying again,
+// other components like Telegram commands can read it via GetChainQuerySnapshotRL.
+//
+// The snapshot is immutable once stored, do not modify its slices and maps.
+type ChainQuerySnapshot struct {
+	ChainName string
+	Height    int64 // latest block height at the time of querying
+	QueriedAt time.Time
+
+	Validators []stakingtypes.Validator // all validators, sorted by rank: bonded first, then by tokens descending
+
+	SigningInfos map[string]slashingtypes.ValidatorSigningInfo // valcons -> signing info, of watched validators only
+
+	SlashingParams          *slashingtypes.Params
+	SlashingParamsQueriedAt time.Time
+
+	Invalidated bool // relevant events streamed after queried, eg: jailing, validator updates
+}
+
+// IsReusableAt returns true if the snapshot was queried at the given height or up to reuseBlocks blocks before,
+// and not invalidated by the streamed events.
+func (s ChainQuerySnapshot) IsReusableAt(height int64, reuseBlocks int64) bool {
+	return s.Height > 0 && !s.Invalidated && height >= s.Height && height-s.Height <= reuseBlocks
+}
+
+// GetValidator returns the validator and its rank (1-based) in the snapshot
+func (s ChainQuerySnapshot) GetValidator(valoper string) (validator stakingtypes.Validator, rank int, found bool) {
+	for i, v := range s.Validators {
+		if v.OperatorAddress == valoper {
+			return v, i + 1, true
+		}
+	}
+	return stakingtypes.Validator{}, 0, false
+}
+
+func putCacheChainQuerySnapshotWL(snapshot ChainQuerySnapshot) {
+	cacheQuerySnapshotMutex.Lock()
+	defer cacheQuerySnapshotMutex.Unlock()
+
+	cacheChainQuerySnapshot[snapshot.ChainName] = snapshot
+}
+
+// invalidateChainQuerySnapshotWL prevents the query snapshot of the chain from being reused,
+// slashing params are still reused.
+func invalidateChainQuerySnapshotWL(chainName string) {
+	cacheQuerySnapshotMutex.Lock()
+	defer cacheQuerySnapshotMutex.Unlock()
+
+	snapshot, found := cacheChainQuerySnapshot[chainName]
+	if !found {
+		return
+	}
+
+	snapshot.Invalidated = true
+	cacheChainQuerySnapshot[chainName] = snapshot
+}
+
+// GetChainQuerySnapshotRL returns the latest query snapshot of the chain
+func GetChainQuerySnapshotRL(chainName string) (ChainQuerySnapshot, bool) {
+	cacheQuerySnapshotMutex.RLock()
+	defer cacheQuerySnapshotMutex.RUnlock()
+
+	snapshot, found := cacheChainQuerySnapshot[chainName]
+	return snapshot, found
+}
+
+func init() {
+	cacheChainQuerySnapshot = make(map[string]ChainQuerySnapshot)
+}
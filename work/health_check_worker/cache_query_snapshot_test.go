@@ -0,0 +1,48 @@
+package health_check_worker
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestChainQuerySnapshot_IsReusableAt(t *testing.T) {
+	snapshot := ChainQuerySnapshot{
+		Height: 100,
+	}
+
+	require.True(t, snapshot.IsReusableAt(100, 1), "same height")
+	require.True(t, snapshot.IsReusableAt(101, 1), "adjacent height")
+	require.False(t, snapshot.IsReusableAt(102, 1))
+	require.False(t, snapshot.IsReusableAt(99, 1), "chain rolled back or RPC lagging")
+	require.False(t, ChainQuerySnapshot{}.IsReusableAt(0, 1), "empty snapshot")
+
+	require.True(t, snapshot.IsReusableAt(100, 0), "same height only")
+	require.False(t, snapshot.IsReusableAt(101, 0), "same height only")
+	require.True(t, snapshot.IsReusableAt(103, 3))
+	require.False(t, snapshot.IsReusableAt(104, 3))
+
+	snapshot.Invalidated = true
+	require.False(t, snapshot.IsReusableAt(100, 1), "invalidated by streamed events")
+}
+
+func Test_invalidateChainQuerySnapshotWL(t *testing.T) {
+	const chainName = "test-invalidate-snapshot"
+	putCacheChainQuerySnapshotWL(ChainQuerySnapshot{
+		ChainName: chainName,
+		Height:    100,
+	})
+
+	snapshot, found := GetChainQuerySnapshotRL(chainName)
+	require.True(t, found)
+	require.True(t, snapshot.IsReusableAt(101, 1))
+
+	// eg: watched validator jailed in block 101
+	invalidateChainQuerySnapshotWL(chainName)
+	snapshot, found = GetChainQuerySnapshotRL(chainName)
+	require.True(t, found, "kept to reuse slashing params")
+	require.False(t, snapshot.IsReusableAt(101, 1))
+
+	invalidateChainQuerySnapshotWL("not-exists") // no-op
+	_, found = GetChainQuerySnapshotRL("not-exists")
+	require.False(t, found)
+}
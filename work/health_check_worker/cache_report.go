@@ -0,0 +1,303 @@
+package health_check_worker
+
+import (
+	"github.com/bcdevtools/validator-health-check/constants"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"sort"
+	"sync"
+	"time"
+)
+
+// history of health-check results, used to compose scheduled reports and to list the findings of on-demand health-check
+
+var cacheReportMutex sync.RWMutex
+var reportHistoryByValidator map[string]*validatorReportHistory // valoper -> history
+var reportHistoryByChain map[string]*chainReportHistory         // chain name -> history
+
+type validatorReportHistory struct {
+	ranks          []rankSample
+	jailEvents     []time.Time
+	missedGovVotes []missedGovVote
+	alerts         []alertEvent
+	jailed         bool // latest known jail status, to detect jail events
+}
+
+type chainReportHistory struct {
+	rpcChecks []rpcAvailabilitySample
+	alerts    []alertEvent // chain-level alerts
+}
+
+type rankSample struct {
+	at   time.Time
+	rank int
+}
+
+type missedGovVote struct {
+	at         time.Time // first seen not voted
+	proposalId uint64
+}
+
+type alertEvent struct {
+	at         time.Time
+	alertType  string
+	severity   tptypes.Severity
+	message    string
+	suppressed bool // not sent to anyone, eg: suppressed by spam prevention
+}
+
+type rpcAvailabilitySample struct {
+	at        time.Time
+	available bool
+}
+
+// validatorReportSummary is the summary of the health-check history of a validator within a period
+type validatorReportSummary struct {
+	rankAtStart        int // 0 if unknown
+	rankAtEnd          int // 0 if unknown
+	jailEvents         int
+	missedGovProposals []uint64
+	alerts             map[string]alertSummary // alert type -> summary
+}
+
+// chainReportSummary is the summary of the health-check history of a chain within a period
+type chainReportSummary struct {
+	rpcChecks    int
+	rpcAvailable int
+	alerts       map[string]alertSummary // alert type -> summary
+}
+
+type alertSummary struct {
+	count           int
+	highestSeverity tptypes.Severity
+}
+
+func getOrCreateValidatorReportHistory(valoper string) *validatorReportHistory {
+	history, found := reportHistoryByValidator[valoper]
+	if !found {
+		history = &validatorReportHistory{}
+		reportHistoryByValidator[valoper] = history
+	}
+	return history
+}
+
+func getOrCreateChainReportHistory(chainName string) *chainReportHistory {
+	history, found := reportHistoryByChain[chainName]
+	if !found {
+		history = &chainReportHistory{}
+		reportHistoryByChain[chainName] = history
+	}
+	return history
+}
+
+// recordValidatorHealthCheckWL records rank and detects jail event from the health-check result of the validator
+func recordValidatorHealthCheckWL(cache CacheValidatorHealthCheck, now time.Time) {
+	cacheReportMutex.Lock()
+	defer cacheReportMutex.Unlock()
+
+	history := getOrCreateValidatorReportHistory(cache.Valoper)
+	if cache.Rank > 0 {
+		history.ranks = append(pruneReportHistory(history.ranks, now, func(sample rankSample) time.Time {
+			return sample.at
+		}), rankSample{at: now, rank: cache.Rank})
+	}
+
+	if cache.Jailed != nil { // unknown if signing info could not be fetched
+		if *cache.Jailed && !history.jailed {
+			history.jailEvents = append(pruneReportHistory(history.jailEvents, now, func(at time.Time) time.Time {
+				return at
+			}), now)
+		}
+		history.jailed = *cache.Jailed
+	}
+}
+
+// recordMissedGovVoteWL records the proposal on voting period which the validator has not voted yet
+func recordMissedGovVoteWL(valoper string, proposalId uint64, now time.Time) {
+	cacheReportMutex.Lock()
+	defer cacheReportMutex.Unlock()
+
+	history := getOrCreateValidatorReportHistory(valoper)
+	for _, missed := range history.missedGovVotes {
+		if missed.proposalId == proposalId {
+			return
+		}
+	}
+	history.missedGovVotes = append(pruneReportHistory(history.missedGovVotes, now, func(missed missedGovVote) time.Time {
+		return missed.at
+	}), missedGovVote{at: now, proposalId: proposalId})
+}
+
+// recordFindingWL records the finding of health-check, chain-level finding if valoper is empty.
+// Suppressed findings are not counted as alerts fired in the reports.
+func recordFindingWL(chainName, valoper, alertType string, severity tptypes.Severity, message string, suppressed bool, now time.Time) {
+	cacheReportMutex.Lock()
+	defer cacheReportMutex.Unlock()
+
+	event := alertEvent{at: now, alertType: alertType, severity: severity, message: message, suppressed: suppressed}
+	getAt := func(event alertEvent) time.Time {
+		return event.at
+	}
+
+	if valoper == "" {
+		history := getOrCreateChainReportHistory(chainName)
+		history.alerts = append(pruneReportHistory(history.alerts, now, getAt), event)
+	} else {
+		history := getOrCreateValidatorReportHistory(valoper)
+		history.alerts = append(pruneReportHistory(history.alerts, now, getAt), event)
+	}
+}
+
+// recordRpcAvailabilityWL records whether any RPC of the chain was available for the health-check
+func recordRpcAvailabilityWL(chainName string, available bool, now time.Time) {
+	cacheReportMutex.Lock()
+	defer cacheReportMutex.Unlock()
+
+	history := getOrCreateChainReportHistory(chainName)
+	history.rpcChecks = append(pruneReportHistory(history.rpcChecks, now, func(sample rpcAvailabilitySample) time.Time {
+		return sample.at
+	}), rpcAvailabilitySample{at: now, available: available})
+}
+
+// HealthCheckFinding is a finding of health-check
+type HealthCheckFinding struct {
+	AlertType  string
+	Severity   tptypes.Severity
+	Message    string
+	Time       time.Time
+	Suppressed bool // not sent to anyone, eg: suppressed by spam prevention
+}
+
+// GetFindingsSinceRL returns the findings since the given time, chain-level findings if valoper is empty.
+// Findings suppressed by spam prevention are included.
+func GetFindingsSinceRL(chainName, valoper string, since time.Time) []HealthCheckFinding {
+	cacheReportMutex.RLock()
+	defer cacheReportMutex.RUnlock()
+
+	var events []alertEvent
+	if valoper == "" {
+		if history, found := reportHistoryByChain[chainName]; found {
+			events = history.alerts
+		}
+	} else {
+		if history, found := reportHistoryByValidator[valoper]; found {
+			events = history.alerts
+		}
+	}
+
+	var findings []HealthCheckFinding
+	for _, event := range events {
+		if event.at.Before(since) {
+			continue
+		}
+		findings = append(findings, HealthCheckFinding{
+			AlertType:  event.alertType,
+			Severity:   event.severity,
+			Message:    event.message,
+			Time:       event.at,
+			Suppressed: event.suppressed,
+		})
+	}
+	return findings
+}
+
+func getValidatorReportSummaryRL(valoper string, since, until time.Time) validatorReportSummary {
+	cacheReportMutex.RLock()
+	defer cacheReportMutex.RUnlock()
+
+	summary := validatorReportSummary{
+		alerts: make(map[string]alertSummary),
+	}
+
+	history, found := reportHistoryByValidator[valoper]
+	if !found {
+		return summary
+	}
+
+	for _, sample := range history.ranks {
+		if !isWithinPeriod(sample.at, since, until) {
+			continue
+		}
+		if summary.rankAtStart == 0 {
+			summary.rankAtStart = sample.rank
+		}
+		summary.rankAtEnd = sample.rank
+	}
+	for _, at := range history.jailEvents {
+		if isWithinPeriod(at, since, until) {
+			summary.jailEvents++
+		}
+	}
+	for _, missed := range history.missedGovVotes {
+		if isWithinPeriod(missed.at, since, until) {
+			summary.missedGovProposals = append(summary.missedGovProposals, missed.proposalId)
+		}
+	}
+	sort.Slice(summary.missedGovProposals, func(i, j int) bool {
+		return summary.missedGovProposals[i] < summary.missedGovProposals[j]
+	})
+	for _, event := range history.alerts {
+		if isWithinPeriod(event.at, since, until) && !event.suppressed {
+			summary.alerts[event.alertType] = summary.alerts[event.alertType].add(event)
+		}
+	}
+
+	return summary
+}
+
+func getChainReportSummaryRL(chainName string, since, until time.Time) chainReportSummary {
+	cacheReportMutex.RLock()
+	defer cacheReportMutex.RUnlock()
+
+	summary := chainReportSummary{
+		alerts: make(map[string]alertSummary),
+	}
+
+	history, found := reportHistoryByChain[chainName]
+	if !found {
+		return summary
+	}
+
+	for _, sample := range history.rpcChecks {
+		if !isWithinPeriod(sample.at, since, until) {
+			continue
+		}
+		summary.rpcChecks++
+		if sample.available {
+			summary.rpcAvailable++
+		}
+	}
+	for _, event := range history.alerts {
+		if isWithinPeriod(event.at, since, until) && !event.suppressed {
+			summary.alerts[event.alertType] = summary.alerts[event.alertType].add(event)
+		}
+	}
+
+	return summary
+}
+
+func (s alertSummary) add(event alertEvent) alertSummary {
+	s.count++
+	if event.severity > s.highestSeverity {
+		s.highestSeverity = event.severity
+	}
+	return s
+}
+
+func isWithinPeriod(at, since, until time.Time) bool {
+	return !at.Before(since) && at.Before(until)
+}
+
+// pruneReportHistory removes the records older than the retention, records must be sorted by time
+func pruneReportHistory[T any](records []T, now time.Time, getAt func(T) time.Time) []T {
+	expiry := now.Add(-constants.REPORT_HISTORY_RETENTION)
+	var pruned int
+	for pruned < len(records) && getAt(records[pruned]).Before(expiry) {
+		pruned++
+	}
+	return records[pruned:]
+}
+
+func init() {
+	reportHistoryByValidator = make(map[string]*validatorReportHistory)
+	reportHistoryByChain = make(map[string]*chainReportHistory)
+}
@@ -0,0 +1,78 @@
+package health_check_worker
+
+import (
+	"sync"
+	"time"
+)
+
+var cacheManagedRpcMutex sync.RWMutex
+var cacheManagedRpcStatus map[string]map[string]ManagedRpcStatus // chain -> endpoint -> status
+
+// ManagedRpcStatus is the latest health-check result of a managed Tendermint RPC endpoint
+type ManagedRpcStatus struct {
+	Endpoint          string
+	LatestBlockHeight int64
+	LatestBlockTime   time.Time
+	Latency           time.Duration
+	Network           string
+	NetworkMismatch   bool
+	LastError         string
+	LastErrorTime     time.Time
+	LastCheckTime     time.Time
+}
+
+// putCacheManagedRpcStatusWL records the status reported by the managed RPC, the last error is kept
+func putCacheManagedRpcStatusWL(chainName, endpoint string, latestBlockHeight int64, latestBlockTime time.Time, latency time.Duration, network, chainId string, now time.Time) {
+	cacheManagedRpcMutex.Lock()
+	defer cacheManagedRpcMutex.Unlock()
+
+	status := getOrInitCacheManagedRpcStatus(chainName, endpoint)
+	status.LatestBlockHeight = latestBlockHeight
+	status.LatestBlockTime = latestBlockTime
+	status.Latency = latency
+	status.Network = network
+	status.NetworkMismatch = network != chainId
+	status.LastCheckTime = now
+	cacheManagedRpcStatus[chainName][endpoint] = status
+}
+
+// putCacheManagedRpcErrorWL records the error of the latest health-check of the managed RPC
+func putCacheManagedRpcErrorWL(chainName, endpoint string, err error, now time.Time) {
+	cacheManagedRpcMutex.Lock()
+	defer cacheManagedRpcMutex.Unlock()
+
+	status := getOrInitCacheManagedRpcStatus(chainName, endpoint)
+	status.LastError = err.Error()
+	status.LastErrorTime = now
+	status.LastCheckTime = now
+	cacheManagedRpcStatus[chainName][endpoint] = status
+}
+
+func getOrInitCacheManagedRpcStatus(chainName, endpoint string) ManagedRpcStatus {
+	statusByEndpoint, found := cacheManagedRpcStatus[chainName]
+	if !found {
+		statusByEndpoint = make(map[string]ManagedRpcStatus)
+		cacheManagedRpcStatus[chainName] = statusByEndpoint
+	}
+
+	status, found := statusByEndpoint[endpoint]
+	if !found {
+		status = ManagedRpcStatus{
+			Endpoint: endpoint,
+		}
+	}
+	return status
+}
+
+// GetCacheManagedRpcStatusRL returns the latest health-check result of the managed RPC of the chain
+func GetCacheManagedRpcStatusRL(chainName, endpoint string) (ManagedRpcStatus, bool) {
+	cacheManagedRpcMutex.RLock()
+	defer cacheManagedRpcMutex.RUnlock()
+
+	status, found := cacheManagedRpcStatus[chainName][endpoint]
+	return status, found
+}
+
+func init() {
+	cacheManagedRpcStatus = make(map[string]map[string]ManagedRpcStatus)
+}
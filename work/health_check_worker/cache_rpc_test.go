@@ -0,0 +1,37 @@
+package health_check_worker
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_putCacheManagedRpcStatusWL(t *testing.T) {
+	const chainName = "chain_managed_rpc"
+	const endpoint = "https://rpc.example.com"
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, found := GetCacheManagedRpcStatusRL(chainName, endpoint)
+	require.False(t, found)
+
+	putCacheManagedRpcErrorWL(chainName, endpoint, fmt.Errorf("connection refused"), now)
+	status, found := GetCacheManagedRpcStatusRL(chainName, endpoint)
+	require.True(t, found)
+	require.Equal(t, endpoint, status.Endpoint)
+	require.Equal(t, "connection refused", status.LastError)
+	require.Zero(t, status.LatestBlockHeight)
+
+	putCacheManagedRpcStatusWL(chainName, endpoint, 100, now, time.Second, "other-1", "chain-1", now.Add(time.Minute))
+	status, found = GetCacheManagedRpcStatusRL(chainName, endpoint)
+	require.True(t, found)
+	require.Equal(t, int64(100), status.LatestBlockHeight)
+	require.True(t, status.NetworkMismatch)
+	require.Equal(t, "connection refused", status.LastError, "last error must be kept")
+	require.Equal(t, now, status.LastErrorTime)
+	require.Equal(t, now.Add(time.Minute), status.LastCheckTime)
+
+	putCacheManagedRpcStatusWL(chainName, endpoint, 101, now, time.Second, "chain-1", "chain-1", now.Add(2*time.Minute))
+	status, _ = GetCacheManagedRpcStatusRL(chainName, endpoint)
+	require.False(t, status.NetworkMismatch)
+}
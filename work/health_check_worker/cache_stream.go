@@ -0,0 +1,61 @@
+package health_check_worker
+
+import (
+	"sync"
+	"time"
+)
+
+var cacheStreamMutex sync.RWMutex
+var cacheStreamedBlock map[string]streamedBlock
+var streamingChains map[string]bool
+
+// streamedBlock is the latest block received via websocket subscription
+type streamedBlock struct {
+	Height     int64
+	BlockTime  time.Time
+	ReceivedAt time.Time
+}
+
+func putCacheStreamedBlockWL(chainName string, height int64, blockTime time.Time) {
+	cacheStreamMutex.Lock()
+	defer cacheStreamMutex.Unlock()
+
+	cacheStreamedBlock[chainName] = streamedBlock{
+		Height:     height,
+		BlockTime:  blockTime,
+		ReceivedAt: time.Now().UTC(),
+	}
+}
+
+func getCacheStreamedBlockRL(chainName string) (streamedBlock, bool) {
+	cacheStreamMutex.RLock()
+	defer cacheStreamMutex.RUnlock()
+
+	block, found := cacheStreamedBlock[chainName]
+	return block, found
+}
+
+// markStreamingChainWL marks the chain as being streamed, returns false if it was already marked
+func markStreamingChainWL(chainName string) bool {
+	cacheStreamMutex.Lock()
+	defer cacheStreamMutex.Unlock()
+
+	if streamingChains[chainName] {
+		return false
+	}
+	streamingChains[chainName] = true
+	return true
+}
+
+func unmarkStreamingChainWL(chainName string) {
+	cacheStreamMutex.Lock()
+	defer cacheStreamMutex.Unlock()
+
+	delete(streamingChains, chainName)
+	delete(cacheStreamedBlock, chainName)
+}
+
+func init() {
+	cacheStreamedBlock = make(map[string]streamedBlock)
+	streamingChains = make(map[string]bool)
+}
@@ -0,0 +1,80 @@
+package health_check_worker
+
+import (
+	lesvc "github.com/bcdevtools/validator-health-check/services/leader_election_svc"
+	"sync"
+	"time"
+)
+
+// tlsCertExpiredRealertInterval is the interval to repeat the alert while the certificate served by an endpoint is expired
+const tlsCertExpiredRealertInterval = 6 * time.Hour
+
+var cacheTlsMutex sync.RWMutex
+var cacheTlsCertExpiryAlertedLeadTime map[string]tlsCertExpiryAlertState
+
+// tlsCertExpiryAlertState holds the smallest lead time alerted for the certificate currently served by an endpoint
+type tlsCertExpiryAlertState struct {
+	NotAfter  time.Time
+	LeadTime  time.Duration // zero when the certificate was alerted as expired
+	AlertedAt time.Time
+}
+
+// shouldAlertTlsCertExpiryWL returns the lead time reached by the remaining validity of the certificate,
+// if it was not alerted yet for the current certificate. Lead times must be sorted descending.
+// Once the certificate is expired, the returned lead time is zero and the alert is repeated every tlsCertExpiredRealertInterval.
+// The state is reset when the certificate is renewed (expiry changed).
+// Nothing is recorded on standby instance, so the new leader alerts right after taking over.
+func shouldAlertTlsCertExpiryWL(endpoint string, notAfter time.Time, leadTimes []time.Duration, now time.Time) (leadTime time.Duration, alert bool) {
+	if !lesvc.IsLeaderRL() {
+		return 0, false
+	}
+
+	cacheTlsMutex.Lock()
+	defer cacheTlsMutex.Unlock()
+
+	state, found := cacheTlsCertExpiryAlertedLeadTime[endpoint]
+	if found && !state.NotAfter.Equal(notAfter) {
+		delete(cacheTlsCertExpiryAlertedLeadTime, endpoint)
+		found = false
+	}
+
+	remaining := notAfter.Sub(now)
+	if remaining <= 0 {
+		if found && state.LeadTime == 0 && now.Sub(state.AlertedAt) < tlsCertExpiredRealertInterval {
+			return 0, false
+		}
+
+		cacheTlsCertExpiryAlertedLeadTime[endpoint] = tlsCertExpiryAlertState{
+			NotAfter:  notAfter,
+			LeadTime:  0,
+			AlertedAt: now,
+		}
+		return 0, true
+	}
+
+	for _, lt := range leadTimes {
+		if remaining <= lt {
+			leadTime = lt
+			alert = true
+		}
+	}
+
+	if !alert {
+		return
+	}
+
+	if found && state.LeadTime <= leadTime {
+		return 0, false
+	}
+
+	cacheTlsCertExpiryAlertedLeadTime[endpoint] = tlsCertExpiryAlertState{
+		NotAfter:  notAfter,
+		LeadTime:  leadTime,
+		AlertedAt: now,
+	}
+	return
+}
+
+func init() {
+	cacheTlsCertExpiryAlertedLeadTime = make(map[string]tlsCertExpiryAlertState)
+}
@@ -0,0 +1,78 @@
+package health_check_worker
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_shouldAlertTlsCertExpiryWL(t *testing.T) {
+	const endpoint = "https://rpc.example.com"
+	leadTimes := []time.Duration{14 * 24 * time.Hour, 3 * 24 * time.Hour, 24 * time.Hour}
+	now := time.Now().UTC()
+
+	_, alert := shouldAlertTlsCertExpiryWL(endpoint, now.Add(30*24*time.Hour), leadTimes, now)
+	require.False(t, alert, "not yet reached any lead time")
+
+	notAfter := now.Add(10 * 24 * time.Hour)
+	leadTime, alert := shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, now)
+	require.True(t, alert)
+	require.Equal(t, 14*24*time.Hour, leadTime)
+
+	_, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, now)
+	require.False(t, alert, "same lead time must not be alerted twice")
+
+	notAfter = now.Add(2 * 24 * time.Hour)
+	leadTime, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, now)
+	require.True(t, alert, "renewed certificate resets the state")
+	require.Equal(t, 3*24*time.Hour, leadTime)
+
+	leadTime, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter.Add(-36*time.Hour), leadTimes, now)
+	require.True(t, alert, "renewed certificate resets the state")
+	require.Equal(t, 24*time.Hour, leadTime)
+}
+
+func Test_shouldAlertTlsCertExpiryWL_sameCertificate(t *testing.T) {
+	const endpoint = "https://grpc.example.com"
+	leadTimes := []time.Duration{14 * 24 * time.Hour, 3 * 24 * time.Hour, 24 * time.Hour}
+	now := time.Now().UTC()
+	notAfter := now.Add(20 * 24 * time.Hour)
+
+	_, alert := shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, now)
+	require.False(t, alert)
+
+	// time passes, the same certificate reaches each lead time in turn
+	leadTime, alert := shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, notAfter.Add(-13*24*time.Hour))
+	require.True(t, alert)
+	require.Equal(t, 14*24*time.Hour, leadTime)
+
+	_, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, notAfter.Add(-5*24*time.Hour))
+	require.False(t, alert, "still within the same lead time")
+
+	leadTime, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, notAfter.Add(-2*24*time.Hour))
+	require.True(t, alert)
+	require.Equal(t, 3*24*time.Hour, leadTime)
+
+	leadTime, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, notAfter.Add(-time.Hour))
+	require.True(t, alert)
+	require.Equal(t, 24*time.Hour, leadTime)
+
+	_, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, notAfter.Add(-time.Minute))
+	require.False(t, alert)
+
+	// expired, alerted then repeated on interval
+	expiredAt := notAfter.Add(time.Minute)
+	leadTime, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, expiredAt)
+	require.True(t, alert, "expired must be alerted")
+	require.Zero(t, leadTime)
+
+	_, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, expiredAt.Add(tlsCertExpiredRealertInterval-time.Minute))
+	require.False(t, alert, "expired must not be alerted again before interval")
+
+	leadTime, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, expiredAt.Add(tlsCertExpiredRealertInterval))
+	require.True(t, alert, "expired must be alerted again after interval")
+	require.Zero(t, leadTime)
+
+	_, alert = shouldAlertTlsCertExpiryWL(endpoint, notAfter, leadTimes, expiredAt.Add(tlsCertExpiredRealertInterval+time.Hour))
+	require.False(t, alert)
+}
@@ -0,0 +1,184 @@
+package health_check_worker
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"fmt"
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/bcdevtools/validator-health-check/constants"
+	rpcreg "github.com/bcdevtools/validator-health-check/registry/rpc_client_registry"
+	"github.com/bcdevtools/validator-health-check/utils"
+	"github.com/pkg/errors"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"time"
+)
+
+// managedEndpointBlockTimeThreshold is the maximum age of the latest block of a managed endpoint before being reported
+const managedEndpointBlockTimeThreshold = 180 * time.Second
+
+// healthCheckManagedRPC health-checks a managed Tendermint RPC endpoint, returns error to be reported if any.
+// The result is cached to be inspected via Telegram.
+func healthCheckManagedRPC(ctx context.Context, chainName, chainId, managedRPC string, logger logging.Logger) (errorToReport error) {
+	defer func() {
+		if errorToReport != nil {
+			putCacheManagedRpcErrorWL(chainName, managedRPC, errorToReport, time.Now().UTC())
+		}
+	}()
+
+	rpcClient, err := rpcreg.GetRpcClientByEndpointWL(managedRPC, logger)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get RPC client to health-check managed RPC %s", managedRPC)
+	}
+
+	var latency time.Duration
+	resultStatus, err := utils.RetryWithContext(ctx, func() (*coretypes.ResultStatus, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		startTime := time.Now()
+		defer func() {
+			latency = time.Since(startTime)
+		}()
+		return rpcClient.GetWebsocketClient().Status(queryCtx)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get status for health-check managed RPC %s", managedRPC)
+	}
+
+	putCacheManagedRpcStatusWL(
+		chainName, managedRPC,
+		resultStatus.SyncInfo.LatestBlockHeight, resultStatus.SyncInfo.LatestBlockTime, latency,
+		resultStatus.NodeInfo.Network, chainId,
+		time.Now().UTC(),
+	)
+
+	if resultStatus.NodeInfo.Network != chainId {
+		return fmt.Errorf("managed RPC node is on network %s, expected %s, RPC %s", resultStatus.NodeInfo.Network, chainId, managedRPC)
+	}
+
+	if resultStatus.SyncInfo.CatchingUp {
+		return fmt.Errorf("managed RPC node is catching up, block %d, time %v, RPC %s", resultStatus.SyncInfo.LatestBlockHeight, resultStatus.SyncInfo.LatestBlockTime, managedRPC)
+	}
+
+	if diff := time.Since(resultStatus.SyncInfo.LatestBlockTime.UTC()); diff >= managedEndpointBlockTimeThreshold {
+		return fmt.Errorf("managed RPC node is out dated %s, time %v, server time %v, RPC %s", explainDuration(diff), resultStatus.SyncInfo.LatestBlockTime, time.Now().UTC(), managedRPC)
+	}
+
+	return nil
+}
+
+// healthCheckManagedEvmRPC health-checks a managed EVM JSON-RPC endpoint, returns error to be reported if any.
+// The EVM block number is compared with the Tendermint block height of the same chain.
+func healthCheckManagedEvmRPC(ctx context.Context, managedEvmRPC string, chainHeadHeight int64, logger logging.Logger) error {
+	evmRpcClient := rpcreg.GetEvmRpcClientByEndpointWL(managedEvmRPC, logger)
+
+	syncStatus, err := utils.RetryWithContext(ctx, func() (rpcreg.EvmSyncStatus, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		return evmRpcClient.Syncing(queryCtx)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get syncing status for health-check managed EVM RPC %s", managedEvmRPC)
+	}
+
+	if syncStatus.Syncing {
+		return fmt.Errorf("managed EVM RPC node is syncing, block %d/%d, EVM RPC %s", syncStatus.CurrentBlock, syncStatus.HighestBlock, managedEvmRPC)
+	}
+
+	evmBlockNumber, err := utils.RetryWithContext(ctx, func() (int64, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		return evmRpcClient.BlockNumber(queryCtx)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get block number for health-check managed EVM RPC %s", managedEvmRPC)
+	}
+
+	if lag := chainHeadHeight - evmBlockNumber; lag >= constants.INFORM_TELEGRAM_IF_MANAGED_ENDPOINT_LAG_BLOCKS {
+		return fmt.Errorf("managed EVM RPC node is lagging %d blocks behind, EVM block %d, Tendermint block %d, EVM RPC %s", lag, evmBlockNumber, chainHeadHeight, managedEvmRPC)
+	}
+
+	peerCount, err := utils.RetryWithContext(ctx, func() (int64, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		return evmRpcClient.PeerCount(queryCtx)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get peer count for health-check managed EVM RPC %s", managedEvmRPC)
+	}
+
+	if peerCount < 1 {
+		return fmt.Errorf("managed EVM RPC node has no peer, EVM RPC %s", managedEvmRPC)
+	}
+
+	return nil
+}
+
+// healthCheckManagedGrpc health-checks a managed gRPC endpoint, returns error to be reported if any.
+func healthCheckManagedGrpc(ctx context.Context, managedGrpc string, chainHeadHeight int64, logger logging.Logger) error {
+	grpcClient, err := rpcreg.GetGrpcClientByEndpointWL(managedGrpc, logger)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get gRPC client to health-check managed gRPC %s", managedGrpc)
+	}
+
+	type latestBlock struct {
+		height    int64
+		blockTime time.Time
+	}
+
+	block, err := utils.RetryWithContext(ctx, func() (latestBlock, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		height, blockTime, err := grpcClient.GetLatestBlock(queryCtx)
+		return latestBlock{height: height, blockTime: blockTime}, err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get latest block for health-check managed gRPC %s", managedGrpc)
+	}
+
+	return checkManagedEndpointFreshness("gRPC", managedGrpc, block.height, block.blockTime, chainHeadHeight)
+}
+
+// healthCheckManagedRest health-checks a managed REST (LCD) endpoint, returns error to be reported if any.
+func healthCheckManagedRest(ctx context.Context, managedRest string, chainHeadHeight int64, logger logging.Logger) error {
+	restClient := rpcreg.GetRestClientByEndpointWL(managedRest, logger)
+
+	type latestBlock struct {
+		height    int64
+		blockTime time.Time
+	}
+
+	block, err := utils.RetryWithContext(ctx, func() (latestBlock, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		height, blockTime, err := restClient.GetLatestBlock(queryCtx)
+		return latestBlock{height: height, blockTime: blockTime}, err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get latest block for health-check managed REST %s", managedRest)
+	}
+
+	return checkManagedEndpointFreshness("REST", managedRest, block.height, block.blockTime, chainHeadHeight)
+}
+
+func checkManagedEndpointFreshness(endpointTypeName, endpoint string, height int64, blockTime time.Time, chainHeadHeight int64) error {
+	if lag := chainHeadHeight - height; lag >= constants.INFORM_TELEGRAM_IF_MANAGED_ENDPOINT_LAG_BLOCKS {
+		return fmt.Errorf("managed %s node is lagging %d blocks behind, block %d, chain head %d, %s %s", endpointTypeName, lag, height, chainHeadHeight, endpointTypeName, endpoint)
+	}
+
+	if diff := time.Since(blockTime.UTC()); diff >= managedEndpointBlockTimeThreshold {
+		return fmt.Errorf("managed %s node is out dated %s, time %v, server time %v, %s %s", endpointTypeName, explainDuration(diff), blockTime, time.Now().UTC(), endpointTypeName, endpoint)
+	}
+
+	return nil
+}
+
+// managedEndpointSubject is the subject used to prevent spamming per managed endpoint of the chain
+func managedEndpointSubject(chainName, endpoint string) string {
+	return chainName + "/" + endpoint
+}
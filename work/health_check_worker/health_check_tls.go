@@ -0,0 +1,52 @@
+package health_check_worker
+
+import (
+	"crypto/x509"
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// tlsCertExpiryFatalLeadTime is the remaining validity of a TLS certificate from which the expiry alert is fatal
+const tlsCertExpiryFatalLeadTime = 24 * time.Hour
+
+// healthCheckManagedEndpointTLS inspects the TLS certificate of a managed HTTPS endpoint.
+// Returns the finding when the certificate is untrusted or not valid for the hostname,
+// and the finding when the certificate reached a configured lead time before expiry (each lead time is reported once per certificate),
+// or periodically while the certificate is expired.
+// Connection errors are ignored here, they are reported by the endpoint health-check itself.
+func healthCheckManagedEndpointTLS(endpoint string, leadTimes []time.Duration) (verifyFinding error, expiryFinding error, expiryFatal bool) {
+	info, err := utils.InspectTLSCertificate(endpoint, nil, 10*time.Second)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	now := time.Now().UTC()
+	expired := !now.Before(info.NotAfter)
+
+	if info.VerifyErr != nil {
+		var certificateInvalidError x509.CertificateInvalidError
+		if errors.As(info.VerifyErr, &certificateInvalidError) && certificateInvalidError.Reason == x509.Expired {
+			expired = true
+		} else {
+			verifyFinding = fmt.Errorf("TLS certificate of managed endpoint %s is invalid, subject: %s, issuer: %s, error: %s", endpoint, info.Subject, info.Issuer, info.VerifyErr.Error())
+		}
+	}
+
+	leadTime, alert := shouldAlertTlsCertExpiryWL(endpoint, info.NotAfter, leadTimes, now)
+	if !alert {
+		return
+	}
+
+	if expired {
+		expiryFinding = fmt.Errorf("TLS certificate of managed endpoint %s EXPIRED at %s, subject: %s", endpoint, info.NotAfter.UTC().Format(time.RFC3339), info.Subject)
+		expiryFatal = true
+		return
+	}
+
+	remaining := info.NotAfter.Sub(now)
+	expiryFinding = fmt.Errorf("TLS certificate of managed endpoint %s will expire in %s (at %s, alert lead time %s), subject: %s", endpoint, explainDuration(remaining), info.NotAfter.UTC().Format(time.RFC3339), explainDuration(leadTime), info.Subject)
+	expiryFatal = remaining <= tlsCertExpiryFatalLeadTime
+	return
+}
@@ -0,0 +1,166 @@
+package health_check_worker
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"fmt"
+	"github.com/EscanBE/go-lib/logging"
+	rpcreg "github.com/bcdevtools/validator-health-check/registry/rpc_client_registry"
+	"github.com/bcdevtools/validator-health-check/utils"
+	"github.com/pkg/errors"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"sort"
+)
+
+// nodeVersions holds the Tendermint/CometBFT version (from node_info) and the application version (from abci_info) of a node
+type nodeVersions struct {
+	NodeVersion string
+	AppVersion  string
+}
+
+// getNodeVersions returns the versions of the node
+func getNodeVersions(ctx context.Context, rpcClient rpcreg.RpcClient) (nodeVersions, error) {
+	resultStatus, err := utils.RetryWithContext(ctx, func() (*coretypes.ResultStatus, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		return rpcClient.GetWebsocketClient().Status(queryCtx)
+	})
+	if err != nil {
+		return nodeVersions{}, errors.Wrap(err, "failed to get status")
+	}
+
+	resultABCIInfo, err := utils.RetryWithContext(ctx, func() (*coretypes.ResultABCIInfo, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		return rpcClient.GetWebsocketClient().ABCIInfo(queryCtx)
+	})
+	if err != nil {
+		return nodeVersions{}, errors.Wrap(err, "failed to get abci info")
+	}
+
+	return nodeVersions{
+		NodeVersion: resultStatus.NodeInfo.Version,
+		AppVersion:  resultABCIInfo.Response.Version,
+	}, nil
+}
+
+// getNodePeersCount returns number of inbound and outbound peers of the node, from net_info
+func getNodePeersCount(ctx context.Context, rpcClient rpcreg.RpcClient) (inbound, outbound int, err error) {
+	resultNetInfo, err := utils.RetryWithContext(ctx, func() (*coretypes.ResultNetInfo, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		return rpcClient.GetWebsocketClient().NetInfo(queryCtx)
+	})
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get net info")
+	}
+
+	for _, peer := range resultNetInfo.Peers {
+		if peer.IsOutbound {
+			outbound++
+		} else {
+			inbound++
+		}
+	}
+
+	return
+}
+
+// getMajorityNodeVersions returns the versions used by the majority of the provided RPCs of the chain.
+// Empty version will be returned if there is no majority.
+func getMajorityNodeVersions(ctx context.Context, rpc []string, chainId string, logger logging.Logger) nodeVersions {
+	type rpcVersions struct {
+		versions nodeVersions
+		err      error
+	}
+
+	chanRpcVersions := make(chan rpcVersions, len(rpc))
+	for _, r := range rpc {
+		go func(r string) {
+			var result rpcVersions
+			defer func() {
+				if rec := recover(); rec != nil {
+					result.err = fmt.Errorf("panic: %v", rec)
+				}
+
+				chanRpcVersions <- result
+			}()
+
+			rpcClient, err := rpcreg.GetRpcClientByEndpointWL(r, logger)
+			if err != nil {
+				result.err = errors.Wrap(err, "failed to get RPC client")
+				return
+			}
+
+			resultStatus, err := utils.RetryWithContext(ctx, func() (*coretypes.ResultStatus, error) {
+				queryCtx, cancel := newQueryContext(ctx)
+				defer cancel()
+
+				return rpcClient.GetWebsocketClient().Status(queryCtx)
+			})
+			if err != nil {
+				result.err = errors.Wrap(err, "failed to get status")
+				return
+			}
+
+			if resultStatus.NodeInfo.Network != chainId {
+				result.err = fmt.Errorf("network mismatch, expected %s, got %s", chainId, resultStatus.NodeInfo.Network)
+				return
+			}
+
+			result.versions, result.err = getNodeVersions(ctx, rpcClient)
+		}(r)
+	}
+
+	var allNodeVersions, allAppVersions []string
+	for i := 0; i < len(rpc); i++ {
+		result := <-chanRpcVersions
+		if result.err != nil {
+			logger.Debug("failed to get versions of RPC", "chain", chainId, "error", result.err.Error())
+			continue
+		}
+
+		allNodeVersions = append(allNodeVersions, result.versions.NodeVersion)
+		allAppVersions = append(allAppVersions, result.versions.AppVersion)
+	}
+
+	var majority nodeVersions
+	majority.NodeVersion, _ = majorityOf(allNodeVersions)
+	majority.AppVersion, _ = majorityOf(allAppVersions)
+	return majority
+}
+
+// majorityOf returns the value which appears in more than half of the non-empty provided values
+func majorityOf(values []string) (string, bool) {
+	counter := make(map[string]int)
+	var total int
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		counter[value]++
+		total++
+	}
+
+	if total == 0 {
+		return "", false
+	}
+
+	distinctValues := make([]string, 0, len(counter))
+	for value := range counter {
+		distinctValues = append(distinctValues, value)
+	}
+	sort.Slice(distinctValues, func(i, j int) bool {
+		return counter[distinctValues[i]] > counter[distinctValues[j]]
+	})
+
+	mostCommon := distinctValues[0]
+	if counter[mostCommon]*2 <= total {
+		return "", false
+	}
+
+	return mostCommon, true
+}
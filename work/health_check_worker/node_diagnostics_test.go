@@ -0,0 +1,56 @@
+package health_check_worker
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_majorityOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []string
+		want      string
+		wantFound bool
+	}{
+		{
+			name:      "all the same",
+			values:    []string{"v1.0.0", "v1.0.0", "v1.0.0"},
+			want:      "v1.0.0",
+			wantFound: true,
+		},
+		{
+			name:      "majority",
+			values:    []string{"v1.0.0", "v2.0.0", "v2.0.0"},
+			want:      "v2.0.0",
+			wantFound: true,
+		},
+		{
+			name:      "empty values are ignored",
+			values:    []string{"", "", "v2.0.0"},
+			want:      "v2.0.0",
+			wantFound: true,
+		},
+		{
+			name:      "no majority when tie",
+			values:    []string{"v1.0.0", "v2.0.0"},
+			wantFound: false,
+		},
+		{
+			name:      "no majority when less than half",
+			values:    []string{"v1.0.0", "v2.0.0", "v2.0.0", "v3.0.0", "v4.0.0"},
+			wantFound: false,
+		},
+		{
+			name:      "empty",
+			values:    nil,
+			wantFound: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := majorityOf(tt.values)
+			require.Equal(t, tt.wantFound, found)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
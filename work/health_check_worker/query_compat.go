@@ -0,0 +1,161 @@
+package health_check_worker
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"fmt"
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	rpcreg "github.com/bcdevtools/validator-health-check/registry/rpc_client_registry"
+	"github.com/bcdevtools/validator-health-check/utils"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// redetectChainQueryProfileAfter is the duration after that the chain versions will be detected again, to catch chain upgrades
+const redetectChainQueryProfileAfter = 1 * time.Hour
+
+// getChainQueryProfile returns the cached query profile of the chain, detect if not yet or outdated.
+func getChainQueryProfile(ctx context.Context, querier rpcreg.RpcQuerier, registeredChainConfig chainreg.RegisteredChainConfig, logger logging.Logger) chainQueryProfile {
+	chainName := registeredChainConfig.GetChainName()
+
+	cachedProfile, found := getCacheChainQueryProfileRL(chainName)
+	if found && time.Since(cachedProfile.DetectedAt) < redetectChainQueryProfileAfter {
+		return cachedProfile
+	}
+
+	profile := detectChainQueryProfile(ctx, querier, registeredChainConfig.GetSdkVersionOverride(), registeredChainConfig.GetGovVersionOverride(), logger)
+	putCacheChainQueryProfileWL(chainName, profile)
+
+	logger.Info("detected chain query profile", "chain", chainName, "sdk", profile.SdkVersion, "gov", profile.GovVersion)
+
+	return profile
+}
+
+// detectChainQueryProfile detects the versions of the chain, config overrides take precedence over detection.
+func detectChainQueryProfile(ctx context.Context, querier rpcreg.RpcQuerier, sdkVersionOverride, govVersionOverride string, logger logging.Logger) chainQueryProfile {
+	profile := chainQueryProfile{
+		SdkVersion: sdkVersionOverride,
+		GovVersion: govVersionOverride,
+		DetectedAt: time.Now().UTC(),
+	}
+
+	if profile.SdkVersion == "" {
+		sdkVersion, err := getCosmosSdkVersion(ctx, querier)
+		if err != nil {
+			logger.Debug("failed to detect Cosmos-SDK version", "error", err.Error())
+		} else {
+			profile.SdkVersion = sdkVersion
+		}
+	}
+
+	if profile.GovVersion == "" {
+		profile.GovVersion = detectGovVersion(ctx, querier, profile.SdkVersion)
+	}
+
+	return profile
+}
+
+// detectGovVersion detects the gov module version supported by the chain.
+// Gov v1 was introduced in Cosmos-SDK v0.46, for unknown SDK version, probe the gov v1 Params query.
+func detectGovVersion(ctx context.Context, querier rpcreg.RpcQuerier, sdkVersion string) string {
+	if sdkVersion != "" {
+		if _, _, success := utils.ParseMajorMinorVersion(sdkVersion); success && !utils.IsVersionAtLeast(sdkVersion, 0, 46) {
+			return constants.GOV_VERSION_V1BETA1
+		}
+	}
+
+	if err := probeGovV1(ctx, querier); err != nil {
+		return constants.GOV_VERSION_V1BETA1
+	}
+
+	return constants.GOV_VERSION_V1
+}
+
+func getCosmosSdkVersion(ctx context.Context, querier rpcreg.RpcQuerier) (string, error) {
+	req := tmservice.GetNodeInfoRequest{}
+
+	bz, err := req.Marshal()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal request, weird!"))
+	}
+
+	getNodeInfoResponse, err := utils.RetryWithContext[*tmservice.GetNodeInfoResponse](ctx, func() (*tmservice.GetNodeInfoResponse, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		resultABCIQuery, err := querier.ABCIQuery(queryCtx, "/cosmos.base.tendermint.v1beta1.Service/GetNodeInfo", bz)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(resultABCIQuery.Response.Value) == 0 {
+			return nil, fmt.Errorf("empty response value, weird")
+		}
+
+		getNodeInfoResponse := &tmservice.GetNodeInfoResponse{}
+		err = getNodeInfoResponse.Unmarshal(resultABCIQuery.Response.Value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal response, weird!")
+		}
+
+		return getNodeInfoResponse, nil
+	})
+
+	if err != nil {
+		return "", errors.Wrap(err, "failed to query node info")
+	}
+
+	if getNodeInfoResponse.ApplicationVersion == nil || getNodeInfoResponse.ApplicationVersion.CosmosSdkVersion == "" {
+		return "", errors.New("cosmos-sdk version is not provided")
+	}
+
+	return getNodeInfoResponse.ApplicationVersion.CosmosSdkVersion, nil
+}
+
+func probeGovV1(ctx context.Context, querier rpcreg.RpcQuerier) error {
+	req := govv1.QueryParamsRequest{
+		ParamsType: govv1.ParamVoting,
+	}
+
+	bz, err := req.Marshal()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal request, weird!"))
+	}
+
+	_, err = utils.RetryWithContext[*govv1.QueryParamsResponse](ctx, func() (*govv1.QueryParamsResponse, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		resultABCIQuery, err := querier.ABCIQuery(queryCtx, "/cosmos.gov.v1.Query/Params", bz)
+		if err != nil {
+			return nil, err
+		}
+
+		if resultABCIQuery.Response.Code != 0 {
+			return nil, fmt.Errorf("query failed with code %d: %s", resultABCIQuery.Response.Code, resultABCIQuery.Response.Log)
+		}
+
+		if len(resultABCIQuery.Response.Value) == 0 {
+			return nil, fmt.Errorf("empty response value, weird")
+		}
+
+		queryParamsResponse := &govv1.QueryParamsResponse{}
+		err = queryParamsResponse.Unmarshal(resultABCIQuery.Response.Value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal response, weird!")
+		}
+
+		return queryParamsResponse, nil
+	}, utils.DefaultRetryOption().MinCount(1).MaxDuration(time.Second))
+
+	return err
+}
+
+// newQueryContext returns a context with per-query timeout, derived from the health-check context
+func newQueryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, constants.RPC_QUERY_TIMEOUT)
+}
@@ -0,0 +1,493 @@
+package health_check_worker
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	rpcreg "github.com/bcdevtools/validator-health-check/registry/rpc_client_registry"
+	"github.com/bcdevtools/validator-health-check/utils"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"strings"
+	"time"
+)
+
+// govProposalsPageLimit is the number of proposals queried per page when enumerating proposals on voting period
+const govProposalsPageLimit = 50
+
+// govProposal is the version-independent representation of a governance proposal, from either gov v1 or v1beta1
+type govProposal struct {
+	Id            uint64
+	Title         string // empty if not available
+	VotingEndTime *time.Time
+}
+
+// govVote is the version-independent representation of a vote, from either gov v1 or v1beta1
+type govVote struct {
+	Options []govWeightedVoteOption
+}
+
+type govWeightedVoteOption struct {
+	Option string // like YES, NO, ABSTAIN, NO_WITH_VETO
+	Weight string
+}
+
+// String describes the vote, like YES or "YES 0.7, NO 0.3" for weighted vote
+func (v govVote) String() string {
+	if len(v.Options) == 1 {
+		return v.Options[0].Option
+	}
+
+	options := make([]string, len(v.Options))
+	for i, option := range v.Options {
+		weight := option.Weight
+		if strings.Contains(weight, ".") {
+			weight = strings.TrimRight(strings.TrimRight(weight, "0"), ".")
+		}
+		options[i] = fmt.Sprintf("%s %s", option.Option, weight)
+	}
+	return strings.Join(options, ", ")
+}
+
+// govProposalsRequest is the version-independent representation of a query proposals request
+type govProposalsRequest struct {
+	OnVotingPeriod bool
+	Pagination     *query.PageRequest
+}
+
+// getGovProposalsOnVotingPeriod queries all the proposals on voting period, page by page
+func getGovProposalsOnVotingPeriod(ctx context.Context, querier rpcreg.RpcQuerier, registeredChainConfig chainreg.RegisteredChainConfig) ([]govProposal, error) {
+	var allProposals []govProposal
+	var nextKey []byte
+	for {
+		proposals, pageResponse, err := getGovProposalsPage(ctx, querier, registeredChainConfig, govProposalsRequest{
+			OnVotingPeriod: true,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: govProposalsPageLimit,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		allProposals = append(allProposals, proposals...)
+
+		if pageResponse == nil || len(pageResponse.NextKey) == 0 || len(proposals) == 0 {
+			break
+		}
+		nextKey = pageResponse.NextKey
+	}
+
+	return allProposals, nil
+}
+
+// getGovProposalsPage queries a page of proposals using the gov version detected for the chain,
+// fall back to the other gov version if the detected one does not work.
+func getGovProposalsPage(ctx context.Context, querier rpcreg.RpcQuerier, registeredChainConfig chainreg.RegisteredChainConfig, req govProposalsRequest) ([]govProposal, *query.PageResponse, error) {
+	type page struct {
+		proposals    []govProposal
+		pageResponse *query.PageResponse
+	}
+
+	result, err := queryGovWithVersionFallback(registeredChainConfig, func(govVersion string) (page, error) {
+		var res page
+		var err error
+		if govVersion == constants.GOV_VERSION_V1 {
+			res.proposals, res.pageResponse, err = getGovV1Proposals(ctx, querier, req)
+		} else {
+			res.proposals, res.pageResponse, err = getGovV1Beta1Proposals(ctx, querier, req)
+		}
+		return res, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result.proposals, result.pageResponse, nil
+}
+
+// getGovVote queries the vote of the voter on the proposal, nil if not voted
+func getGovVote(ctx context.Context, querier rpcreg.RpcQuerier, registeredChainConfig chainreg.RegisteredChainConfig, proposalId uint64, voter string) (*govVote, error) {
+	return queryGovWithVersionFallback(registeredChainConfig, func(govVersion string) (*govVote, error) {
+		if govVersion == constants.GOV_VERSION_V1 {
+			return getGovV1Vote(ctx, querier, proposalId, voter)
+		}
+		return getGovV1Beta1Vote(ctx, querier, proposalId, voter)
+	})
+}
+
+// queryGovWithVersionFallback queries using the gov version detected for the chain,
+// fall back to the other gov version if the detected one does not work.
+func queryGovWithVersionFallback[T any](registeredChainConfig chainreg.RegisteredChainConfig, queryFunc func(govVersion string) (T, error)) (T, error) {
+	chainName := registeredChainConfig.GetChainName()
+
+	preferredGovVersion := constants.GOV_VERSION_V1
+	if profile, found := getCacheChainQueryProfileRL(chainName); found && profile.GovVersion != "" {
+		preferredGovVersion = profile.GovVersion
+	}
+
+	govVersions := []string{preferredGovVersion}
+	if preferredGovVersion == constants.GOV_VERSION_V1 {
+		govVersions = append(govVersions, constants.GOV_VERSION_V1BETA1)
+	} else {
+		govVersions = append(govVersions, constants.GOV_VERSION_V1)
+	}
+
+	var firstErr error
+	for _, govVersion := range govVersions {
+		res, err := queryFunc(govVersion)
+		if err == nil {
+			if govVersion != preferredGovVersion && registeredChainConfig.GetGovVersionOverride() == "" {
+				updateCacheChainGovVersionWL(chainName, govVersion)
+			}
+			return res, nil
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	var empty T
+	return empty, firstErr
+}
+
+func getGovV1Proposals(ctx context.Context, querier rpcreg.RpcQuerier, req govProposalsRequest) ([]govProposal, *query.PageResponse, error) {
+	reqV1 := govv1.QueryProposalsRequest{
+		Pagination: req.Pagination,
+	}
+	if req.OnVotingPeriod {
+		reqV1.ProposalStatus = govv1.ProposalStatus_PROPOSAL_STATUS_VOTING_PERIOD
+	}
+
+	bz, err := reqV1.Marshal()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal request, weird!"))
+	}
+
+	var rawResponse []byte
+	queryGovV1ProposalsResponse, err := utils.RetryWithContext[*govv1.QueryProposalsResponse](ctx, func() (*govv1.QueryProposalsResponse, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		resultABCIQuery, err := querier.ABCIQuery(queryCtx, "/cosmos.gov.v1.Query/Proposals", bz)
+		if err != nil {
+			return nil, err
+		}
+
+		if resultABCIQuery.Response.Code != 0 {
+			return nil, fmt.Errorf("query failed with code %d: %s", resultABCIQuery.Response.Code, resultABCIQuery.Response.Log)
+		}
+
+		queryProposalsResponse := &govv1.QueryProposalsResponse{}
+		err = queryProposalsResponse.Unmarshal(resultABCIQuery.Response.Value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal response, weird!")
+		}
+
+		rawResponse = resultABCIQuery.Response.Value
+		return queryProposalsResponse, nil
+	})
+
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to query gov v1 proposals")
+	}
+
+	if queryGovV1ProposalsResponse == nil {
+		return nil, nil, errors.New("empty response, weird!")
+	}
+
+	titles := getGovV1ProposalTitles(rawResponse)
+
+	proposals := make([]govProposal, len(queryGovV1ProposalsResponse.Proposals))
+	for i, proposal := range queryGovV1ProposalsResponse.Proposals {
+		title, found := titles[proposal.Id]
+		if !found {
+			title = getTitleFromGovV1ProposalMetadata(proposal.Metadata)
+		}
+		proposals[i] = govProposal{
+			Id:            proposal.Id,
+			Title:         title,
+			VotingEndTime: proposal.VotingEndTime,
+		}
+	}
+
+	return proposals, queryGovV1ProposalsResponse.Pagination, nil
+}
+
+func getGovV1Beta1Proposals(ctx context.Context, querier rpcreg.RpcQuerier, req govProposalsRequest) ([]govProposal, *query.PageResponse, error) {
+	reqV1Beta1 := govv1beta1.QueryProposalsRequest{
+		Pagination: req.Pagination,
+	}
+	if req.OnVotingPeriod {
+		reqV1Beta1.ProposalStatus = govv1beta1.StatusVotingPeriod
+	}
+
+	bz, err := reqV1Beta1.Marshal()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal request, weird!"))
+	}
+
+	queryGovV1Beta1ProposalsResponse, err := utils.RetryWithContext[*govv1beta1.QueryProposalsResponse](ctx, func() (*govv1beta1.QueryProposalsResponse, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		resultABCIQuery, err := querier.ABCIQuery(queryCtx, "/cosmos.gov.v1beta1.Query/Proposals", bz)
+		if err != nil {
+			return nil, err
+		}
+
+		if resultABCIQuery.Response.Code != 0 {
+			return nil, fmt.Errorf("query failed with code %d: %s", resultABCIQuery.Response.Code, resultABCIQuery.Response.Log)
+		}
+
+		queryProposalsResponse := &govv1beta1.QueryProposalsResponse{}
+		err = queryProposalsResponse.Unmarshal(resultABCIQuery.Response.Value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal response, weird!")
+		}
+
+		return queryProposalsResponse, nil
+	})
+
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to query gov v1beta1 proposals")
+	}
+
+	if queryGovV1Beta1ProposalsResponse == nil {
+		return nil, nil, errors.New("empty response, weird!")
+	}
+
+	proposals := make([]govProposal, len(queryGovV1Beta1ProposalsResponse.Proposals))
+	for i, proposal := range queryGovV1Beta1ProposalsResponse.Proposals {
+		votingEndTime := proposal.VotingEndTime
+		var title string
+		if proposal.Content != nil {
+			// content types are not registered, all the proposal contents declare title as the first field
+			title = getProtoStringField(proposal.Content.Value, 1)
+		}
+		proposals[i] = govProposal{
+			Id:            proposal.ProposalId,
+			Title:         title,
+			VotingEndTime: &votingEndTime,
+		}
+	}
+
+	return proposals, queryGovV1Beta1ProposalsResponse.Pagination, nil
+}
+
+func getGovV1Vote(ctx context.Context, querier rpcreg.RpcQuerier, proposalId uint64, voter string) (*govVote, error) {
+	reqV1 := govv1.QueryVoteRequest{
+		ProposalId: proposalId,
+		Voter:      voter,
+	}
+
+	bz, err := reqV1.Marshal()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal request, weird!"))
+	}
+
+	queryGovV1VoteResponse, err := utils.RetryWithContext[*govv1.QueryVoteResponse](ctx, func() (*govv1.QueryVoteResponse, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		resultABCIQuery, err := querier.ABCIQuery(queryCtx, "/cosmos.gov.v1.Query/Vote", bz)
+		if err != nil {
+			return nil, err
+		}
+
+		if resultABCIQuery.Response.Code != 0 {
+			if isGovVoteNotFound(resultABCIQuery.Response.Log) {
+				return &govv1.QueryVoteResponse{}, nil
+			}
+			return nil, fmt.Errorf("query failed with code %d: %s", resultABCIQuery.Response.Code, resultABCIQuery.Response.Log)
+		}
+
+		queryVoteResponse := &govv1.QueryVoteResponse{}
+		err = queryVoteResponse.Unmarshal(resultABCIQuery.Response.Value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal response, weird!")
+		}
+
+		return queryVoteResponse, nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query gov v1 vote")
+	}
+
+	if queryGovV1VoteResponse == nil || queryGovV1VoteResponse.Vote == nil {
+		return nil, nil
+	}
+
+	vote := &govVote{}
+	for _, option := range queryGovV1VoteResponse.Vote.Options {
+		vote.Options = append(vote.Options, govWeightedVoteOption{
+			Option: strings.TrimPrefix(option.Option.String(), "VOTE_OPTION_"),
+			Weight: option.Weight,
+		})
+	}
+
+	return vote, nil
+}
+
+func getGovV1Beta1Vote(ctx context.Context, querier rpcreg.RpcQuerier, proposalId uint64, voter string) (*govVote, error) {
+	reqV1Beta1 := govv1beta1.QueryVoteRequest{
+		ProposalId: proposalId,
+		Voter:      voter,
+	}
+
+	bz, err := reqV1Beta1.Marshal()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal request, weird!"))
+	}
+
+	queryGovV1Beta1VoteResponse, err := utils.RetryWithContext[*govv1beta1.QueryVoteResponse](ctx, func() (*govv1beta1.QueryVoteResponse, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		resultABCIQuery, err := querier.ABCIQuery(queryCtx, "/cosmos.gov.v1beta1.Query/Vote", bz)
+		if err != nil {
+			return nil, err
+		}
+
+		if resultABCIQuery.Response.Code != 0 {
+			if isGovVoteNotFound(resultABCIQuery.Response.Log) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("query failed with code %d: %s", resultABCIQuery.Response.Code, resultABCIQuery.Response.Log)
+		}
+
+		queryVoteResponse := &govv1beta1.QueryVoteResponse{}
+		err = queryVoteResponse.Unmarshal(resultABCIQuery.Response.Value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal response, weird!")
+		}
+
+		return queryVoteResponse, nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query gov v1beta1 vote")
+	}
+
+	if queryGovV1Beta1VoteResponse == nil {
+		return nil, nil
+	}
+
+	vote := &govVote{}
+	for _, option := range queryGovV1Beta1VoteResponse.Vote.Options {
+		vote.Options = append(vote.Options, govWeightedVoteOption{
+			Option: strings.TrimPrefix(option.Option.String(), "VOTE_OPTION_"),
+			Weight: option.Weight.String(),
+		})
+	}
+	if len(vote.Options) == 0 && queryGovV1Beta1VoteResponse.Vote.Option != govv1beta1.OptionEmpty { //nolint:staticcheck
+		// votes submitted before weighted voting was introduced
+		vote.Options = append(vote.Options, govWeightedVoteOption{
+			Option: strings.TrimPrefix(queryGovV1Beta1VoteResponse.Vote.Option.String(), "VOTE_OPTION_"), //nolint:staticcheck
+			Weight: "1",
+		})
+	}
+	if len(vote.Options) == 0 {
+		return nil, nil
+	}
+
+	return vote, nil
+}
+
+// isGovVoteNotFound returns true if the error log of the vote query indicates that the voter has not voted
+func isGovVoteNotFound(log string) bool {
+	return strings.Contains(log, "not found")
+}
+
+// getGovV1ProposalTitles extracts the titles of proposals from the raw gov v1 QueryProposalsResponse.
+// Title was added to gov v1 Proposal (field 11) in Cosmos-SDK v0.47, it is not available in the types of the SDK version in use.
+func getGovV1ProposalTitles(bz []byte) map[uint64]string {
+	titles := make(map[uint64]string)
+	forEachProtoField(bz, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) {
+		if num != 1 || typ != protowire.BytesType { // proposals
+			return
+		}
+
+		var proposalId uint64
+		var title string
+		forEachProtoField(value, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) {
+			switch {
+			case num == 1 && typ == protowire.VarintType:
+				proposalId = varint
+			case num == 11 && typ == protowire.BytesType:
+				title = string(value)
+			}
+		})
+		if title != "" {
+			titles[proposalId] = title
+		}
+	})
+	return titles
+}
+
+// getTitleFromGovV1ProposalMetadata returns the title declared in the JSON metadata of the proposal, if any
+func getTitleFromGovV1ProposalMetadata(metadata string) string {
+	var jsonMetadata struct {
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal([]byte(metadata), &jsonMetadata); err != nil {
+		return ""
+	}
+	return jsonMetadata.Title
+}
+
+// getProtoStringField returns the first string field having the given number within the protobuf-encoded message
+func getProtoStringField(bz []byte, fieldNumber protowire.Number) string {
+	var found bool
+	var result string
+	forEachProtoField(bz, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) {
+		if !found && num == fieldNumber && typ == protowire.BytesType {
+			found = true
+			result = string(value)
+		}
+	})
+	return result
+}
+
+// forEachProtoField iterates the top-level fields of the protobuf-encoded message, stops at malformed input
+func forEachProtoField(bz []byte, callback func(num protowire.Number, typ protowire.Type, value []byte, varint uint64)) {
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return
+		}
+		bz = bz[n:]
+
+		switch typ {
+		case protowire.VarintType:
+			varint, n := protowire.ConsumeVarint(bz)
+			if n < 0 {
+				return
+			}
+			callback(num, typ, nil, varint)
+			bz = bz[n:]
+		case protowire.BytesType:
+			value, n := protowire.ConsumeBytes(bz)
+			if n < 0 {
+				return
+			}
+			callback(num, typ, value, 0)
+			bz = bz[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, bz)
+			if n < 0 {
+				return
+			}
+			bz = bz[n:]
+		}
+	}
+}
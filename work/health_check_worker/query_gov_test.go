@@ -0,0 +1,45 @@
+package health_check_worker
+
+import (
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"testing"
+)
+
+func Test_getGovV1ProposalTitles(t *testing.T) {
+	encodeProposal := func(id uint64, title string) []byte {
+		var bz []byte
+		bz = protowire.AppendTag(bz, 1, protowire.VarintType)
+		bz = protowire.AppendVarint(bz, id)
+		bz = protowire.AppendTag(bz, 10, protowire.BytesType)
+		bz = protowire.AppendString(bz, "metadata")
+		if title != "" {
+			bz = protowire.AppendTag(bz, 11, protowire.BytesType)
+			bz = protowire.AppendString(bz, title)
+		}
+		return bz
+	}
+
+	var response []byte
+	for _, proposal := range []struct {
+		id    uint64
+		title string
+	}{{101, "Upgrade v2"}, {102, ""}} {
+		response = protowire.AppendTag(response, 1, protowire.BytesType)
+		response = protowire.AppendBytes(response, encodeProposal(proposal.id, proposal.title))
+	}
+
+	require.Equal(t, map[uint64]string{101: "Upgrade v2"}, getGovV1ProposalTitles(response))
+	require.Empty(t, getGovV1ProposalTitles([]byte{0xff}), "malformed")
+
+	require.Equal(t, "Upgrade v3", getTitleFromGovV1ProposalMetadata(`{"title":"Upgrade v3","summary":"..."}`))
+	require.Empty(t, getTitleFromGovV1ProposalMetadata("ipfs://Qm..."))
+}
+
+func Test_govVote_String(t *testing.T) {
+	require.Equal(t, "YES", govVote{Options: []govWeightedVoteOption{{Option: "YES", Weight: "1.000000000000000000"}}}.String())
+	require.Equal(t, "YES 0.7, NO 0.3", govVote{Options: []govWeightedVoteOption{
+		{Option: "YES", Weight: "0.700000000000000000"},
+		{Option: "NO", Weight: "0.300000000000000000"},
+	}}.String())
+}
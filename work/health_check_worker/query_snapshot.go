@@ -0,0 +1,108 @@
+package health_check_worker
+
+import (
+	"context"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	rpcreg "github.com/bcdevtools/validator-health-check/registry/rpc_client_registry"
+	valaddreg "github.com/bcdevtools/validator-health-check/registry/validator_address_registry"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	"sort"
+	"time"
+)
+
+// getChainQuerySnapshot returns the query snapshot of the chain at the given height.
+// The cached snapshot is reused if it was queried at the same height or up to the configured number of blocks before,
+// and not invalidated by the streamed events, only missing data is queried.
+// Slashing params are cached for slashingParamsCacheDuration regardless of height.
+// Signing infos are queried only for the watched validators, via `SigningInfo`.
+// The new snapshot is put into cache for other components to read.
+func (w Worker) getChainQuerySnapshot(
+	ctx context.Context, querier rpcreg.RpcQuerier, registeredChainConfig chainreg.RegisteredChainConfig, latestBlockHeight int64,
+) (snapshot ChainQuerySnapshot, errFetchStakingValidators, errFetchSigningInfo, errFetchSlashingParams error) {
+	logger := w.ctx.AppCtx.Logger
+	chainName := registeredChainConfig.GetChainName()
+
+	previousSnapshot, found := GetChainQuerySnapshotRL(chainName)
+	if found && previousSnapshot.IsReusableAt(latestBlockHeight, registeredChainConfig.GetSnapshotReuseBlocks()) {
+		logger.Debug("reuse query snapshot", "chain", chainName, "snapshot_height", previousSnapshot.Height, "latest_height", latestBlockHeight)
+		snapshot = previousSnapshot
+	} else {
+		snapshot = ChainQuerySnapshot{
+			ChainName: chainName,
+			Height:    latestBlockHeight,
+			QueriedAt: time.Now().UTC(),
+		}
+
+		stakingValidators, err := getAllValidators(ctx, querier)
+		if err != nil {
+			errFetchStakingValidators = err
+			return
+		}
+
+		// prepare ranking
+		sort.Slice(stakingValidators, func(i, j int) bool {
+			left := stakingValidators[i]
+			right := stakingValidators[j]
+
+			if left.IsBonded() == right.IsBonded() {
+				return left.Tokens.GT(right.Tokens)
+			}
+
+			return left.IsBonded()
+		})
+		snapshot.Validators = stakingValidators
+	}
+
+	if found {
+		// slashing params rarely change, reuse regardless of height
+		snapshot.SlashingParams = previousSnapshot.SlashingParams
+		snapshot.SlashingParamsQueriedAt = previousSnapshot.SlashingParamsQueriedAt
+	}
+
+	// reload mapping
+	w.reloadMappingValAddressIfNeeded(registeredChainConfig, snapshot.Validators)
+
+	// fetch signing infos of watched validators, those available in the reused snapshot are kept
+	signingInfos := make(map[string]slashingtypes.ValidatorSigningInfo)
+	for _, validator := range registeredChainConfig.GetValidators() {
+		valcons, found := valaddreg.GetValconsByValoperRL(chainName, validator.ValidatorOperatorAddress)
+		if !found {
+			continue
+		}
+
+		if signingInfo, found := snapshot.SigningInfos[valcons]; found {
+			signingInfos[valcons] = signingInfo
+			continue
+		}
+
+		signingInfo, err := getSigningInfo(ctx, querier, valcons)
+		if err != nil {
+			errFetchSigningInfo = err
+			break
+		}
+		if signingInfo == nil {
+			continue
+		}
+		signingInfos[valcons] = *signingInfo
+	}
+	snapshot.SigningInfos = signingInfos
+
+	// fetch slashing params
+	if snapshot.SlashingParams == nil || time.Since(snapshot.SlashingParamsQueriedAt) > slashingParamsCacheDuration {
+		slashingParams, err := getSlashingParams(ctx, querier)
+		if err != nil {
+			if snapshot.SlashingParams == nil {
+				errFetchSlashingParams = err
+			} // otherwise keep using the previous params
+		} else {
+			snapshot.SlashingParams = slashingParams
+			snapshot.SlashingParamsQueriedAt = time.Now().UTC()
+		}
+	}
+
+	if errFetchSigningInfo == nil {
+		putCacheChainQuerySnapshotWL(snapshot)
+	}
+
+	return
+}
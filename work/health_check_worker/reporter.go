@@ -0,0 +1,215 @@
+package health_check_worker
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	libapp "github.com/EscanBE/go-lib/app"
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	usereg "github.com/bcdevtools/validator-health-check/registry/user_registry"
+	lesvc "github.com/bcdevtools/validator-health-check/services/leader_election_svc"
+	tpsvc "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc"
+	tptemplates "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/templates"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"github.com/bcdevtools/validator-health-check/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reporter sends the scheduled reports, summary of the watched validators, to users having report configured
+type reporter struct {
+	appCtx         config.AppContext
+	lastReportedAt map[string]time.Time // identity -> end of the period of the last report sent
+}
+
+// StartReportScheduler starts the routine sending the scheduled reports
+func StartReportScheduler(appCtx config.AppContext) {
+	r := newReporter(appCtx)
+	go r.start()
+}
+
+func newReporter(appCtx config.AppContext) *reporter {
+	return &reporter{
+		appCtx:         appCtx,
+		lastReportedAt: make(map[string]time.Time),
+	}
+}
+
+func (r *reporter) start() {
+	logger := r.appCtx.Logger
+	defer libapp.TryRecoverAndExecuteExitFunctionIfRecovered(logger)
+
+	for {
+		time.Sleep(constants.REPORT_CHECK_INTERVAL)
+
+		if !lesvc.IsLeaderRL() {
+			// standby, the leader sends the reports
+			continue
+		}
+
+		for _, userRecord := range r.getDueReports(time.Now().UTC(), usereg.GetAllUserRecordsRL()) {
+			since, until, _ := userRecord.GetReportPeriod(time.Now().UTC())
+			logger.Info("sending scheduled report", "identity", userRecord.Identity, "since", since, "until", until)
+
+			// the report of many validators might exceed the Telegram message length limit
+			report := composeReport(userRecord, chainreg.GetCopyAllChainConfigsRL(), since, until)
+			for _, chunk := range utils.SplitTextByLines(report, constants.TELEGRAM_MESSAGE_MAX_LENGTH) {
+				tpsvc.EnqueueMessageWL(tptypes.QueueMessage{
+					ReceiverID: userRecord.TelegramConfig.UserId,
+					Priority:   userRecord.Root,
+					Report:     true,
+					Message:    chunk,
+				})
+			}
+		}
+	}
+}
+
+// getDueReports returns users having the latest scheduled report not sent yet.
+// Reports missed by more than the maximum delay, eg: the daemon was down, are skipped.
+func (r *reporter) getDueReports(now time.Time, userRecords config.UserRecords) (due config.UserRecords) {
+	for _, userRecord := range userRecords {
+		if userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
+			continue
+		}
+
+		_, until, enabled := userRecord.GetReportPeriod(now)
+		if !enabled {
+			continue
+		}
+
+		if lastReportedAt, found := r.lastReportedAt[userRecord.Identity]; found && !lastReportedAt.Before(until) {
+			continue
+		}
+		r.lastReportedAt[userRecord.Identity] = until
+
+		if now.Sub(until) > constants.REPORT_MAX_DELAY {
+			continue
+		}
+
+		due = append(due, userRecord)
+	}
+
+	return
+}
+
+// composeReport composes the report of the validators watched by the user, within the given period
+func composeReport(userRecord config.UserRecord, chains chainreg.RegisteredChainsConfig, since, until time.Time) string {
+	location := userRecord.GetLocation()
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(
+		"📊 %s report for %s\n%s - %s",
+		func() string {
+			if userRecord.Report.Schedule == constants.REPORT_SCHEDULE_WEEKLY {
+				return "Weekly"
+			}
+			return "Daily"
+		}(),
+		userRecord.Identity,
+		since.In(location).Format(time.DateTime),
+		until.In(location).Format(time.DateTime+" MST"),
+	))
+
+	var anyValidator bool
+	for _, chain := range chains.Sort() {
+		var watchedValidators []chainreg.ValidatorOfRegisteredChainConfig
+		for _, validator := range chain.GetValidators() {
+			if utils.Contains(validator.WatchersIdentity, userRecord.Identity) {
+				watchedValidators = append(watchedValidators, validator)
+			}
+		}
+		if len(watchedValidators) < 1 {
+			continue
+		}
+		anyValidator = true
+
+		chainSummary := getChainReportSummaryRL(chain.GetChainName(), since, until)
+		sb.WriteString(fmt.Sprintf("\n\n[%s]", chain.GetChainName()))
+		if chainSummary.rpcChecks > 0 {
+			sb.WriteString(fmt.Sprintf(
+				" RPC availability %.2f%% (%d/%d health-checks)",
+				utils.RatioOfInt64(int64(chainSummary.rpcAvailable), int64(chainSummary.rpcChecks)), chainSummary.rpcAvailable, chainSummary.rpcChecks,
+			))
+		} else {
+			sb.WriteString(" not health-checked within the period")
+		}
+		if len(chainSummary.alerts) > 0 {
+			sb.WriteString("\nChain alerts: ")
+			sb.WriteString(describeAlertsSummary(chainSummary.alerts))
+		}
+
+		for _, validator := range watchedValidators {
+			valoper := validator.ValidatorOperatorAddress
+			summary := getValidatorReportSummaryRL(valoper, since, until)
+
+			sb.WriteString("\n- ")
+			if cache, found := GetCacheValidatorHealthCheckRL(valoper); found && cache.Moniker != "" {
+				sb.WriteString(fmt.Sprintf("%s (%s)", cache.Moniker, valoper))
+				if cache.Uptime != nil {
+					sb.WriteString(fmt.Sprintf("\n  Uptime: %.2f%%", *cache.Uptime))
+				}
+			} else {
+				sb.WriteString(valoper)
+			}
+
+			if summary.rankAtEnd > 0 {
+				sb.WriteString(fmt.Sprintf("\n  Rank: %d", summary.rankAtEnd))
+				if change := summary.rankAtStart - summary.rankAtEnd; change > 0 {
+					sb.WriteString(fmt.Sprintf(" (up %d)", change))
+				} else if change < 0 {
+					sb.WriteString(fmt.Sprintf(" (down %d)", -change))
+				} else {
+					sb.WriteString(" (unchanged)")
+				}
+			}
+			if summary.jailEvents > 0 {
+				sb.WriteString(fmt.Sprintf("\n  Jailed: %d time(s)", summary.jailEvents))
+			}
+			if len(summary.missedGovProposals) > 0 {
+				proposals := make([]string, len(summary.missedGovProposals))
+				for i, proposalId := range summary.missedGovProposals {
+					proposals[i] = fmt.Sprintf("#%d", proposalId)
+				}
+				sb.WriteString("\n  Not voted proposals: ")
+				sb.WriteString(strings.Join(proposals, ", "))
+			}
+			if len(summary.alerts) > 0 {
+				sb.WriteString("\n  Alerts: ")
+				sb.WriteString(describeAlertsSummary(summary.alerts))
+			} else {
+				sb.WriteString("\n  No alert")
+			}
+		}
+	}
+
+	if !anyValidator {
+		sb.WriteString("\n\nYou are not watching any validator")
+	}
+
+	return sb.String()
+}
+
+// describeAlertsSummary describes the alerts fired, the most severe first
+func describeAlertsSummary(alerts map[string]alertSummary) string {
+	alertTypes := make([]string, 0, len(alerts))
+	for alertType := range alerts {
+		alertTypes = append(alertTypes, alertType)
+	}
+	sort.Slice(alertTypes, func(i, j int) bool {
+		left, right := alerts[alertTypes[i]], alerts[alertTypes[j]]
+		if left.highestSeverity != right.highestSeverity {
+			return left.highestSeverity > right.highestSeverity
+		}
+		return alertTypes[i] < alertTypes[j]
+	})
+
+	described := make([]string, len(alertTypes))
+	for i, alertType := range alertTypes {
+		summary := alerts[alertType]
+		described[i] = fmt.Sprintf("%s %s x%d", tptemplates.GetSeverityEmoji(summary.highestSeverity), alertType, summary.count)
+	}
+	return strings.Join(described, ", ")
+}
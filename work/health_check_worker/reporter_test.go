@@ -0,0 +1,130 @@
+package health_check_worker
+
+import (
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	tptemplates "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/templates"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type reportTestChain struct {
+	chainreg.RegisteredChainConfig
+	chainName  string
+	validators []chainreg.ValidatorOfRegisteredChainConfig
+}
+
+func (c reportTestChain) GetChainName() string {
+	return c.chainName
+}
+
+func (c reportTestChain) IsPriority() bool {
+	return false
+}
+
+func (c reportTestChain) GetValidators() []chainreg.ValidatorOfRegisteredChainConfig {
+	return c.validators
+}
+
+func Test_reportHistory(t *testing.T) {
+	const valoper = "valoper_report_history"
+	const chainName = "chain_report_history"
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bTrue, bFalse := true, false
+
+	recordValidatorHealthCheckWL(CacheValidatorHealthCheck{Valoper: valoper, Rank: 90, Jailed: &bFalse}, start.Add(-8*24*time.Hour)) // pruned later
+	recordValidatorHealthCheckWL(CacheValidatorHealthCheck{Valoper: valoper, Rank: 10, Jailed: &bFalse}, start.Add(time.Hour))
+	recordValidatorHealthCheckWL(CacheValidatorHealthCheck{Valoper: valoper, Rank: 12, Jailed: &bTrue}, start.Add(2*time.Hour))
+	recordValidatorHealthCheckWL(CacheValidatorHealthCheck{Valoper: valoper, Rank: 12, Jailed: &bTrue}, start.Add(3*time.Hour)) // still jailed
+	recordValidatorHealthCheckWL(CacheValidatorHealthCheck{Valoper: valoper, Rank: 15}, start.Add(4*time.Hour))                 // unknown jail status
+	recordValidatorHealthCheckWL(CacheValidatorHealthCheck{Valoper: valoper, Rank: 15, Jailed: &bFalse}, start.Add(5*time.Hour))
+	recordValidatorHealthCheckWL(CacheValidatorHealthCheck{Valoper: valoper, Rank: 14, Jailed: &bTrue}, start.Add(6*time.Hour))
+	recordMissedGovVoteWL(valoper, 7, start.Add(time.Hour))
+	recordMissedGovVoteWL(valoper, 7, start.Add(2*time.Hour)) // duplicated
+	recordMissedGovVoteWL(valoper, 5, start.Add(2*time.Hour))
+	recordFindingWL(chainName, valoper, tptemplates.AlertTypeMissedBlocks, tptypes.SeverityWarning, "", false, start.Add(time.Hour))
+	recordFindingWL(chainName, valoper, tptemplates.AlertTypeMissedBlocks, tptypes.SeverityFatal, "missed 100 blocks", false, start.Add(2*time.Hour))
+	recordFindingWL(chainName, valoper, tptemplates.AlertTypeMissedBlocks, tptypes.SeverityFatal, "missed 101 blocks", true, start.Add(3*time.Hour)) // suppressed by spam prevention
+	recordFindingWL(chainName, "", tptemplates.AlertTypeBlockOutdated, tptypes.SeverityCritical, "", false, start.Add(2*time.Hour))
+	recordRpcAvailabilityWL(chainName, true, start.Add(time.Hour))
+	recordRpcAvailabilityWL(chainName, false, start.Add(2*time.Hour))
+	recordRpcAvailabilityWL(chainName, true, start.Add(3*time.Hour))
+	recordRpcAvailabilityWL(chainName, true, start.Add(25*time.Hour)) // out of period
+
+	summary := getValidatorReportSummaryRL(valoper, start, start.Add(24*time.Hour))
+	require.Equal(t, 10, summary.rankAtStart)
+	require.Equal(t, 14, summary.rankAtEnd)
+	require.Equal(t, 2, summary.jailEvents)
+	require.Equal(t, []uint64{5, 7}, summary.missedGovProposals)
+	require.Equal(t, map[string]alertSummary{
+		tptemplates.AlertTypeMissedBlocks: {count: 2, highestSeverity: tptypes.SeverityFatal},
+	}, summary.alerts)
+
+	chainSummary := getChainReportSummaryRL(chainName, start, start.Add(24*time.Hour))
+	require.Equal(t, 3, chainSummary.rpcChecks)
+	require.Equal(t, 2, chainSummary.rpcAvailable)
+	require.Equal(t, 1, chainSummary.alerts[tptemplates.AlertTypeBlockOutdated].count)
+
+	findings := GetFindingsSinceRL(chainName, valoper, start.Add(2*time.Hour))
+	require.Len(t, findings, 2, "suppressed findings are included")
+	require.Equal(t, "missed 100 blocks", findings[0].Message)
+	require.False(t, findings[0].Suppressed)
+	require.Equal(t, "missed 101 blocks", findings[1].Message)
+	require.True(t, findings[1].Suppressed)
+	require.Len(t, GetFindingsSinceRL(chainName, "", start), 1, "chain-level findings")
+
+	require.Equal(t, 0, getValidatorReportSummaryRL(valoper, start.Add(-9*24*time.Hour), start).rankAtStart, "older than retention should be pruned")
+
+	report := composeReport(
+		config.UserRecord{Identity: "alice", Report: &config.UserReportConfig{Schedule: constants.REPORT_SCHEDULE_DAILY}},
+		chainreg.RegisteredChainsConfig{
+			reportTestChain{chainName: chainName, validators: []chainreg.ValidatorOfRegisteredChainConfig{
+				{ValidatorOperatorAddress: valoper, WatchersIdentity: []string{"alice"}},
+			}},
+			reportTestChain{chainName: "not_watched", validators: []chainreg.ValidatorOfRegisteredChainConfig{
+				{ValidatorOperatorAddress: "other", WatchersIdentity: []string{"bob"}},
+			}},
+		},
+		start, start.Add(24*time.Hour),
+	)
+	require.Contains(t, report, "Daily report for alice")
+	require.Contains(t, report, "[chain_report_history] RPC availability 66.67% (2/3 health-checks)")
+	require.Contains(t, report, "Chain alerts: ❗ block-outdated x1")
+	require.Contains(t, report, "Rank: 14 (down 4)")
+	require.Contains(t, report, "Jailed: 2 time(s)")
+	require.Contains(t, report, "Not voted proposals: #5, #7")
+	require.Contains(t, report, "Alerts: 🚨 missed-blocks x2")
+	require.NotContains(t, report, "not_watched")
+}
+
+func Test_reporter_getDueReports(t *testing.T) {
+	userRecord := config.UserRecord{
+		Identity: "alice",
+		TelegramConfig: &config.UserTelegramConfig{
+			Username: "alice",
+			UserId:   1,
+			Token:    "token",
+		},
+		Report: &config.UserReportConfig{Schedule: constants.REPORT_SCHEDULE_DAILY, At: "09:00"},
+	}
+	noReport := config.UserRecord{
+		Identity:       "bob",
+		TelegramConfig: userRecord.TelegramConfig,
+	}
+	userRecords := config.UserRecords{userRecord, noReport}
+
+	r := newReporter(config.AppContext{})
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	require.Len(t, r.getDueReports(day.Add(9*time.Hour+30*time.Minute), userRecords), 1)
+	require.Empty(t, r.getDueReports(day.Add(9*time.Hour+31*time.Minute), userRecords), "already sent")
+	require.Empty(t, r.getDueReports(day.Add(33*time.Hour-1*time.Minute), userRecords), "not yet")
+	require.Len(t, r.getDueReports(day.Add(33*time.Hour+1*time.Minute), userRecords), 1, "next day")
+
+	r = newReporter(config.AppContext{})
+	require.Empty(t, r.getDueReports(day.Add(11*time.Hour), userRecords), "missed for too long, eg: daemon was down")
+	require.Empty(t, r.getDueReports(day.Add(11*time.Hour), userRecords))
+}
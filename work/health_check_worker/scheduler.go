@@ -0,0 +1,343 @@
+package health_check_worker
+
+import (
+	"container/heap"
+	"context"
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	workertypes "github.com/bcdevtools/validator-health-check/work/health_check_worker/types"
+	"sync"
+	"time"
+)
+
+// schedulerSyncInterval is the interval to pick up chains added by hot-reload
+const schedulerSyncInterval = 5 * time.Second
+
+// Scheduler dispatches chains to health-check workers.
+// Chains are queued by due time (last dispatch + health-check interval), chains marked as priority are dispatched first.
+// Health-checks requested via chainreg.RequestHealthCheckWL are dispatched sooner than the interval.
+type Scheduler struct {
+	sync.Mutex
+	appCtx   config.AppContext
+	interval time.Duration
+
+	priorityQueue scheduledChainQueue
+	normalQueue   scheduledChainQueue
+	chains        map[string]*scheduledChain // queued or in-flight chains
+	lastSyncAt    time.Time
+	wake          chan struct{}
+
+	dispatchCtx  context.Context // cancelled to stop dispatching new health-checks
+	stopDispatch context.CancelFunc
+	checkCtx     context.Context // cancelled to abort in-flight health-checks
+	cancelChecks context.CancelFunc
+
+	workers sync.WaitGroup
+}
+
+type scheduledChain struct {
+	chainName       string
+	priority        bool
+	dueAt           time.Time
+	addedAt         time.Time
+	lastDispatchAt  time.Time
+	lastCompletedAt time.Time
+	inFlight        bool
+	requested       bool // health-check requested while in-flight
+	index           int  // index within the queue, -1 when not queued
+}
+
+// NewScheduler creates new health-check scheduler
+func NewScheduler(appCtx config.AppContext) *Scheduler {
+	interval := appCtx.AppConfig.General.HealthCheckInterval
+	if interval < 30*time.Second {
+		interval = 30 * time.Second
+	}
+
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	checkCtx, cancelChecks := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		appCtx:       appCtx,
+		interval:     interval,
+		chains:       make(map[string]*scheduledChain),
+		wake:         make(chan struct{}, 1),
+		dispatchCtx:  dispatchCtx,
+		stopDispatch: stopDispatch,
+		checkCtx:     checkCtx,
+		cancelChecks: cancelChecks,
+	}
+}
+
+// Start launches the health-check workers
+func (s *Scheduler) Start(workerCount int) {
+	for id := 1; id <= workerCount; id++ {
+		workerWorkingCtx := &workertypes.HcwContext{
+			WorkerID: id,
+			AppCtx:   s.appCtx,
+		}
+
+		s.appCtx.Logger.Debug("starting health-check worker", "wid", workerWorkingCtx.WorkerID)
+		s.workers.Add(1)
+		go NewHcWorker(workerWorkingCtx).Start(s)
+	}
+}
+
+// Shutdown stops dispatching new health-checks and waits for the in-flight health-checks to finish.
+// If they do not finish within the drain timeout, they are cancelled.
+// Returns true if all in-flight health-checks finished within the drain timeout.
+func (s *Scheduler) Shutdown(drainTimeout time.Duration) bool {
+	s.stopDispatch()
+
+	drained := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return true
+	case <-time.After(drainTimeout):
+		s.cancelChecks()
+		select {
+		case <-drained:
+		case <-time.After(5 * time.Second):
+		}
+		return false
+	}
+}
+
+// next blocks until a chain is due for health-check, returns false when the scheduler is shutting down.
+func (s *Scheduler) next() (chainreg.RegisteredChainConfig, bool) {
+	for {
+		if s.dispatchCtx.Err() != nil {
+			return nil, false
+		}
+
+		registeredChainConfig, wait := s.dequeue(time.Now().UTC())
+		if registeredChainConfig != nil {
+			return registeredChainConfig, true
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-s.dispatchCtx.Done():
+			timer.Stop()
+			return nil, false
+		case <-s.wake:
+		case <-chainreg.HealthCheckRequestedSignal():
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// done marks the health-check of the chain as finished, re-queues the chain.
+func (s *Scheduler) done(chainName string) {
+	s.Lock()
+	defer s.Unlock()
+
+	sc, found := s.chains[chainName]
+	if !found {
+		return
+	}
+
+	sc.inFlight = false
+	sc.lastCompletedAt = time.Now().UTC()
+	chainreg.NotifyHealthCheckCompletedWL(chainName, sc.lastDispatchAt)
+	if registeredChainConfig, found := chainreg.GetChainConfigRL(chainName); found {
+		sc.priority = registeredChainConfig.IsPriority()
+	} else {
+		delete(s.chains, chainName)
+		return
+	}
+
+	if sc.requested {
+		sc.requested = false
+		s.advanceDueAt(sc, time.Now().UTC())
+	}
+
+	heap.Push(s.queueOf(sc), sc)
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dequeue returns the chain due for health-check, or the duration to wait until the next chain is due.
+func (s *Scheduler) dequeue(now time.Time) (chainreg.RegisteredChainConfig, time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.syncWithRegistry(now)
+	s.applyRequestedHealthChecks(now)
+
+	for _, queue := range []*scheduledChainQueue{&s.priorityQueue, &s.normalQueue} {
+		for queue.Len() > 0 && !(*queue)[0].dueAt.After(now) {
+			sc := heap.Pop(queue).(*scheduledChain)
+
+			registeredChainConfig, found := chainreg.GetChainConfigRL(sc.chainName)
+			if !found {
+				// removed by hot-reload
+				delete(s.chains, sc.chainName)
+				continue
+			}
+
+			sc.dueAt = now.Add(s.interval)
+
+			if paused, _ := chainreg.IsChainPausedRL(sc.chainName); paused {
+				heap.Push(queue, sc)
+				continue
+			}
+
+			sc.inFlight = true
+			sc.lastDispatchAt = now
+			registeredChainConfig.SetLastHealthCheckUtcWL()
+			return registeredChainConfig, 0
+		}
+	}
+
+	wait := schedulerSyncInterval
+	for _, queue := range []scheduledChainQueue{s.priorityQueue, s.normalQueue} {
+		if queue.Len() < 1 {
+			continue
+		}
+		if untilDue := queue[0].dueAt.Sub(now); untilDue < wait {
+			wait = untilDue
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+
+	return nil, wait
+}
+
+// syncWithRegistry queues the chains newly registered by hot-reload
+func (s *Scheduler) syncWithRegistry(now time.Time) {
+	if now.Sub(s.lastSyncAt) < schedulerSyncInterval {
+		return
+	}
+	s.lastSyncAt = now
+
+	for _, registeredChainConfig := range chainreg.GetCopyAllChainConfigsRL() {
+		chainName := registeredChainConfig.GetChainName()
+		if _, found := s.chains[chainName]; found {
+			continue
+		}
+
+		sc := &scheduledChain{
+			chainName: chainName,
+			priority:  registeredChainConfig.IsPriority(),
+			dueAt:     now,
+			addedAt:   now,
+			index:     -1,
+		}
+		s.chains[chainName] = sc
+		heap.Push(s.queueOf(sc), sc)
+	}
+}
+
+// applyRequestedHealthChecks brings forward the due time of the chains requested to be health-checked
+func (s *Scheduler) applyRequestedHealthChecks(now time.Time) {
+	for _, chainName := range chainreg.ConsumeRequestedHealthChecksWL() {
+		sc, found := s.chains[chainName]
+		if !found {
+			continue
+		}
+
+		if sc.inFlight {
+			sc.requested = true
+			continue
+		}
+
+		if s.advanceDueAt(sc, now) && sc.index >= 0 {
+			heap.Fix(s.queueOf(sc), sc.index)
+		}
+	}
+}
+
+// advanceDueAt brings forward the due time of the chain, respecting the minimum duration between requested health-checks.
+func (s *Scheduler) advanceDueAt(sc *scheduledChain, now time.Time) bool {
+	dueAt := sc.lastDispatchAt.Add(constants.MIN_DURATION_BETWEEN_REQUESTED_HEALTH_CHECK)
+	if dueAt.Before(now) {
+		dueAt = now
+	}
+
+	if !dueAt.Before(sc.dueAt) {
+		return false
+	}
+
+	sc.dueAt = dueAt
+	return true
+}
+
+// scheduledChainStatus is the progress of the health-checks of a chain, for watchdog
+type scheduledChainStatus struct {
+	chainName       string
+	addedAt         time.Time
+	lastCompletedAt time.Time // zero if never completed
+	inFlight        bool
+}
+
+// getChainsStatus returns the progress of the health-checks of all scheduled chains
+func (s *Scheduler) getChainsStatus() []scheduledChainStatus {
+	s.Lock()
+	defer s.Unlock()
+
+	chainsStatus := make([]scheduledChainStatus, 0, len(s.chains))
+	for _, sc := range s.chains {
+		chainsStatus = append(chainsStatus, scheduledChainStatus{
+			chainName:       sc.chainName,
+			addedAt:         sc.addedAt,
+			lastCompletedAt: sc.lastCompletedAt,
+			inFlight:        sc.inFlight,
+		})
+	}
+	return chainsStatus
+}
+
+func (s *Scheduler) queueOf(sc *scheduledChain) *scheduledChainQueue {
+	if sc.priority {
+		return &s.priorityQueue
+	}
+	return &s.normalQueue
+}
+
+var _ heap.Interface = &scheduledChainQueue{}
+
+// scheduledChainQueue is a min-heap of scheduled chains, ordered by due time
+type scheduledChainQueue []*scheduledChain
+
+func (q scheduledChainQueue) Len() int {
+	return len(q)
+}
+
+func (q scheduledChainQueue) Less(i, j int) bool {
+	return q[i].dueAt.Before(q[j].dueAt)
+}
+
+func (q scheduledChainQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *scheduledChainQueue) Push(x any) {
+	sc := x.(*scheduledChain)
+	sc.index = len(*q)
+	*q = append(*q, sc)
+}
+
+func (q *scheduledChainQueue) Pop() any {
+	old := *q
+	n := len(old)
+	sc := old[n-1]
+	old[n-1] = nil
+	sc.index = -1
+	*q = old[:n-1]
+	return sc
+}
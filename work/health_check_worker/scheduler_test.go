@@ -0,0 +1,123 @@
+package health_check_worker
+
+import (
+	"container/heap"
+	"context"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_scheduledChainQueue(t *testing.T) {
+	now := time.Now()
+
+	var queue scheduledChainQueue
+	for i, offset := range []time.Duration{3 * time.Minute, time.Minute, 2 * time.Minute} {
+		heap.Push(&queue, &scheduledChain{
+			chainName: []string{"c", "a", "b"}[i],
+			dueAt:     now.Add(offset),
+			index:     -1,
+		})
+	}
+
+	// bring forward the last one
+	c := queue[0]
+	for _, sc := range queue {
+		if sc.chainName == "c" {
+			c = sc
+		}
+	}
+	c.dueAt = now
+	heap.Fix(&queue, c.index)
+
+	var order []string
+	for queue.Len() > 0 {
+		sc := heap.Pop(&queue).(*scheduledChain)
+		require.Equal(t, -1, sc.index)
+		order = append(order, sc.chainName)
+	}
+	require.Equal(t, []string{"c", "a", "b"}, order)
+}
+
+func TestScheduler_advanceDueAt(t *testing.T) {
+	s := &Scheduler{}
+	now := time.Now()
+
+	sc := &scheduledChain{
+		dueAt:          now.Add(10 * time.Minute),
+		lastDispatchAt: now.Add(-time.Minute),
+	}
+	require.True(t, s.advanceDueAt(sc, now))
+	require.Equal(t, now, sc.dueAt, "should be due immediately")
+
+	sc = &scheduledChain{
+		dueAt:          now.Add(10 * time.Minute),
+		lastDispatchAt: now.Add(-time.Second),
+	}
+	require.True(t, s.advanceDueAt(sc, now))
+	require.Equal(t, now.Add(-time.Second).Add(constants.MIN_DURATION_BETWEEN_REQUESTED_HEALTH_CHECK), sc.dueAt, "should respect minimum duration between requested health-checks")
+
+	sc = &scheduledChain{
+		dueAt:          now,
+		lastDispatchAt: now.Add(-time.Hour),
+	}
+	require.False(t, s.advanceDueAt(sc, now.Add(time.Second)), "already due")
+}
+
+func TestScheduler_Shutdown(t *testing.T) {
+	s := &Scheduler{}
+	s.dispatchCtx, s.stopDispatch = context.WithCancel(context.Background())
+	s.checkCtx, s.cancelChecks = context.WithCancel(context.Background())
+
+	// in-flight health-check finishing in time
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		<-s.dispatchCtx.Done()
+		time.Sleep(50 * time.Millisecond)
+	}()
+	require.True(t, s.Shutdown(time.Second))
+	require.NoError(t, s.checkCtx.Err(), "in-flight health-checks should not be cancelled when drained in time")
+
+	// in-flight health-check stuck until cancelled
+	s.dispatchCtx, s.stopDispatch = context.WithCancel(context.Background())
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		<-s.checkCtx.Done()
+	}()
+	require.False(t, s.Shutdown(50*time.Millisecond))
+	require.Error(t, s.checkCtx.Err())
+}
+
+func TestScheduler_done_notifyCompletionWaiters(t *testing.T) {
+	const chainName = "chain_completion_waiters"
+	s := &Scheduler{
+		chains: make(map[string]*scheduledChain),
+		wake:   make(chan struct{}, 1),
+	}
+	s.chains[chainName] = &scheduledChain{
+		chainName:      chainName,
+		inFlight:       true,
+		lastDispatchAt: time.Now().UTC().Add(-time.Second), // dispatched before the request
+	}
+
+	completed, _ := chainreg.RequestHealthCheckAndWaitWL(chainName)
+	_ = chainreg.ConsumeRequestedHealthChecksWL()
+
+	s.done(chainName)
+	select {
+	case <-completed:
+		t.Fatal("health-check dispatched before the request should not notify")
+	default:
+	}
+
+	chainreg.NotifyHealthCheckCompletedWL(chainName, time.Now().UTC())
+	select {
+	case <-completed:
+	default:
+		t.Fatal("health-check dispatched after the request should notify")
+	}
+}
@@ -0,0 +1,328 @@
+package health_check_worker
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"fmt"
+	libapp "github.com/EscanBE/go-lib/app"
+	"github.com/EscanBE/go-lib/logging"
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	rpcreg "github.com/bcdevtools/validator-health-check/registry/rpc_client_registry"
+	valaddreg "github.com/bcdevtools/validator-health-check/registry/validator_address_registry"
+	"github.com/bcdevtools/validator-health-check/utils"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/pkg/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
+	httpclient "github.com/tendermint/tendermint/rpc/client/http"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"strings"
+	"time"
+)
+
+const streamingSubscriber = "validator-health-check"
+
+// errStreamingNotSupported indicates the events of the node can not be decoded by the Tendermint v0.34 client
+var errStreamingNotSupported = errors.New("streaming not supported")
+
+//goland:noinspection SpellCheckingInspection
+const (
+	streamingQueryNewBlock        = "tm.event='NewBlock'"
+	streamingQuerySubmitProposal  = "tm.event='Tx' AND submit_proposal.voting_period_start EXISTS"
+	streamingQueryProposalDeposit = "tm.event='Tx' AND proposal_deposit.voting_period_start EXISTS"
+)
+
+// StartEventStreamingService starts the routine that maintains websocket subscriptions of chains having streaming enabled.
+// Relevant events (jailing, validator updates, new gov proposals) request an immediate health-check of the chain,
+// polling every health-check interval remains as fallback.
+func StartEventStreamingService(appCtx config.AppContext) {
+	go routineManageEventStreams(appCtx)
+}
+
+func routineManageEventStreams(appCtx config.AppContext) {
+	logger := appCtx.Logger
+	defer libapp.TryRecoverAndExecuteExitFunctionIfRecovered(logger)
+
+	for {
+		for _, registeredChainConfig := range chainreg.GetCopyAllChainConfigsRL() {
+			if !registeredChainConfig.IsStreamingEnabled() {
+				continue
+			}
+
+			chainName := registeredChainConfig.GetChainName()
+			if !markStreamingChainWL(chainName) {
+				continue
+			}
+
+			logger.Info("start streaming events", "chain", chainName)
+			go routineStreamChainEvents(chainName, logger)
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// routineStreamChainEvents keeps the subscriptions of the chain alive, reconnects with exponential backoff.
+// Stops when the chain is removed or streaming is disabled.
+func routineStreamChainEvents(chainName string, logger logging.Logger) {
+	defer unmarkStreamingChainWL(chainName)
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("panic while streaming events", "chain", chainName, "error", fmt.Sprintf("%v", r))
+		}
+	}()
+
+	backoff := constants.STREAMING_RECONNECT_MIN_BACKOFF
+	for {
+		registeredChainConfig, found := chainreg.GetChainConfigRL(chainName)
+		if !found || !registeredChainConfig.IsStreamingEnabled() {
+			logger.Info("stop streaming events", "chain", chainName)
+			return
+		}
+
+		connectedAt := time.Now()
+		err := streamChainEvents(registeredChainConfig, logger)
+		if errors.Is(err, errStreamingNotSupported) {
+			// checked again later, the chain or the RPC used to stream might be changed
+			logger.Error("streaming events disabled, polling remains", "chain", chainName, "reason", err.Error(), "recheck-after", constants.STREAMING_NOT_SUPPORTED_RECHECK_INTERVAL)
+			time.Sleep(constants.STREAMING_NOT_SUPPORTED_RECHECK_INTERVAL)
+			backoff = constants.STREAMING_RECONNECT_MIN_BACKOFF
+			continue
+		}
+		if time.Since(connectedAt) > constants.STREAMING_STALL_TIMEOUT {
+			// the connection was healthy for a while
+			backoff = constants.STREAMING_RECONNECT_MIN_BACKOFF
+		}
+
+		if err != nil {
+			logger.Error("streaming events interrupted, reconnecting", "chain", chainName, "backoff", backoff, "error", err.Error())
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > constants.STREAMING_RECONNECT_MAX_BACKOFF {
+			backoff = constants.STREAMING_RECONNECT_MAX_BACKOFF
+		}
+	}
+}
+
+// streamChainEvents subscribes to events of the chain and processes them until the connection is broken or stalled.
+func streamChainEvents(registeredChainConfig chainreg.RegisteredChainConfig, logger logging.Logger) error {
+	chainName := registeredChainConfig.GetChainName()
+
+	endpoint := getStreamingEndpoint(registeredChainConfig)
+	if endpoint == "" {
+		return fmt.Errorf("no RPC to stream events")
+	}
+
+	client, err := rpcreg.NewEventStreamingClient(endpoint)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create streaming client for %s", endpoint)
+	}
+	defer func() {
+		_ = client.Stop()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := ensureStreamingSupported(ctx, client); err != nil {
+		return errors.Wrapf(err, "streaming via %s", endpoint)
+	}
+
+	chNewBlock, err := client.Subscribe(ctx, streamingSubscriber, streamingQueryNewBlock, 10)
+	if err != nil {
+		return errors.Wrapf(err, "failed to subscribe NewBlock via %s", endpoint)
+	}
+	chSubmitProposal, err := client.Subscribe(ctx, streamingSubscriber, streamingQuerySubmitProposal, 10)
+	if err != nil {
+		return errors.Wrapf(err, "failed to subscribe Tx submit proposal via %s", endpoint)
+	}
+	chProposalDeposit, err := client.Subscribe(ctx, streamingSubscriber, streamingQueryProposalDeposit, 10)
+	if err != nil {
+		return errors.Wrapf(err, "failed to subscribe Tx proposal deposit via %s", endpoint)
+	}
+	defer func() {
+		_ = client.UnsubscribeAll(context.Background(), streamingSubscriber)
+	}()
+
+	logger.Debug("subscribed to events", "chain", chainName, "endpoint", endpoint)
+
+	stallTimer := time.NewTimer(constants.STREAMING_STALL_TIMEOUT)
+	defer stallTimer.Stop()
+
+	checkAlive := time.NewTicker(30 * time.Second)
+	defer checkAlive.Stop()
+
+	requestHealthCheck := func(reason string) {
+		logger.Info("requesting health-check from streamed events", "chain", chainName, "reason", reason)
+		chainreg.RequestHealthCheckWL(chainName)
+	}
+
+	for {
+		select {
+		case event := <-chNewBlock:
+			newBlock, ok := event.Data.(tmtypes.EventDataNewBlock)
+			if !ok || newBlock.Block == nil {
+				continue
+			}
+
+			if !stallTimer.Stop() {
+				<-stallTimer.C
+			}
+			stallTimer.Reset(constants.STREAMING_STALL_TIMEOUT)
+
+			putCacheStreamedBlockWL(chainName, newBlock.Block.Height, newBlock.Block.Time)
+
+			if reasons := findRelevantNewBlockEvents(newBlock, getWatchedConsensusAddresses(registeredChainConfig)); len(reasons) > 0 {
+				invalidateChainQuerySnapshotWL(chainName) // validators changed, must be queried again
+				requestHealthCheck(fmt.Sprintf("block %d: %s", newBlock.Block.Height, strings.Join(reasons, ", ")))
+			}
+		case event := <-chSubmitProposal:
+			resetCacheLastCheckGovByChainWL(chainName)
+			requestHealthCheck(fmt.Sprintf("new proposal on voting period %v", event.Events["submit_proposal.proposal_id"]))
+		case event := <-chProposalDeposit:
+			resetCacheLastCheckGovByChainWL(chainName)
+			requestHealthCheck(fmt.Sprintf("new proposal on voting period %v", event.Events["proposal_deposit.proposal_id"]))
+		case <-stallTimer.C:
+			return fmt.Errorf("no new block received within %s via %s", constants.STREAMING_STALL_TIMEOUT, endpoint)
+		case <-checkAlive.C:
+			latestRegisteredChainConfig, found := chainreg.GetChainConfigRL(chainName)
+			if !found || !latestRegisteredChainConfig.IsStreamingEnabled() {
+				return nil
+			}
+			registeredChainConfig = latestRegisteredChainConfig
+		}
+	}
+}
+
+// ensureStreamingSupported returns errStreamingNotSupported if the node runs CometBFT v0.37 or later.
+// The events of NewBlock are decoded using Tendermint v0.34 types:
+// CometBFT v0.37 changed the event attributes from bytes to strings,
+// CometBFT v0.38 replaced the results of begin/end block by the result of FinalizeBlock.
+func ensureStreamingSupported(ctx context.Context, client *httpclient.HTTP) error {
+	queryCtx, cancel := newQueryContext(ctx)
+	defer cancel()
+
+	resultStatus, err := client.Status(queryCtx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get node version")
+	}
+
+	if !isStreamingSupportedNodeVersion(resultStatus.NodeInfo.Version) {
+		return errors.Wrapf(errStreamingNotSupported, "events of CometBFT %s can not be decoded", resultStatus.NodeInfo.Version)
+	}
+
+	return nil
+}
+
+// isStreamingSupportedNodeVersion returns true if the NewBlock events of the Tendermint/CometBFT version can be decoded
+func isStreamingSupportedNodeVersion(nodeVersion string) bool {
+	// unrecognized version, eg: fork having custom version, is tried
+	return !utils.IsVersionAtLeast(nodeVersion, 0, 37)
+}
+
+// getStreamingEndpoint returns the best endpoint of the RPC pool of the chain if available, otherwise the first RPC
+func getStreamingEndpoint(registeredChainConfig chainreg.RegisteredChainConfig) string {
+	if rpcPool, found := rpcreg.GetRpcPoolByChainRL(registeredChainConfig.GetChainName()); found {
+		for _, stats := range rpcPool.GetRankedEndpointsStats() {
+			if stats.IsUsable() && stats.ConsecutiveFailures == 0 && stats.LatestBlockHeight > 0 {
+				return stats.Endpoint
+			}
+		}
+	}
+
+	rpc := registeredChainConfig.GetRPCs()
+	if len(rpc) == 0 {
+		return ""
+	}
+	return rpc[0]
+}
+
+// getWatchedConsensusAddresses returns the consensus addresses (hex) of the watched validators of the chain,
+// those not yet resolved by health-check are not included.
+func getWatchedConsensusAddresses(registeredChainConfig chainreg.RegisteredChainConfig) map[string]bool {
+	consAddrs := make(map[string]bool)
+	for _, validator := range registeredChainConfig.GetValidators() {
+		valcons, found := valaddreg.GetValconsByValoperRL(registeredChainConfig.GetChainName(), validator.ValidatorOperatorAddress)
+		if !found {
+			continue
+		}
+
+		_, bz, err := bech32.DecodeAndConvert(valcons)
+		if err != nil {
+			continue
+		}
+
+		consAddrs[fmt.Sprintf("%X", bz)] = true
+	}
+	return consAddrs
+}
+
+// findRelevantNewBlockEvents returns the reasons to health-check the chain immediately:
+// watched validators slashed or jailed in begin block, or voting power of watched validators updated in end block.
+func findRelevantNewBlockEvents(newBlock tmtypes.EventDataNewBlock, watchedConsAddrs map[string]bool) []string {
+	if len(watchedConsAddrs) == 0 {
+		return nil
+	}
+
+	var reasons []string
+
+	isWatchedValcons := func(valcons string) bool {
+		_, bz, err := bech32.DecodeAndConvert(valcons)
+		if err != nil {
+			return false
+		}
+		return watchedConsAddrs[fmt.Sprintf("%X", bz)]
+	}
+
+	for _, event := range append(newBlock.ResultBeginBlock.Events, newBlock.ResultEndBlock.Events...) {
+		if event.Type != "slash" && event.Type != "liveness" {
+			continue
+		}
+
+		var watched, jailed bool
+		for _, attribute := range event.Attributes {
+			switch string(attribute.Key) {
+			case "address":
+				watched = watched || isWatchedValcons(string(attribute.Value))
+			case "jailed":
+				jailed = true
+				watched = watched || isWatchedValcons(string(attribute.Value))
+			}
+		}
+
+		if !watched {
+			continue
+		}
+
+		if jailed {
+			reasons = append(reasons, "watched validator jailed")
+		} else {
+			reasons = append(reasons, fmt.Sprintf("watched validator %s", event.Type))
+		}
+	}
+
+	if hasWatchedValidatorUpdates(newBlock.ResultEndBlock.ValidatorUpdates, watchedConsAddrs) {
+		reasons = append(reasons, "voting power of watched validator updated")
+	}
+
+	return reasons
+}
+
+func hasWatchedValidatorUpdates(validatorUpdates []abci.ValidatorUpdate, watchedConsAddrs map[string]bool) bool {
+	for _, validatorUpdate := range validatorUpdates {
+		pubKey, err := cryptoenc.PubKeyFromProto(validatorUpdate.PubKey)
+		if err != nil {
+			continue
+		}
+
+		if watchedConsAddrs[pubKey.Address().String()] {
+			return true
+		}
+	}
+
+	return false
+}
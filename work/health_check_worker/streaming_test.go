@@ -0,0 +1,120 @@
+package health_check_worker
+
+import (
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"testing"
+)
+
+func Test_findRelevantNewBlockEvents(t *testing.T) {
+	watchedPubKey := ed25519.GenPrivKey().PubKey()
+	otherPubKey := ed25519.GenPrivKey().PubKey()
+
+	toValcons := func(pubKeyAddress []byte) string {
+		valcons, err := bech32.ConvertAndEncode("cosmosvalcons", pubKeyAddress)
+		require.NoError(t, err)
+		return valcons
+	}
+	watchedValcons := toValcons(watchedPubKey.Address())
+	otherValcons := toValcons(otherPubKey.Address())
+
+	watchedConsAddrs := map[string]bool{
+		fmt.Sprintf("%X", watchedPubKey.Address().Bytes()): true,
+	}
+
+	slashEvent := func(valcons string, jailed bool) abci.Event {
+		event := abci.Event{
+			Type: "slash",
+			Attributes: []abci.EventAttribute{
+				{Key: []byte("address"), Value: []byte(valcons)},
+				{Key: []byte("reason"), Value: []byte("missing_signature")},
+			},
+		}
+		if jailed {
+			event.Attributes = append(event.Attributes, abci.EventAttribute{Key: []byte("jailed"), Value: []byte(valcons)})
+		}
+		return event
+	}
+
+	validatorUpdate := func(pubKey crypto.PubKey) abci.ValidatorUpdate {
+		protoPubKey, err := cryptoenc.PubKeyToProto(pubKey)
+		require.NoError(t, err)
+		return abci.ValidatorUpdate{PubKey: protoPubKey, Power: 1}
+	}
+
+	tests := []struct {
+		name             string
+		newBlock         tmtypes.EventDataNewBlock
+		watchedConsAddrs map[string]bool
+		wantReasons      []string
+	}{
+		{
+			name: "no watched validator",
+			newBlock: tmtypes.EventDataNewBlock{
+				ResultBeginBlock: abci.ResponseBeginBlock{Events: []abci.Event{slashEvent(watchedValcons, true)}},
+			},
+			watchedConsAddrs: nil,
+			wantReasons:      nil,
+		},
+		{
+			name: "watched validator jailed",
+			newBlock: tmtypes.EventDataNewBlock{
+				ResultBeginBlock: abci.ResponseBeginBlock{Events: []abci.Event{slashEvent(watchedValcons, true)}},
+			},
+			watchedConsAddrs: watchedConsAddrs,
+			wantReasons:      []string{"watched validator jailed"},
+		},
+		{
+			name: "watched validator slashed without jailing",
+			newBlock: tmtypes.EventDataNewBlock{
+				ResultBeginBlock: abci.ResponseBeginBlock{Events: []abci.Event{slashEvent(watchedValcons, false)}},
+			},
+			watchedConsAddrs: watchedConsAddrs,
+			wantReasons:      []string{"watched validator slash"},
+		},
+		{
+			name: "other validator jailed",
+			newBlock: tmtypes.EventDataNewBlock{
+				ResultBeginBlock: abci.ResponseBeginBlock{Events: []abci.Event{slashEvent(otherValcons, true)}},
+			},
+			watchedConsAddrs: watchedConsAddrs,
+			wantReasons:      nil,
+		},
+		{
+			name: "voting power of watched validator updated",
+			newBlock: tmtypes.EventDataNewBlock{
+				ResultEndBlock: abci.ResponseEndBlock{ValidatorUpdates: []abci.ValidatorUpdate{validatorUpdate(otherPubKey), validatorUpdate(watchedPubKey)}},
+			},
+			watchedConsAddrs: watchedConsAddrs,
+			wantReasons:      []string{"voting power of watched validator updated"},
+		},
+		{
+			name: "voting power of other validator updated",
+			newBlock: tmtypes.EventDataNewBlock{
+				ResultEndBlock: abci.ResponseEndBlock{ValidatorUpdates: []abci.ValidatorUpdate{validatorUpdate(otherPubKey)}},
+			},
+			watchedConsAddrs: watchedConsAddrs,
+			wantReasons:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantReasons, findRelevantNewBlockEvents(tt.newBlock, tt.watchedConsAddrs))
+		})
+	}
+}
+
+func Test_isStreamingSupportedNodeVersion(t *testing.T) {
+	require.True(t, isStreamingSupportedNodeVersion("0.34.27"))
+	require.True(t, isStreamingSupportedNodeVersion("v0.34.29-terra.rc.1"))
+	require.True(t, isStreamingSupportedNodeVersion("custom"), "unrecognized version is tried")
+	require.False(t, isStreamingSupportedNodeVersion("0.37.4"), "event attributes changed to strings")
+	require.False(t, isStreamingSupportedNodeVersion("0.38.2"), "FinalizeBlock replaced begin/end block")
+	require.False(t, isStreamingSupportedNodeVersion("1.0.0"))
+}
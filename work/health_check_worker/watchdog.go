@@ -0,0 +1,236 @@
+package health_check_worker
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"fmt"
+	libapp "github.com/EscanBE/go-lib/app"
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	chainreg "github.com/bcdevtools/validator-health-check/registry/chain_registry"
+	usereg "github.com/bcdevtools/validator-health-check/registry/user_registry"
+	lesvc "github.com/bcdevtools/validator-health-check/services/leader_election_svc"
+	tpsvc "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc"
+	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// watchdogInterval is the interval the watchdog evaluates the progress of the health-checks
+const watchdogInterval = 15 * time.Second
+
+// watchdog is the dead-man's switch of the daemon itself.
+// It pings the heartbeat URL after each successful scheduling cycle, means every active chain completed a health-check,
+// so an external monitor notices when the daemon hangs or dies. When high-availability is enabled, only the leader pings.
+// It also alerts root users when a chain has not been health-checked for several intervals.
+type watchdog struct {
+	appCtx       config.AppContext
+	scheduler    *Scheduler
+	heartbeatUrl string
+	httpClient   *http.Client
+
+	cycleStartedAt  time.Time
+	lastHeartbeatAt time.Time
+	lastPausedAt    map[string]time.Time // chain -> last time seen paused, staleness counted from resuming
+	staleAlertedAt  map[string]time.Time // chain -> last time alerted
+}
+
+// staleChain is a chain not health-checked for several intervals
+type staleChain struct {
+	chainName       string
+	staleFor        time.Duration
+	lastCompletedAt time.Time // zero if never completed
+	inFlight        bool
+}
+
+// watchdogResult is the outcome of a watchdog evaluation
+type watchdogResult struct {
+	heartbeat bool         // scheduling cycle completed, heartbeat URL should be pinged
+	stale     []staleChain // chains to alert root users
+	recovered []string     // chains health-checked again after alerted as stale
+}
+
+// StartWatchdog starts the routine watching the progress of the health-checks dispatched by the scheduler
+func StartWatchdog(appCtx config.AppContext, scheduler *Scheduler) {
+	w := newWatchdog(appCtx, scheduler, time.Now().UTC())
+	go w.start()
+}
+
+func newWatchdog(appCtx config.AppContext, scheduler *Scheduler, now time.Time) *watchdog {
+	return &watchdog{
+		appCtx:       appCtx,
+		scheduler:    scheduler,
+		heartbeatUrl: appCtx.AppConfig.General.HeartbeatUrl,
+		httpClient: &http.Client{
+			Timeout: constants.HEARTBEAT_PING_TIMEOUT,
+		},
+		cycleStartedAt: now,
+		lastPausedAt:   make(map[string]time.Time),
+		staleAlertedAt: make(map[string]time.Time),
+	}
+}
+
+func (w *watchdog) start() {
+	logger := w.appCtx.Logger
+	defer libapp.TryRecoverAndExecuteExitFunctionIfRecovered(logger)
+
+	for {
+		time.Sleep(watchdogInterval)
+
+		if w.scheduler.dispatchCtx.Err() != nil {
+			// shutting down
+			return
+		}
+
+		result := w.evaluate(time.Now().UTC(), w.scheduler.getChainsStatus(), func(chainName string) bool {
+			paused, _ := chainreg.IsChainPausedRL(chainName)
+			return paused
+		})
+
+		// only the leader pings, otherwise a standby instance keeps the heartbeat alive while the leader is stuck
+		if result.heartbeat && w.heartbeatUrl != "" && lesvc.IsLeaderRL() {
+			if err := w.pingHeartbeat(); err != nil {
+				logger.Error("failed to ping heartbeat URL", "error", err.Error())
+			} else {
+				logger.Debug("pinged heartbeat URL")
+			}
+		}
+
+		for _, stale := range result.stale {
+			logger.Error("chain has not been health-checked for a while", "chain", stale.chainName, "stale-for", stale.staleFor, "last-completed", stale.lastCompletedAt, "in-flight", stale.inFlight)
+
+			lastCompleted := "never"
+			if !stale.lastCompletedAt.IsZero() {
+				lastCompleted = stale.lastCompletedAt.Format(time.RFC3339)
+			}
+			var inFlight string
+			if stale.inFlight {
+				inFlight = ", a health-check is stuck in progress"
+			}
+			w.enqueueToAllRootUsers(tptypes.SeverityFatal, fmt.Sprintf(
+				"[%s] watchdog: not health-checked for %s (last completed: %s%s), the daemon may be stuck",
+				stale.chainName, stale.staleFor.Truncate(time.Second), lastCompleted, inFlight,
+			))
+		}
+
+		for _, chainName := range result.recovered {
+			logger.Info("chain health-check resumed", "chain", chainName)
+			w.enqueueToAllRootUsers(tptypes.SeverityInfo, fmt.Sprintf("[%s] watchdog: health-check resumed", chainName))
+		}
+	}
+}
+
+// evaluate determines whether the scheduling cycle completed and which chains are stale, based on the progress of the health-checks
+func (w *watchdog) evaluate(now time.Time, chainsStatus []scheduledChainStatus, isPaused func(chainName string) bool) (result watchdogResult) {
+	staleThreshold := constants.WATCHDOG_STALE_HEALTH_CHECK_INTERVALS * w.scheduler.interval
+
+	cycleCompleted := true
+	activeChains := make(map[string]bool)
+	for _, chainStatus := range chainsStatus {
+		chainName := chainStatus.chainName
+
+		if isPaused(chainName) {
+			w.lastPausedAt[chainName] = now
+			delete(w.staleAlertedAt, chainName)
+			continue
+		}
+		activeChains[chainName] = true
+
+		if chainStatus.lastCompletedAt.Before(w.cycleStartedAt) {
+			cycleCompleted = false
+		}
+
+		checkedAt := chainStatus.lastCompletedAt
+		if chainStatus.addedAt.After(checkedAt) {
+			checkedAt = chainStatus.addedAt
+		}
+		if lastPausedAt := w.lastPausedAt[chainName]; lastPausedAt.After(checkedAt) {
+			checkedAt = lastPausedAt
+		}
+
+		staleFor := now.Sub(checkedAt)
+		alertedAt, alerted := w.staleAlertedAt[chainName]
+		if staleFor <= staleThreshold {
+			if alerted {
+				delete(w.staleAlertedAt, chainName)
+				result.recovered = append(result.recovered, chainName)
+			}
+			continue
+		}
+
+		if alerted && now.Sub(alertedAt) < constants.WATCHDOG_REMIND_STALE_INTERVAL {
+			continue
+		}
+
+		w.staleAlertedAt[chainName] = now
+		result.stale = append(result.stale, staleChain{
+			chainName:       chainName,
+			staleFor:        staleFor,
+			lastCompletedAt: chainStatus.lastCompletedAt,
+			inFlight:        chainStatus.inFlight,
+		})
+	}
+
+	// forget removed chains
+	for chainName := range w.staleAlertedAt {
+		if !activeChains[chainName] {
+			delete(w.staleAlertedAt, chainName)
+		}
+	}
+
+	if len(activeChains) == 0 {
+		// nothing to health-check, the scheduler is alive as long as the watchdog is
+		cycleCompleted = now.Sub(w.cycleStartedAt) >= w.scheduler.interval
+	}
+
+	if cycleCompleted && now.Sub(w.lastHeartbeatAt) >= constants.MIN_DURATION_BETWEEN_HEARTBEATS {
+		result.heartbeat = true
+		w.lastHeartbeatAt = now
+		w.cycleStartedAt = now
+	}
+
+	return
+}
+
+func (w *watchdog) pingHeartbeat() error {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.HEARTBEAT_PING_TIMEOUT)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.heartbeatUrl, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create heartbeat request")
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send heartbeat request")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat responded status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (w *watchdog) enqueueToAllRootUsers(severity tptypes.Severity, message string) {
+	for _, identity := range usereg.GetRootUsersIdentityRL() {
+		userRecord, found := usereg.GetUserRecordByIdentityRL(identity)
+		if !found || userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
+			continue
+		}
+
+		tpsvc.EnqueueMessageWL(tptypes.QueueMessage{
+			ReceiverID: userRecord.TelegramConfig.UserId,
+			Priority:   true,
+			Severity:   severity,
+			Message:    message,
+		})
+	}
+}
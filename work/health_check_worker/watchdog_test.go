@@ -0,0 +1,103 @@
+package health_check_worker
+
+import (
+	"github.com/bcdevtools/validator-health-check/config"
+	"github.com/bcdevtools/validator-health-check/constants"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_watchdog_evaluate(t *testing.T) {
+	interval := time.Minute
+	start := time.Now().UTC()
+	w := newWatchdog(config.AppContext{}, &Scheduler{interval: interval}, start)
+
+	paused := make(map[string]bool)
+	isPaused := func(chainName string) bool {
+		return paused[chainName]
+	}
+
+	chainsStatus := []scheduledChainStatus{
+		{chainName: "a", addedAt: start},
+		{chainName: "b", addedAt: start},
+	}
+
+	result := w.evaluate(start.Add(watchdogInterval), chainsStatus, isPaused)
+	require.False(t, result.heartbeat, "no chain completed yet")
+
+	chainsStatus[0].lastCompletedAt = start.Add(10 * time.Second)
+	result = w.evaluate(start.Add(2*watchdogInterval), chainsStatus, isPaused)
+	require.False(t, result.heartbeat, "chain b not yet completed")
+
+	chainsStatus[1].lastCompletedAt = start.Add(40 * time.Second)
+	result = w.evaluate(start.Add(time.Minute), chainsStatus, isPaused)
+	require.True(t, result.heartbeat, "all chains completed")
+	require.Empty(t, result.stale)
+
+	result = w.evaluate(start.Add(time.Minute+watchdogInterval), chainsStatus, isPaused)
+	require.False(t, result.heartbeat, "new cycle not yet completed")
+
+	// chain b hangs
+	chainsStatus[0].lastCompletedAt = start.Add(4 * time.Minute)
+	chainsStatus[1].inFlight = true
+	now := start.Add(40*time.Second + constants.WATCHDOG_STALE_HEALTH_CHECK_INTERVALS*interval + time.Second)
+	result = w.evaluate(now, chainsStatus, isPaused)
+	require.False(t, result.heartbeat, "stuck chain should stop the heartbeat")
+	require.Len(t, result.stale, 1)
+	require.Equal(t, "b", result.stale[0].chainName)
+	require.True(t, result.stale[0].inFlight)
+
+	result = w.evaluate(now.Add(watchdogInterval), chainsStatus, isPaused)
+	require.Empty(t, result.stale, "should not alert again before reminder interval")
+
+	chainsStatus[0].lastCompletedAt = now.Add(constants.WATCHDOG_REMIND_STALE_INTERVAL)
+	result = w.evaluate(now.Add(constants.WATCHDOG_REMIND_STALE_INTERVAL), chainsStatus, isPaused)
+	require.Len(t, result.stale, 1, "should remind")
+	require.Equal(t, "b", result.stale[0].chainName)
+
+	// chain b resumes
+	now = now.Add(constants.WATCHDOG_REMIND_STALE_INTERVAL + watchdogInterval)
+	chainsStatus[0].lastCompletedAt = now
+	chainsStatus[1].lastCompletedAt = now
+	chainsStatus[1].inFlight = false
+	result = w.evaluate(now, chainsStatus, isPaused)
+	require.True(t, result.heartbeat)
+	require.Equal(t, []string{"b"}, result.recovered)
+
+	// paused chains are neither stale nor blocking the heartbeat
+	paused["b"] = true
+	now = now.Add(10 * interval)
+	chainsStatus[0].lastCompletedAt = now
+	result = w.evaluate(now, chainsStatus, isPaused)
+	require.True(t, result.heartbeat)
+	require.Empty(t, result.stale)
+
+	// staleness is counted from resuming
+	paused["b"] = false
+	result = w.evaluate(now.Add(interval), chainsStatus, isPaused)
+	require.Empty(t, result.stale)
+}
+
+func Test_watchdog_pingHeartbeat(t *testing.T) {
+	var pinged int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pinged++
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	w := newWatchdog(config.AppContext{}, &Scheduler{}, time.Now())
+
+	w.heartbeatUrl = server.URL + "/ok"
+	require.NoError(t, w.pingHeartbeat())
+
+	w.heartbeatUrl = server.URL + "/fail"
+	require.Error(t, w.pingHeartbeat())
+
+	require.Equal(t, 2, pinged)
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	libapp "github.com/EscanBE/go-lib/app"
-	"github.com/EscanBE/go-lib/logging"
 	"github.com/bcdevtools/validator-health-check/codec"
 	"github.com/bcdevtools/validator-health-check/config"
 	"github.com/bcdevtools/validator-health-check/constants"
@@ -15,18 +14,17 @@ import (
 	usereg "github.com/bcdevtools/validator-health-check/registry/user_registry"
 	valaddreg "github.com/bcdevtools/validator-health-check/registry/validator_address_registry"
 	tpsvc "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc"
+	tptemplates "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/templates"
 	tptypes "github.com/bcdevtools/validator-health-check/services/telegram_push_message_svc/types"
 	"github.com/bcdevtools/validator-health-check/utils"
 	workertypes "github.com/bcdevtools/validator-health-check/work/health_check_worker/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/bech32"
 	"github.com/cosmos/cosmos-sdk/types/query"
-	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
 	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"github.com/pkg/errors"
 	coretypes "github.com/tendermint/tendermint/rpc/core/types"
-	"sort"
 	"strings"
 	"time"
 )
@@ -43,659 +41,869 @@ func NewHcWorker(wCtx *workertypes.HcwContext) Worker {
 	}
 }
 
-// Start performs business logic of worker
-func (w Worker) Start() {
+// Start performs business logic of worker, health-check the chains dispatched by the scheduler until it is shut down
+func (w Worker) Start(scheduler *Scheduler) {
 	logger := w.ctx.AppCtx.Logger
 	defer libapp.TryRecoverAndExecuteExitFunctionIfRecovered(logger)
-
-	healthCheckInterval := w.ctx.AppCtx.AppConfig.General.HealthCheckInterval
-	if healthCheckInterval < 30*time.Second {
-		healthCheckInterval = 30 * time.Second
-	}
+	defer scheduler.workers.Done()
 
 	for {
-		time.Sleep(30 * time.Millisecond)
-
-		registeredChainConfig := chainreg.GetFirstChainConfigForHealthCheckRL(healthCheckInterval)
-		if registeredChainConfig == nil {
-			//logger.Debug("no chain to health-check", "wid", w.Ctx.WorkerID)
-			continue
+		registeredChainConfig, ok := scheduler.next()
+		if !ok {
+			logger.Debug("health-check worker stopped", "wid", w.ctx.WorkerID)
+			return
 		}
 
-		func(registeredChainConfig chainreg.RegisteredChainConfig) {
-			allWatchersIdentity := make([]string, 0)
-			watchersIdentityToUserRecord := make(map[string]config.UserRecord)
-			for _, validator := range registeredChainConfig.GetValidators() {
-				for _, identity := range validator.WatchersIdentity {
-					userRecord, found := usereg.GetUserRecordByIdentityRL(identity)
-					if !found {
-						continue
-					}
-					if userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
-						panic(fmt.Sprintf("telegram config is empty or incomplete, weird! identity: %s", identity))
-					}
-					allWatchersIdentity = append(allWatchersIdentity, identity)
-					watchersIdentityToUserRecord[identity] = userRecord
-				}
-			}
+		func() {
+			defer scheduler.done(registeredChainConfig.GetChainName())
+			w.healthCheckChain(scheduler.checkCtx, registeredChainConfig)
+		}()
+	}
+}
 
-			chainName := registeredChainConfig.GetChainName()
-			logger.Debug("health-checking chain", "chain", chainName, "wid", w.ctx.WorkerID)
+// healthCheckChain performs health-check a chain and its validators.
+// The context is cancelled when the application is shutting down and draining in-flight health-checks timed out.
+func (w Worker) healthCheckChain(ctx context.Context, registeredChainConfig chainreg.RegisteredChainConfig) {
+	logger := w.ctx.AppCtx.Logger
 
-			var countEnqueuedTelegramMessages int
+	allWatchersIdentity := make([]string, 0)
+	watchersIdentityToUserRecord := make(map[string]config.UserRecord)
+	for _, validator := range registeredChainConfig.GetValidators() {
+		for _, identity := range validator.WatchersIdentity {
+			userRecord, found := usereg.GetUserRecordByIdentityRL(identity)
+			if !found {
+				continue
+			}
+			if userRecord.TelegramConfig.IsEmptyOrIncompleteConfig() {
+				panic(fmt.Sprintf("telegram config is empty or incomplete, weird! identity: %s", identity))
+			}
+			allWatchersIdentity = append(allWatchersIdentity, identity)
+			watchersIdentityToUserRecord[identity] = userRecord
+		}
+	}
 
-			defer func() {
-				logger.Info("enqueued telegram messages", "count", countEnqueuedTelegramMessages, "chain", chainName)
-			}()
+	chainName := registeredChainConfig.GetChainName()
+	logger.Debug("health-checking chain", "chain", chainName, "wid", w.ctx.WorkerID)
 
-			type conditionalMessage struct {
-				message        string
-				messageForRoot string
-			}
+	var countEnqueuedTelegramMessages int
 
-			enqueueTelegramMessageByIdentity := func(validator string, condMsg conditionalMessage, fatal bool, identities ...string) {
-				countEnqueuedTelegramMessages++
-				for _, identity := range identities {
-					userRecord, found := watchersIdentityToUserRecord[identity]
-					if !found {
-						logger.Error("can not enqueue telegram message, user not found", "validator", validator, "chain", chainName, "fatal", fatal, "identity", identity, "message", condMsg.message)
-						continue
-					}
+	defer func() {
+		logger.Info("enqueued telegram messages", "count", countEnqueuedTelegramMessages, "chain", chainName)
+	}()
 
-					message := condMsg.message
-					if condMsg.messageForRoot != "" && userRecord.Root {
-						message = condMsg.messageForRoot
-					}
+	type conditionalMessage struct {
+		alertType      string // selects the template to render the message
+		message        string
+		messageForRoot string
+	}
 
-					var messagePrefix string
-					if fatal {
-						messagePrefix += "*FATAL!!*"
-					}
-					messagePrefix += fmt.Sprintf("[%s]", chainName)
-					if validator != "" {
-						messagePrefix += fmt.Sprintf("[%s]", validator)
-					}
-					message = fmt.Sprintf("%s %s", messagePrefix, message)
+	// enqueueTelegramMessageByIdentity records the finding and enqueues the message to the identities,
+	// the finding is recorded as suppressed if no identity, eg: suppressed by spam prevention.
+	enqueueTelegramMessageByIdentity := func(validator string, condMsg conditionalMessage, severity tptypes.Severity, identities ...string) {
+		suppressed := len(identities) == 0
+		recordFindingWL(chainName, validator, condMsg.alertType, severity, condMsg.message, suppressed, time.Now().UTC())
+		if !suppressed {
+			countEnqueuedTelegramMessages++
+		}
 
-					tpsvc.EnqueueMessageWL(tptypes.QueueMessage{
-						ReceiverID: userRecord.TelegramConfig.UserId,
-						Priority:   userRecord.Root,
-						Message:    message,
-					})
+		for _, identity := range identities {
+			userRecord, found := watchersIdentityToUserRecord[identity]
+			if !found {
+				logger.Error("can not enqueue telegram message, user not found", "validator", validator, "chain", chainName, "severity", severity, "identity", identity, "message", condMsg.message)
+				continue
+			}
 
-					logger.Debug("enqueued telegram message by identity", "message", message, "identity", identity)
-				}
+			message := condMsg.message
+			if condMsg.messageForRoot != "" && userRecord.Root {
+				message = condMsg.messageForRoot
 			}
 
-			var healthCheckError error
-			defer func() {
-				if healthCheckError == nil {
-					logger.Debug("health-check successfully", "chain", chainName)
-					return
-				}
+			message, parseMode := tptemplates.RenderAlertRL(tptemplates.AlertData{
+				Type:         condMsg.alertType,
+				Severity:     severity,
+				Chain:        chainName,
+				Validator:    validator,
+				ValidatorUrl: registeredChainConfig.GetExplorerValidatorUrl(validator),
+				Message:      message,
+			})
 
-				logger.Error("failed to health-check chain", "chain", chainName, "error", healthCheckError.Error())
-				enqueueTelegramMessageByIdentity(
-					"",
-					conditionalMessage{
-						message: fmt.Sprintf("failed to health-check, error: %s", healthCheckError.Error()),
-					},
-					false,
-					allWatchersIdentity...,
-				)
-			}()
+			tpsvc.EnqueueMessageWL(tptypes.QueueMessage{
+				ReceiverID: userRecord.TelegramConfig.UserId,
+				Priority:   userRecord.Root,
+				Severity:   severity,
+				Chain:      chainName,
+				Message:    message,
+				ParseMode:  parseMode,
+			})
 
-			// get the most healthy RPC
-			rpcClient, mostHealthyEndpoint, latestBlockTime, errFetchHealthyRpc := getMostHealthyRpc(registeredChainConfig.GetRPCs(), registeredChainConfig.GetChainId(), logger)
-			if errFetchHealthyRpc != nil {
-				healthCheckError = errors.Wrap(errFetchHealthyRpc, "failed to get most healthy RPC")
-				return
-			}
+			logger.Debug("enqueued telegram message by identity", "message", message, "identity", identity)
+		}
+	}
 
-			logger.Debug("most healthy RPC", "chain", chainName, "endpoint", mostHealthyEndpoint, "latest_block_time", latestBlockTime)
-			if outdated := time.Since(latestBlockTime); outdated > constants.INFORM_TELEGRAM_IF_BLOCK_OLDER_THAN {
-				enqueueTelegramMessageByIdentity(
-					"",
-					conditionalMessage{
-						message:        fmt.Sprintf("latest block time of the most healthy RPC is too old: %s, diff %s", latestBlockTime, outdated),
-						messageForRoot: fmt.Sprintf("latest block time of the most healthy RPC is too old: %s, diff %s, endpoint: %s", latestBlockTime, outdated, mostHealthyEndpoint),
-					},
-					false,
-					allWatchersIdentity...,
-				)
-			}
+	var healthCheckError error
+	defer func() {
+		if healthCheckError == nil {
+			logger.Debug("health-check successfully", "chain", chainName)
+			return
+		}
 
-			registeredChainConfig.InformPriorityLatestHealthyRpcWL(mostHealthyEndpoint)
+		if ctx.Err() != nil {
+			// cancelled, e.g. shutting down after drain timeout, not a failure of the chain
+			logger.Info("health-check cancelled", "chain", chainName, "error", healthCheckError.Error())
+			return
+		}
 
-			// fetch all validators
-			stakingValidators, errFetchStakingValidators := getAllValidators(rpcClient)
-			if errFetchStakingValidators != nil {
-				healthCheckError = errors.Wrap(errFetchStakingValidators, "failed to get all validators")
-				return
-			}
-			stakingValidatorByValoper := make(map[string]stakingtypes.Validator)
-			for _, validator := range stakingValidators {
-				stakingValidatorByValoper[validator.OperatorAddress] = validator
-			}
+		logger.Error("failed to health-check chain", "chain", chainName, "error", healthCheckError.Error())
+		enqueueTelegramMessageByIdentity(
+			"",
+			conditionalMessage{
+				alertType: tptemplates.AlertTypeHealthCheckFailed,
+				message:   fmt.Sprintf("failed to health-check, error: %s", healthCheckError.Error()),
+			},
+			tptypes.SeverityWarning,
+			allWatchersIdentity...,
+		)
+	}()
+
+	// refresh the RPC pool of the chain, queries are executed on the best endpoint and fail over to the next-best ones
+	rpcPool := rpcreg.GetRpcPoolByChainWL(chainName, registeredChainConfig.GetRPCs(), logger)
+	refreshCtx, cancelRefresh := newQueryContext(ctx)
+	bestRpc, errRefreshRpcPool := rpcPool.Refresh(refreshCtx, registeredChainConfig.GetChainId())
+	cancelRefresh()
+	if ctx.Err() == nil { // not shutting down
+		recordRpcAvailabilityWL(chainName, errRefreshRpcPool == nil, time.Now().UTC())
+	}
+	if errRefreshRpcPool != nil {
+		healthCheckError = errors.Wrap(errRefreshRpcPool, "failed to get most healthy RPC")
+		return
+	}
+	latestBlockHeight := bestRpc.LatestBlockHeight
+	latestBlockTime := bestRpc.LatestBlockTime
+	if streamed, found := getCacheStreamedBlockRL(chainName); found && streamed.Height > latestBlockHeight {
+		// the streaming subscription received a newer block
+		latestBlockHeight = streamed.Height
+		latestBlockTime = streamed.BlockTime
+	}
 
-			// reload mapping
-			w.reloadMappingValAddressIfNeeded(registeredChainConfig, stakingValidators)
+	logger.Debug("most healthy RPC", "chain", chainName, "endpoint", bestRpc.Endpoint, "latest_block", latestBlockHeight, "latest_block_time", latestBlockTime, "latency", bestRpc.LatencyEwma)
+	if outdated := time.Since(latestBlockTime); outdated > constants.INFORM_TELEGRAM_IF_BLOCK_OLDER_THAN {
+		enqueueTelegramMessageByIdentity(
+			"",
+			conditionalMessage{
+				alertType:      tptemplates.AlertTypeBlockOutdated,
+				message:        fmt.Sprintf("latest block time of the most healthy RPC is too old: %s, diff %s", latestBlockTime, outdated),
+				messageForRoot: fmt.Sprintf("latest block time of the most healthy RPC is too old: %s, diff %s, endpoint: %s", latestBlockTime, outdated, bestRpc.Endpoint),
+			},
+			tptypes.SeverityCritical,
+			allWatchersIdentity...,
+		)
+	}
 
-			// fetch all signingInfos
-			signingInfos, errFetchSigningInfo := getAllSigningInfos(rpcClient)
-			if errFetchSigningInfo != nil {
-				enqueueTelegramMessageByIdentity(
-					"",
-					conditionalMessage{
-						message: fmt.Sprintf("failed to get all validator signing infos, for uptime-check, error: %s", errFetchSigningInfo.Error()),
-					},
-					false,
-					allWatchersIdentity...,
-				)
-			}
-			valconsToSigningInfo := make(map[string]slashingtypes.ValidatorSigningInfo)
-			for _, signingInfo := range signingInfos {
-				valconsToSigningInfo[signingInfo.Address] = signingInfo
-			}
+	// detect versions of the chain, to query and decode the responses properly
+	queryProfile := getChainQueryProfile(ctx, rpcPool, registeredChainConfig, logger)
+	logger.Debug("chain query profile", "chain", chainName, "sdk", queryProfile.SdkVersion, "gov", queryProfile.GovVersion)
 
-			// fetch slashing params
-			slashingParams, errFetchSlashingParams := getSlashingParams(rpcClient)
-			if errFetchSlashingParams != nil {
-				enqueueTelegramMessageByIdentity(
-					"",
-					conditionalMessage{
-						message: fmt.Sprintf("failed to get all slashing params, for uptime-check, error: %s", errFetchSlashingParams.Error()),
-					},
-					false,
-					allWatchersIdentity...,
-				)
-			}
+	// query validators, signing infos of watched validators and slashing params,
+	// the snapshot of the previous pass is reused if queried at the same or a few blocks earlier height
+	snapshot, errFetchStakingValidators, errFetchSigningInfo, errFetchSlashingParams := w.getChainQuerySnapshot(ctx, rpcPool, registeredChainConfig, latestBlockHeight)
+	if errFetchStakingValidators != nil {
+		healthCheckError = errors.Wrap(errFetchStakingValidators, "failed to get all validators")
+		return
+	}
+	if errFetchSigningInfo != nil {
+		enqueueTelegramMessageByIdentity(
+			"",
+			conditionalMessage{
+				alertType: tptemplates.AlertTypeQueryFailed,
+				message:   fmt.Sprintf("failed to get validator signing infos, for uptime-check, error: %s", errFetchSigningInfo.Error()),
+			},
+			tptypes.SeverityWarning,
+			allWatchersIdentity...,
+		)
+	}
+	if errFetchSlashingParams != nil {
+		enqueueTelegramMessageByIdentity(
+			"",
+			conditionalMessage{
+				alertType: tptemplates.AlertTypeQueryFailed,
+				message:   fmt.Sprintf("failed to get all slashing params, for uptime-check, error: %s", errFetchSlashingParams.Error()),
+			},
+			tptypes.SeverityWarning,
+			allWatchersIdentity...,
+		)
+	}
+
+	stakingValidatorByValoper := make(map[string]stakingtypes.Validator)
+	valoperToRank := make(map[string]int)
+	for i, validator := range snapshot.Validators {
+		stakingValidatorByValoper[validator.OperatorAddress] = validator
+		valoperToRank[validator.OperatorAddress] = i + 1
+	}
+	valconsToSigningInfo := snapshot.SigningInfos
+	slashingParams := snapshot.SlashingParams
+
+	// versions used by the majority of public RPCs, lazy computed when any validator needs direct health-check
+	var majorityNodeVersionsOfChain *nodeVersions
+	getMajorityNodeVersionsOfChain := func() nodeVersions {
+		if majorityNodeVersionsOfChain == nil {
+			majorityVersions := getMajorityNodeVersions(ctx, registeredChainConfig.GetRPCs(), registeredChainConfig.GetChainId(), logger)
+			majorityNodeVersionsOfChain = &majorityVersions
+		}
+		return *majorityNodeVersionsOfChain
+	}
 
-			// prepare ranking
-			sort.Slice(stakingValidators, func(i, j int) bool {
-				left := stakingValidators[i]
-				right := stakingValidators[j]
+	// health-check each validator
 
-				if left.IsBonded() == right.IsBonded() {
-					return left.Tokens.GT(right.Tokens)
-				}
+	for _, validator := range registeredChainConfig.GetValidators() {
+		valoperAddr := validator.ValidatorOperatorAddress
 
-				if left.IsBonded() {
-					return true
-				} else {
-					return false
-				}
-			})
-			valoperToRank := make(map[string]int)
-			for i, validator := range stakingValidators {
-				valoperToRank[validator.OperatorAddress] = i + 1
-			}
+		if paused, _ := chainreg.IsValidatorPausedRL(valoperAddr); paused {
+			logger.Info("validator paused, skipping health-check", "chain", chainName, "valoper", valoperAddr)
+			continue
+		}
 
-			// health-check each validator
+		cacheHc := CacheValidatorHealthCheck{
+			ChainName: chainName,
+			Valoper:   valoperAddr,
+		}
 
-			for _, validator := range registeredChainConfig.GetValidators() {
-				valoperAddr := validator.ValidatorOperatorAddress
+		stakingValidator, found := stakingValidatorByValoper[valoperAddr]
+		if !found {
+			enqueueTelegramMessageByIdentity(
+				valoperAddr,
+				conditionalMessage{
+					alertType: tptemplates.AlertTypeValidatorNotFound,
+					message:   "validator not found",
+				},
+				tptypes.SeverityWarning,
+				validator.WatchersIdentity...,
+			)
+			continue
+		}
 
-				if paused, _ := chainreg.IsValidatorPausedRL(valoperAddr); paused {
-					logger.Info("validator paused, skipping health-check", "chain", chainName, "valoper", valoperAddr)
-					continue
-				}
+		rank, found := valoperToRank[valoperAddr]
+		if found {
+			cacheHc.Rank = rank
+		}
 
-				cacheHc := CacheValidatorHealthCheck{
-					Valoper: valoperAddr,
-				}
+		moniker := stakingValidator.Description.Moniker
+		cacheHc.Moniker = moniker
+
+		switch stakingValidator.Status {
+		case stakingtypes.Bonded:
+			// all good
+		case stakingtypes.Unbonded:
+			enqueueTelegramMessageByIdentity(
+				valoperAddr,
+				conditionalMessage{
+					alertType: tptemplates.AlertTypeValidatorUnbonded,
+					message:   fmt.Sprintf("validator %s is un-bonded! Tombstoned?\nUse [/%s %s] to pause health-checking this validator", moniker, constants.CommandPause, valoperAddr),
+				},
+				tptypes.SeverityFatal,
+				validator.WatchersIdentity...,
+			)
+		case stakingtypes.Unbonding:
+			enqueueTelegramMessageByIdentity(
+				valoperAddr,
+				conditionalMessage{
+					alertType: tptemplates.AlertTypeValidatorUnbonding,
+					message: fmt.Sprintf("validator %s is unbonding! Fall-out of active set? Was jailed?%s", moniker, func() string {
+						if rank == 0 {
+							return ""
+						}
+						return fmt.Sprintf(" Rank %d.", rank)
+					}()),
+				},
+				tptypes.SeverityFatal,
+				validator.WatchersIdentity...,
+			)
+		default:
+			enqueueTelegramMessageByIdentity(
+				valoperAddr,
+				conditionalMessage{
+					alertType: tptemplates.AlertTypeValidatorBondStatus,
+					message:   fmt.Sprintf("unknown bond status %s", stakingValidator.Status),
+				},
+				tptypes.SeverityCritical,
+				validator.WatchersIdentity...,
+			)
+		}
 
-				stakingValidator, found := stakingValidatorByValoper[valoperAddr]
-				if !found {
-					enqueueTelegramMessageByIdentity(
-						valoperAddr,
-						conditionalMessage{
-							message: "validator not found",
-						},
-						false,
-						validator.WatchersIdentity...,
-					)
-					continue
-				}
+		cacheHc.BondStatus = &stakingValidator.Status
 
-				rank, found := valoperToRank[valoperAddr]
+		if errFetchSigningInfo == nil { // skip check if error on fetch, error message informed before
+			valconsAddr, found := valaddreg.GetValconsByValoperRL(chainName, valoperAddr)
+			cacheHc.Valcons = valconsAddr
+			if found {
+				signingInfo, found := valconsToSigningInfo[valconsAddr]
 				if found {
-					cacheHc.Rank = rank
-				}
-
-				moniker := stakingValidator.Description.Moniker
-				cacheHc.Moniker = moniker
+					if signingInfo.Tombstoned {
+						sendToWatchers := tpsvc.ShouldSendMessageWL(
+							tpsvc.PreventSpammingCaseTomeStoned,
+							validator.WatchersIdentity,
+							1*time.Hour,
+						)
+						enqueueTelegramMessageByIdentity(
+							valoperAddr,
+							conditionalMessage{
+								alertType: tptemplates.AlertTypeValidatorTombstoned,
+								message:   fmt.Sprintf("%s is TOMBSTONED! Contact to unsubscribing this validator", moniker),
+							},
+							tptypes.SeverityFatal,
+							sendToWatchers...,
+						)
+						bTrue := true
+						cacheHc.TomeStoned = &bTrue
+					} else if now := time.Now().UTC(); signingInfo.JailedUntil.After(now) {
+						sendToWatchers := tpsvc.ShouldSendMessageWL(
+							tpsvc.PreventSpammingCaseJailed,
+							validator.WatchersIdentity,
+							30*time.Minute,
+						)
+						enqueueTelegramMessageByIdentity(
+							valoperAddr,
+							conditionalMessage{
+								alertType: tptemplates.AlertTypeValidatorJailed,
+								message:   fmt.Sprintf("%s was Jailed until %s, %f minutes left", moniker, signingInfo.JailedUntil, signingInfo.JailedUntil.Sub(now).Minutes()),
+							},
+							tptypes.SeverityFatal,
+							sendToWatchers...,
+						)
+						bTrue := true
+						cacheHc.Jailed = &bTrue
+						cacheHc.JailedUntil = &signingInfo.JailedUntil
+					} else {
+						bFalse := false
+						cacheHc.Jailed = &bFalse
+
+						if signingInfo.MissedBlocksCounter > 0 {
+							if slashingParams != nil {
+								if slashingParams.MinSignedPerWindow.IsPositive() && slashingParams.SignedBlocksWindow > 0 {
+									var downtimeSlashingWhenMissedExcess int64
+									if slashingParams.MinSignedPerWindow.Equal(sdk.OneDec()) {
+										downtimeSlashingWhenMissedExcess = 0
+									} else {
+										downtimeSlashingWhenMissedExcess =
+											slashingParams.SignedBlocksWindow - slashingParams.MinSignedPerWindow.Mul(sdk.NewDec(slashingParams.SignedBlocksWindow)).Ceil().RoundInt64()
+									}
+									cacheHc.DowntimeSlashingWhenMissedExcess = &downtimeSlashingWhenMissedExcess
+
+									missedBlocksOverDowntimeSlashingRatio := utils.RatioOfInt64(signingInfo.MissedBlocksCounter, downtimeSlashingWhenMissedExcess)
+									if missedBlocksOverDowntimeSlashingRatio > 50.0 {
+										sendToWatchers := tpsvc.ShouldSendMessageWL(
+											tpsvc.PreventSpammingCaseMissedBlocksOverDangerousThreshold,
+											validator.WatchersIdentity,
+											15*time.Minute,
+										)
+										enqueueTelegramMessageByIdentity(
+											valoperAddr,
+											conditionalMessage{
+												alertType: tptemplates.AlertTypeMissedBlocks,
+												message: fmt.Sprintf(
+													"%s has missed more than half of the allowed blocks in the window, beware of being Jailed. Missed %d/%d, ratio %f%%, window %d blocks",
+													moniker,
+													signingInfo.MissedBlocksCounter,
+													downtimeSlashingWhenMissedExcess,
+													missedBlocksOverDowntimeSlashingRatio,
+													slashingParams.SignedBlocksWindow,
+												),
+											},
+											tptypes.SeverityFatal,
+											sendToWatchers...,
+										)
+									} else if missedBlocksOverDowntimeSlashingRatio > 10.0 {
+										sendToWatchers := tpsvc.ShouldSendMessageWL(
+											tpsvc.PreventSpammingCaseMissedBlocksOverDangerousThreshold,
+											validator.WatchersIdentity,
+											2*time.Hour,
+										)
+										enqueueTelegramMessageByIdentity(
+											valoperAddr,
+											conditionalMessage{
+												alertType: tptemplates.AlertTypeMissedBlocks,
+												message: fmt.Sprintf(
+													"%s has high missed-block-ratio. Missed %d/%d, ratio %f%%, window %d blocks",
+													moniker,
+													signingInfo.MissedBlocksCounter,
+													downtimeSlashingWhenMissedExcess,
+													missedBlocksOverDowntimeSlashingRatio,
+													slashingParams.SignedBlocksWindow,
+												),
+											},
+											tptypes.SeverityWarning,
+											sendToWatchers...,
+										)
+									}
 
-				switch stakingValidator.Status {
-				case stakingtypes.Bonded:
-					// all good
-				case stakingtypes.Unbonded:
+									uptime := 100.0 - utils.RatioOfInt64(signingInfo.MissedBlocksCounter, slashingParams.SignedBlocksWindow)
+									if uptime <= 90.0 {
+										var ignoreIfLastSentLessThan time.Duration
+										if uptime <= 65.0 {
+											ignoreIfLastSentLessThan = 15 * time.Minute
+										} else if uptime <= 75.0 {
+											ignoreIfLastSentLessThan = 30 * time.Minute
+										} else {
+											ignoreIfLastSentLessThan = 1 * time.Hour
+										}
+										sendToWatchers := tpsvc.ShouldSendMessageWL(
+											tpsvc.PreventSpammingCaseLowUptime,
+											validator.WatchersIdentity,
+											ignoreIfLastSentLessThan,
+										)
+										severity := tptypes.SeverityWarning
+										if uptime <= 70.0 {
+											severity = tptypes.SeverityFatal
+										}
+										enqueueTelegramMessageByIdentity(
+											valoperAddr,
+											conditionalMessage{
+												alertType: tptemplates.AlertTypeLowUptime,
+												message:   fmt.Sprintf("%s has low uptime %f%%", moniker, uptime),
+											},
+											severity,
+											sendToWatchers...,
+										)
+									}
+									cacheHc.Uptime = &uptime
+
+									logger.Debug(
+										"validator health-check information",
+										"uptime", fmt.Sprintf("%f%%", uptime),
+										"missed-block", fmt.Sprintf("%d/%d", signingInfo.MissedBlocksCounter, downtimeSlashingWhenMissedExcess),
+										"valoper", valoperAddr,
+										"chain", chainName,
+									)
+								}
+							} else {
+								enqueueTelegramMessageByIdentity(
+									valoperAddr,
+									conditionalMessage{
+										alertType: tptemplates.AlertTypeQueryFailed,
+										message:   fmt.Sprintf("skipped uptime health-check for %s because missing slashing params", moniker),
+									},
+									tptypes.SeverityInfo,
+									validator.WatchersIdentity...,
+								)
+							}
+							cacheHc.MissedBlockCount = &signingInfo.MissedBlocksCounter
+						} else {
+							logger.Debug("no missed block", "chain", chainName, "valoper", valoperAddr, "signing-info", signingInfo)
+						}
+					}
+				} else {
 					enqueueTelegramMessageByIdentity(
 						valoperAddr,
 						conditionalMessage{
-							message: fmt.Sprintf("validator %s is un-bonded! Tombstoned?\nUse [/%s %s] to pause health-checking this validator", moniker, constants.CommandPause, valoperAddr),
+							alertType: tptemplates.AlertTypeSigningInfoNotFound,
+							message:   fmt.Sprintf("validator %s signing info could not be found, valcons: %s", moniker, valconsAddr),
 						},
-						true,
+						tptypes.SeverityWarning,
 						validator.WatchersIdentity...,
 					)
-				case stakingtypes.Unbonding:
-					enqueueTelegramMessageByIdentity(
+					logger.Debug("validator signing info could not be found", "chain", chainName, "valcons", valconsAddr, "valoper", valoperAddr, "snapshot-height", snapshot.Height)
+				}
+			} else {
+				enqueueTelegramMessageByIdentity(
+					valoperAddr,
+					conditionalMessage{
+						alertType: tptemplates.AlertTypeConsensusAddressNotFound,
+						message:   fmt.Sprintf("validator %s consensus address not found in mapping", moniker),
+					},
+					tptypes.SeverityWarning,
+					validator.WatchersIdentity...,
+				)
+			}
+		}
+
+		if validator.OptionalHealthCheckRPC != "" {
+			func(validator chainreg.ValidatorOfRegisteredChainConfig, valoperAddr string) {
+				reportDirectHealthCheckFinding := func(preventSpammingCase tpsvc.PreventSpammingCase, finding error, severity tptypes.Severity, ignoreIfLastSentLessThan time.Duration) {
+					// tracked per validator, so findings of other validators watched by the same user are not suppressed
+					sendToWatchers := tpsvc.ShouldSendMessageForSubjectWL(
+						preventSpammingCase,
 						valoperAddr,
-						conditionalMessage{
-							message: fmt.Sprintf("validator %s is unbonding! Fall-out of active set? Was jailed?%s", moniker, func() string {
-								if rank == 0 {
-									return ""
-								}
-								return fmt.Sprintf(" Rank %d.", rank)
-							}()),
-						},
-						true,
-						validator.WatchersIdentity...,
+						validator.WatchersIdentity,
+						ignoreIfLastSentLessThan,
 					)
-				default:
 					enqueueTelegramMessageByIdentity(
 						valoperAddr,
 						conditionalMessage{
-							message: fmt.Sprintf("unknown bond status %s", stakingValidator.Status),
+							alertType: tptemplates.AlertTypeValidatorNode,
+							message:   finding.Error(),
 						},
-						true,
-						validator.WatchersIdentity...,
+						severity,
+						sendToWatchers...,
 					)
 				}
 
-				cacheHc.BondStatus = &stakingValidator.Status
-
-				if errFetchSigningInfo == nil { // skip check if error on fetch, error message informed before
-					valconsAddr, found := valaddreg.GetValconsByValoperRL(chainName, valoperAddr)
-					cacheHc.Valcons = valconsAddr
-					if found {
-						signingInfo, found := valconsToSigningInfo[valconsAddr]
-						if found {
-							if signingInfo.Tombstoned {
-								sendToWatchers := tpsvc.ShouldSendMessageWL(
-									tpsvc.PreventSpammingCaseTomeStoned,
-									validator.WatchersIdentity,
-									1*time.Hour,
-								)
-								if len(sendToWatchers) > 0 {
-									enqueueTelegramMessageByIdentity(
-										valoperAddr,
-										conditionalMessage{
-											message: fmt.Sprintf("%s is TOMBSTONED! Contact to unsubscribing this validator", moniker),
-										},
-										true,
-										sendToWatchers...,
-									)
-								}
-								bTrue := true
-								cacheHc.TomeStoned = &bTrue
-							} else if now := time.Now().UTC(); signingInfo.JailedUntil.After(now) {
-								sendToWatchers := tpsvc.ShouldSendMessageWL(
-									tpsvc.PreventSpammingCaseJailed,
-									validator.WatchersIdentity,
-									30*time.Minute,
-								)
-								if len(sendToWatchers) > 0 {
-									enqueueTelegramMessageByIdentity(
-										valoperAddr,
-										conditionalMessage{
-											message: fmt.Sprintf("%s was Jailed until %s, %f minutes left", moniker, signingInfo.JailedUntil, signingInfo.JailedUntil.Sub(now).Minutes()),
-										},
-										true,
-										sendToWatchers...,
-									)
-								}
-								bTrue := true
-								cacheHc.Jailed = &bTrue
-								cacheHc.JailedUntil = &signingInfo.JailedUntil
-							} else {
-								if signingInfo.MissedBlocksCounter > 0 {
-									if slashingParams != nil {
-										if slashingParams.MinSignedPerWindow.IsPositive() && slashingParams.SignedBlocksWindow > 0 {
-											var downtimeSlashingWhenMissedExcess int64
-											if slashingParams.MinSignedPerWindow.Equal(sdk.OneDec()) {
-												downtimeSlashingWhenMissedExcess = 0
-											} else {
-												downtimeSlashingWhenMissedExcess =
-													slashingParams.SignedBlocksWindow - slashingParams.MinSignedPerWindow.Mul(sdk.NewDec(slashingParams.SignedBlocksWindow)).Ceil().RoundInt64()
-											}
-											cacheHc.DowntimeSlashingWhenMissedExcess = &downtimeSlashingWhenMissedExcess
-
-											missedBlocksOverDowntimeSlashingRatio := utils.RatioOfInt64(signingInfo.MissedBlocksCounter, downtimeSlashingWhenMissedExcess)
-											if missedBlocksOverDowntimeSlashingRatio > 50.0 {
-												sendToWatchers := tpsvc.ShouldSendMessageWL(
-													tpsvc.PreventSpammingCaseMissedBlocksOverDangerousThreshold,
-													validator.WatchersIdentity,
-													15*time.Minute,
-												)
-												if len(sendToWatchers) > 0 {
-													enqueueTelegramMessageByIdentity(
-														valoperAddr,
-														conditionalMessage{
-															message: fmt.Sprintf(
-																"%s has missed more than half of the allowed blocks in the window, beware of being Jailed. Missed %d/%d, ratio %f%%, window %d blocks",
-																moniker,
-																signingInfo.MissedBlocksCounter,
-																downtimeSlashingWhenMissedExcess,
-																missedBlocksOverDowntimeSlashingRatio,
-																slashingParams.SignedBlocksWindow,
-															),
-														},
-														true,
-														sendToWatchers...,
-													)
-												}
-											} else if missedBlocksOverDowntimeSlashingRatio > 10.0 {
-												sendToWatchers := tpsvc.ShouldSendMessageWL(
-													tpsvc.PreventSpammingCaseMissedBlocksOverDangerousThreshold,
-													validator.WatchersIdentity,
-													2*time.Hour,
-												)
-												if len(sendToWatchers) > 0 {
-													enqueueTelegramMessageByIdentity(
-														valoperAddr,
-														conditionalMessage{
-															message: fmt.Sprintf(
-																"%s has high missed-block-ratio. Missed %d/%d, ratio %f%%, window %d blocks",
-																moniker,
-																signingInfo.MissedBlocksCounter,
-																downtimeSlashingWhenMissedExcess,
-																missedBlocksOverDowntimeSlashingRatio,
-																slashingParams.SignedBlocksWindow,
-															),
-														},
-														false,
-														sendToWatchers...,
-													)
-												}
-											}
-
-											uptime := 100.0 - utils.RatioOfInt64(signingInfo.MissedBlocksCounter, slashingParams.SignedBlocksWindow)
-											if uptime <= 90.0 {
-												var ignoreIfLastSentLessThan time.Duration
-												if uptime <= 65.0 {
-													ignoreIfLastSentLessThan = 15 * time.Minute
-												} else if uptime <= 75.0 {
-													ignoreIfLastSentLessThan = 30 * time.Minute
-												} else {
-													ignoreIfLastSentLessThan = 1 * time.Hour
-												}
-												sendToWatchers := tpsvc.ShouldSendMessageWL(
-													tpsvc.PreventSpammingCaseLowUptime,
-													validator.WatchersIdentity,
-													ignoreIfLastSentLessThan,
-												)
-												fatal := uptime <= 70.0
-												if len(sendToWatchers) > 0 {
-													enqueueTelegramMessageByIdentity(
-														valoperAddr,
-														conditionalMessage{
-															message: fmt.Sprintf("%s has low uptime %f%%", moniker, uptime),
-														},
-														fatal,
-														sendToWatchers...,
-													)
-												}
-											}
-											cacheHc.Uptime = &uptime
-
-											logger.Debug(
-												"validator health-check information",
-												"uptime", fmt.Sprintf("%f%%", uptime),
-												"missed-block", fmt.Sprintf("%d/%d", signingInfo.MissedBlocksCounter, downtimeSlashingWhenMissedExcess),
-												"valoper", valoperAddr,
-												"chain", chainName,
-											)
-										}
-									} else {
-										enqueueTelegramMessageByIdentity(
-											valoperAddr,
-											conditionalMessage{
-												message: fmt.Sprintf("skipped uptime health-check for %s because missing slashing params", moniker),
-											},
-											false,
-											validator.WatchersIdentity...,
-										)
-									}
-									cacheHc.MissedBlockCount = &signingInfo.MissedBlocksCounter
-								} else {
-									logger.Debug("no missed block", "chain", chainName, "valoper", valoperAddr, "signing-info", signingInfo)
+				var errorToReport error
+				severity := tptypes.SeverityWarning
+				ignoreIfLastSentLessThan := 15 * time.Minute
+
+				defer func() {
+					if errorToReport != nil {
+						cacheHc.NodeError = errorToReport.Error()
+						reportDirectHealthCheckFinding(tpsvc.PreventSpammingCaseDirectHealthCheckOptionalRPC, errorToReport, severity, ignoreIfLastSentLessThan)
+					}
+				}()
+
+				rpcClient, err := rpcreg.GetRpcClientByEndpointWL(validator.OptionalHealthCheckRPC, logger)
+				if err != nil {
+					errorToReport = errors.Wrapf(err, "failed to get RPC client to direct health-check validator %s: %s", moniker, validator.OptionalHealthCheckRPC)
+					return
+				}
+
+				resultStatus, err := utils.RetryWithContext(ctx, func() (*coretypes.ResultStatus, error) {
+					queryCtx, cancel := newQueryContext(ctx)
+					defer cancel()
+
+					return rpcClient.GetWebsocketClient().Status(queryCtx)
+				})
+				if err != nil {
+					errorToReport = errors.Wrapf(err, "failed to get status from direct health-check validator %s: %s", moniker, validator.OptionalHealthCheckRPC)
+					return
+				}
+
+				nodeLatestHeight := resultStatus.SyncInfo.LatestBlockHeight
+				nodeLagBlocks := latestBlockHeight - nodeLatestHeight
+				if nodeLagBlocks < 0 {
+					nodeLagBlocks = 0 // node is ahead of the most healthy RPC
+				}
+				cacheHc.NodeLatestHeight = &nodeLatestHeight
+				cacheHc.NodeLagBlocks = &nodeLagBlocks
+				cacheHc.NodeCatchingUp = &resultStatus.SyncInfo.CatchingUp
+				cacheHc.NodeVersion = resultStatus.NodeInfo.Version
+
+				if resultStatus.SyncInfo.CatchingUp {
+					errorToReport = fmt.Errorf("validator %s is catching up, block %d, time %v, %d blocks behind", moniker, nodeLatestHeight, resultStatus.SyncInfo.LatestBlockTime, nodeLagBlocks)
+					severity = tptypes.SeverityFatal
+				} else if diff := time.Since(resultStatus.SyncInfo.LatestBlockTime.UTC()); diff > 30*time.Second {
+					errorToReport = fmt.Errorf("validator %s is out dated %s, %d blocks behind, time %v, server time %v", moniker, explainDuration(diff), nodeLagBlocks, resultStatus.SyncInfo.LatestBlockTime, time.Now().UTC())
+					ignoreIfLastSentLessThan = 10 * time.Minute
+					severity = tptypes.SeverityFatal
+				} else if nodeLagBlocks >= constants.INFORM_TELEGRAM_IF_VALIDATOR_NODE_LAG_BLOCKS {
+					errorToReport = fmt.Errorf("validator %s is lagging %d blocks behind the most healthy RPC, block %d, chain head %d", moniker, nodeLagBlocks, nodeLatestHeight, latestBlockHeight)
+					ignoreIfLastSentLessThan = 10 * time.Minute
+				}
+
+				// peers
+				inboundPeers, outboundPeers, err := getNodePeersCount(ctx, rpcClient)
+				if err != nil {
+					logger.Error("failed to get peers count from direct health-check", "chain", chainName, "valoper", valoperAddr, "error", err.Error())
+				} else {
+					cacheHc.NodeInboundPeers = &inboundPeers
+					cacheHc.NodeOutboundPeers = &outboundPeers
+
+					var lowPeers []string
+					if validator.MinInboundPeers > 0 && inboundPeers < validator.MinInboundPeers {
+						lowPeers = append(lowPeers, fmt.Sprintf("inbound %d/%d", inboundPeers, validator.MinInboundPeers))
+					}
+					if validator.MinOutboundPeers > 0 && outboundPeers < validator.MinOutboundPeers {
+						lowPeers = append(lowPeers, fmt.Sprintf("outbound %d/%d", outboundPeers, validator.MinOutboundPeers))
+					}
+					if len(lowPeers) > 0 {
+						reportDirectHealthCheckFinding(
+							tpsvc.PreventSpammingCaseDirectHealthCheckLowPeers,
+							fmt.Errorf("validator %s node has low peers count: %s", moniker, strings.Join(lowPeers, ", ")),
+							func() tptypes.Severity {
+								if inboundPeers+outboundPeers == 0 {
+									return tptypes.SeverityFatal
 								}
-							}
-						} else {
-							enqueueTelegramMessageByIdentity(
-								valoperAddr,
-								conditionalMessage{
-									message: fmt.Sprintf("validator %s signing info could not be found, valcons: %s", moniker, valconsAddr),
-								},
-								false,
-								validator.WatchersIdentity...,
-							)
-							logger.Debug("validator signing info could not be found", "chain", chainName, "valcons", valconsAddr, "valoper", valoperAddr, "signing-info-size", len(signingInfos))
-						}
-					} else {
-						enqueueTelegramMessageByIdentity(
-							valoperAddr,
-							conditionalMessage{
-								message: fmt.Sprintf("validator %s consensus address not found in mapping", moniker),
-							},
-							false,
-							validator.WatchersIdentity...,
+								return tptypes.SeverityWarning
+							}(),
+							30*time.Minute,
 						)
 					}
 				}
 
-				if validator.OptionalHealthCheckRPC != "" {
-					func(validator chainreg.ValidatorOfRegisteredChainConfig, valoperAddr string) {
-						var errorToReport error
-						var fatal bool
-						ignoreIfLastSentLessThan := 15 * time.Minute
-
-						defer func() {
-							if errorToReport != nil {
-								sendToWatchers := tpsvc.ShouldSendMessageWL(
-									tpsvc.PreventSpammingCaseDirectHealthCheckOptionalRPC,
-									validator.WatchersIdentity,
-									ignoreIfLastSentLessThan,
-								)
-								if len(sendToWatchers) > 0 {
-									enqueueTelegramMessageByIdentity(
-										valoperAddr,
-										conditionalMessage{
-											message: errorToReport.Error(),
-										},
-										fatal,
-										sendToWatchers...,
-									)
-								}
-							}
-						}()
+				// version drift
+				versions, err := getNodeVersions(ctx, rpcClient)
+				if err != nil {
+					logger.Error("failed to get versions from direct health-check", "chain", chainName, "valoper", valoperAddr, "error", err.Error())
+				} else {
+					cacheHc.NodeAppVersion = versions.AppVersion
 
-						rpcClient, err := rpcreg.GetRpcClientByEndpointWL(validator.OptionalHealthCheckRPC, logger)
-						if err != nil {
-							errorToReport = errors.Wrapf(err, "failed to get RPC client to direct health-check validator %s: %s", moniker, validator.OptionalHealthCheckRPC)
-						} else {
-							resultStatus, err := utils.Retry(func() (*coretypes.ResultStatus, error) {
-								return rpcClient.GetWebsocketClient().Status(context.Background())
-							})
-							if err != nil {
-								errorToReport = errors.Wrapf(err, "failed to get status from direct health-check validator %s: %s", moniker, validator.OptionalHealthCheckRPC)
-							} else {
-								if resultStatus.SyncInfo.CatchingUp {
-									errorToReport = fmt.Errorf("validator %s is catching up, block %d, time %v", moniker, resultStatus.SyncInfo.LatestBlockHeight, resultStatus.SyncInfo.LatestBlockTime)
-									fatal = true
-								} else if diff := time.Since(resultStatus.SyncInfo.LatestBlockTime.UTC()); diff > 30*time.Second {
-									errorToReport = fmt.Errorf("validator %s is out dated %s, time %v, server time %v", moniker, explainDuration(diff), resultStatus.SyncInfo.LatestBlockTime, time.Now().UTC())
-									ignoreIfLastSentLessThan = 10 * time.Minute
-									fatal = true
-								}
-							}
-						}
-					}(validator, valoperAddr)
+					majorityVersions := getMajorityNodeVersionsOfChain()
+					var drifts []string
+					if majorityVersions.AppVersion != "" && versions.AppVersion != majorityVersions.AppVersion {
+						drifts = append(drifts, fmt.Sprintf("app version %s, majority %s", versions.AppVersion, majorityVersions.AppVersion))
+					}
+					if majorityVersions.NodeVersion != "" && versions.NodeVersion != majorityVersions.NodeVersion {
+						drifts = append(drifts, fmt.Sprintf("node version %s, majority %s", versions.NodeVersion, majorityVersions.NodeVersion))
+					}
+					if len(drifts) > 0 {
+						reportDirectHealthCheckFinding(
+							tpsvc.PreventSpammingCaseDirectHealthCheckVersionDrift,
+							fmt.Errorf("validator %s node version differs from the majority of public RPCs, upgraded? %s", moniker, strings.Join(drifts, "; ")),
+							tptypes.SeverityInfo,
+							2*time.Hour,
+						)
+					}
 				}
+			}(validator, valoperAddr)
+		}
 
-				putCacheValidatorHealthCheckWL(cacheHc)
+		putCacheValidatorHealthCheckWL(cacheHc)
+		recordValidatorHealthCheckWL(cacheHc, time.Now().UTC())
+	}
+
+	// health-check managed endpoints, failures are reported to root users watching this chain only
+	managedEndpointsCount := len(registeredChainConfig.GetHealthCheckRPCs()) +
+		len(registeredChainConfig.GetHealthCheckEvmRPCs()) +
+		len(registeredChainConfig.GetHealthCheckGrpcs()) +
+		len(registeredChainConfig.GetHealthCheckRests())
+	if managedEndpointsCount > 0 {
+		rootUsersIdentity := usereg.GetRootUsersIdentityRL()
+		rootUsersIdentityWatchingThisChain := utils.Collisions(rootUsersIdentity, allWatchersIdentity)
+		if len(rootUsersIdentityWatchingThisChain) == 0 {
+			logger.Info("no root user watching this chain to report, skipping health-check managed endpoints", "chain", chainName)
+		} else {
+			healthCheckManagedEndpoint := func(endpointType, endpoint string, preventSpammingCase tpsvc.PreventSpammingCase, healthCheck func() error) {
+				errorToReport := healthCheck()
+				if errorToReport == nil {
+					return
+				}
+
+				logger.Error("health-check managed endpoint failed", "chain", chainName, "type", endpointType, "endpoint", endpoint, "error", errorToReport.Error())
+				sendToWatchers := tpsvc.ShouldSendMessageForSubjectWL(
+					preventSpammingCase,
+					managedEndpointSubject(chainName, endpoint),
+					rootUsersIdentityWatchingThisChain,
+					30*time.Minute,
+				)
+				enqueueTelegramMessageByIdentity(
+					"",
+					conditionalMessage{
+						alertType: tptemplates.AlertTypeManagedEndpoint,
+						message:   errorToReport.Error(),
+					},
+					tptypes.SeverityWarning,
+					sendToWatchers...,
+				)
 			}
 
-			// health-check managed RPCs
-			if len(registeredChainConfig.GetHealthCheckRPCs()) > 0 {
-				rootUsersIdentity := usereg.GetRootUsersIdentityRL()
-				rootUsersIdentityWatchingThisChain := utils.Collisions(rootUsersIdentity, allWatchersIdentity)
-				if len(rootUsersIdentityWatchingThisChain) == 0 {
-					logger.Info("no root user watching this chain to report, skipping health-check managed RPCs", "chain", chainName)
-				} else {
-					for _, managedRPC := range registeredChainConfig.GetHealthCheckRPCs() {
-						func(managedRPC string, rootUsersIdentityWatchingThisChain []string) {
-							var errorToReport error
-
-							defer func() {
-								if errorToReport != nil {
-									logger.Error("health-check managed RPC failed", "chain", chainName, "managed_rpc", managedRPC, "error", errorToReport.Error())
-									sendToWatchers := tpsvc.ShouldSendMessageWL(
-										tpsvc.PreventSpammingCaseHealthCheckManagedRPC,
-										rootUsersIdentityWatchingThisChain,
-										30*time.Minute,
-									)
-									if len(sendToWatchers) > 0 {
-										enqueueTelegramMessageByIdentity(
-											"",
-											conditionalMessage{
-												message: errorToReport.Error(),
-											},
-											false,
-											sendToWatchers...,
-										)
-									}
-								}
-							}()
+			for _, managedRPC := range registeredChainConfig.GetHealthCheckRPCs() {
+				healthCheckManagedEndpoint(constants.ENDPOINT_TYPE_RPC, managedRPC, tpsvc.PreventSpammingCaseHealthCheckManagedRPC, func() error {
+					return healthCheckManagedRPC(ctx, chainName, registeredChainConfig.GetChainId(), managedRPC, logger)
+				})
+			}
 
-							rpcClient, err := rpcreg.GetRpcClientByEndpointWL(managedRPC, logger)
-							if err != nil {
-								errorToReport = errors.Wrapf(err, "failed to get RPC client to health-check managed RPC %s", managedRPC)
-								return
-							}
+			for _, managedEvmRPC := range registeredChainConfig.GetHealthCheckEvmRPCs() {
+				healthCheckManagedEndpoint(constants.ENDPOINT_TYPE_EVM_RPC, managedEvmRPC, tpsvc.PreventSpammingCaseHealthCheckManagedEvmRPC, func() error {
+					return healthCheckManagedEvmRPC(ctx, managedEvmRPC, latestBlockHeight, logger)
+				})
+			}
 
-							resultStatus, err := utils.Retry(func() (*coretypes.ResultStatus, error) {
-								return rpcClient.GetWebsocketClient().Status(context.Background())
-							})
-							if err != nil {
-								errorToReport = errors.Wrapf(err, "failed to get status for health-check managed RPC %s", managedRPC)
-								return
-							}
+			for _, managedGrpc := range registeredChainConfig.GetHealthCheckGrpcs() {
+				healthCheckManagedEndpoint(constants.ENDPOINT_TYPE_GRPC, managedGrpc, tpsvc.PreventSpammingCaseHealthCheckManagedGrpc, func() error {
+					return healthCheckManagedGrpc(ctx, managedGrpc, latestBlockHeight, logger)
+				})
+			}
 
-							if resultStatus.SyncInfo.CatchingUp {
-								errorToReport = fmt.Errorf("managed RPC node is catching up, block %d, time %v, RPC %s", resultStatus.SyncInfo.LatestBlockHeight, resultStatus.SyncInfo.LatestBlockTime, managedRPC)
-								return
-							}
+			for _, managedRest := range registeredChainConfig.GetHealthCheckRests() {
+				healthCheckManagedEndpoint(constants.ENDPOINT_TYPE_REST, managedRest, tpsvc.PreventSpammingCaseHealthCheckManagedRest, func() error {
+					return healthCheckManagedRest(ctx, managedRest, latestBlockHeight, logger)
+				})
+			}
 
-							if diff := time.Since(resultStatus.SyncInfo.LatestBlockTime.UTC()); diff >= 180*time.Second {
-								errorToReport = fmt.Errorf("managed RPC node is out dated %s, time %v, server time %v, RPC %s", explainDuration(diff), resultStatus.SyncInfo.LatestBlockTime, time.Now().UTC(), managedRPC)
-							}
-						}(managedRPC, rootUsersIdentityWatchingThisChain)
+			// inspect TLS certificates of managed HTTPS endpoints
+			tlsCertExpiryAlertLeadTimes := w.ctx.AppCtx.AppConfig.General.GetTlsCertExpiryAlertLeadTimes()
+			var managedHttpsEndpoints []string
+			for _, managedEndpoints := range [][]string{
+				registeredChainConfig.GetHealthCheckRPCs(),
+				registeredChainConfig.GetHealthCheckEvmRPCs(),
+				registeredChainConfig.GetHealthCheckGrpcs(),
+				registeredChainConfig.GetHealthCheckRests(),
+			} {
+				for _, managedEndpoint := range managedEndpoints {
+					if utils.IsHttpsEndpoint(managedEndpoint) {
+						managedHttpsEndpoints = append(managedHttpsEndpoints, managedEndpoint)
 					}
 				}
 			}
+			for _, managedHttpsEndpoint := range managedHttpsEndpoints {
+				verifyFinding, expiryFinding, expiryFatal := healthCheckManagedEndpointTLS(managedHttpsEndpoint, tlsCertExpiryAlertLeadTimes)
+				expirySeverity := tptypes.SeverityWarning
+				if expiryFatal {
+					expirySeverity = tptypes.SeverityCritical
+				}
 
-			// check validator voting governance
-			if lastCheck := getLastCheckGovByChainRL(chainName); time.Since(lastCheck) > 2*time.Hour {
-				// fetch the latest gov on voting period
-				latestProposalIdOnVotingPeriod, err := getLatestGovV1ProposalOnVotingPeriod(rpcClient)
-				if err != nil {
+				if verifyFinding != nil {
+					logger.Error("TLS certificate of managed endpoint is invalid", "chain", chainName, "endpoint", managedHttpsEndpoint, "error", verifyFinding.Error())
+					sendToWatchers := tpsvc.ShouldSendMessageForSubjectWL(
+						tpsvc.PreventSpammingCaseManagedEndpointTLS,
+						managedEndpointSubject(chainName, managedHttpsEndpoint),
+						rootUsersIdentityWatchingThisChain,
+						6*time.Hour,
+					)
 					enqueueTelegramMessageByIdentity(
 						"",
 						conditionalMessage{
-							message: fmt.Sprintf("failed to get latest proposal on voting period, error: %s", err.Error()),
+							alertType: tptemplates.AlertTypeManagedEndpointCertificate,
+							message:   verifyFinding.Error(),
 						},
-						false,
-						allWatchersIdentity...,
+						tptypes.SeverityCritical,
+						sendToWatchers...,
 					)
-				} else if latestProposalIdOnVotingPeriod != nil && *latestProposalIdOnVotingPeriod > 0 {
-					proposalId := *latestProposalIdOnVotingPeriod
-
-					putCacheLastCheckGovByChainWL(chainName)
-
-					for _, validator := range registeredChainConfig.GetValidators() {
-						valoperAddr := validator.ValidatorOperatorAddress
-
-						if paused, _ := chainreg.IsValidatorPausedRL(valoperAddr); paused {
-							logger.Info("validator paused, skipping checking gov", "chain", chainName, "valoper", valoperAddr)
-							continue
-						}
-
-						if !isVotedGovLessThan(valoperAddr, proposalId) {
-							continue
-						}
+				}
 
-						addr, found := valaddreg.GetAddressByValoperRL(valoperAddr)
-						if !found {
-							addrHrp, success := utils.GetAddrHrpFromValoperHrp(valoperAddr)
-							if !success {
-								panic(fmt.Sprintf("failed to get account address hrp from valoper hrp, weird! valoper: %s", valoperAddr))
-							}
+				if expiryFinding != nil {
+					// each lead time is alerted once per certificate and expired certificate is re-alerted on interval, no need to prevent spamming
+					logger.Error("TLS certificate of managed endpoint is expiring", "chain", chainName, "endpoint", managedHttpsEndpoint, "finding", expiryFinding.Error())
+					enqueueTelegramMessageByIdentity(
+						"",
+						conditionalMessage{
+							alertType: tptemplates.AlertTypeManagedEndpointCertificate,
+							message:   expiryFinding.Error(),
+						},
+						expirySeverity,
+						rootUsersIdentityWatchingThisChain...,
+					)
+				}
+			}
+		}
+	}
 
-							_, bzAddr, err := bech32.DecodeAndConvert(valoperAddr)
-							if err != nil {
-								panic(errors.Wrapf(err, "failed to decode valoper address, weird! valoper: %s", valoperAddr))
-							}
+	// check validator voting governance, each proposal on voting period is tracked separately
+	govVoteEscalationLeadTimes := w.ctx.AppCtx.AppConfig.General.GetGovVoteEscalationLeadTimes()
+	if lastCheck := getLastCheckGovByChainRL(chainName); time.Since(lastCheck) > 2*time.Hour || isGovVoteEscalationDueRL(chainName, govVoteEscalationLeadTimes, time.Now().UTC()) {
+		proposalsOnVotingPeriod, err := getGovProposalsOnVotingPeriod(ctx, rpcPool, registeredChainConfig)
+		if err != nil {
+			enqueueTelegramMessageByIdentity(
+				"",
+				conditionalMessage{
+					alertType: tptemplates.AlertTypeQueryFailed,
+					message:   fmt.Sprintf("failed to get proposals on voting period, error: %s", err.Error()),
+				},
+				tptypes.SeverityWarning,
+				allWatchersIdentity...,
+			)
+		} else {
+			putCacheLastCheckGovByChainWL(chainName)
+
+			now := time.Now().UTC()
+			govSnapshot := GovSnapshot{
+				UpdatedAt: now,
+			}
+			proposalIds := make([]uint64, 0, len(proposalsOnVotingPeriod))
+
+			for _, proposal := range proposalsOnVotingPeriod {
+				proposalIds = append(proposalIds, proposal.Id)
+				proposalSnapshot := GovProposalSnapshot{
+					Id:            proposal.Id,
+					Title:         proposal.Title,
+					VotingEndTime: proposal.VotingEndTime,
+					Votes:         make(map[string]string),
+				}
 
-							addr, err = bech32.ConvertAndEncode(addrHrp, bzAddr)
-							if err != nil {
-								panic(errors.Wrapf(err, "failed to convert and encode address, weird! valoper: %s, next HRP: %s", valoperAddr, addrHrp))
-							}
+				for _, validator := range registeredChainConfig.GetValidators() {
+					valoperAddr := validator.ValidatorOperatorAddress
 
-							valaddreg.RegisterPairValAddressToAddressWL(valoperAddr, addr)
-						}
+					if paused, _ := chainreg.IsValidatorPausedRL(valoperAddr); paused {
+						logger.Info("validator paused, skipping checking gov", "chain", chainName, "valoper", valoperAddr)
+						continue
+					}
 
-						latestVotedByValidator, err := getLatestVotedGovV1ProposalOnVotingPeriod(rpcClient, addr)
+					vote, cached := getCacheGovVoteRL(chainName, proposal.Id, valoperAddr)
+					if !cached {
+						queriedVote, err := getGovVote(ctx, rpcPool, registeredChainConfig, proposal.Id, getAccountAddressOfValoper(valoperAddr))
 						if err != nil {
 							enqueueTelegramMessageByIdentity(
 								valoperAddr,
 								conditionalMessage{
-									message: fmt.Sprintf("failed to get latest voted proposal on voting period, error: %s", err.Error()),
+									alertType: tptemplates.AlertTypeQueryFailed,
+									message:   fmt.Sprintf("failed to get vote on proposal %d, error: %s", proposal.Id, err.Error()),
 								},
-								false,
+								tptypes.SeverityWarning,
 								validator.WatchersIdentity...,
 							)
 							continue
 						}
-
-						var govSuggestionMessageToBeSent string
-						if latestVotedByValidator == nil {
-							govSuggestionMessageToBeSent = fmt.Sprintf(
-								"Proposal %d is on Voting period, validator %s need to participate",
-								proposalId, valoperAddr,
-							)
-						} else {
-							if *latestVotedByValidator < proposalId {
-								govSuggestionMessageToBeSent = fmt.Sprintf(
-									"Proposal %d is on Voting period, validator %s needs to participate. Latest voted %d",
-									proposalId, valoperAddr, *latestVotedByValidator,
-								)
-							}
-							putCacheVotedGovWL(valoperAddr, *latestVotedByValidator)
+						if queriedVote != nil {
+							vote = queriedVote.String()
+							putCacheGovVoteWL(chainName, proposal.Id, valoperAddr, vote)
 						}
-						if len(govSuggestionMessageToBeSent) > 0 {
-							sendToWatchers := tpsvc.ShouldSendMessageWL(
-								tpsvc.PreventSpammingCaseNotVotedGovernance,
-								validator.WatchersIdentity,
-								12*time.Hour,
-							)
-							if len(sendToWatchers) > 0 {
-								enqueueTelegramMessageByIdentity(
-									valoperAddr,
-									conditionalMessage{
-										message: govSuggestionMessageToBeSent,
-									},
-									false,
-									sendToWatchers...,
-								)
+					}
+
+					proposalSnapshot.Votes[valoperAddr] = vote // empty if not voted
+					if vote != "" {
+						continue
+					}
+
+					recordMissedGovVoteWL(valoperAddr, proposal.Id, now)
+
+					// remind at most every 12 hours per proposal, escalate as voting end approaches
+					severity := tptypes.SeverityWarning
+					sendToWatchers := tpsvc.ShouldSendMessageForSubjectWL(
+						tpsvc.PreventSpammingCaseNotVotedGovernance,
+						govVoteKey(chainName, proposal.Id, valoperAddr),
+						validator.WatchersIdentity,
+						12*time.Hour,
+					)
+					if proposal.VotingEndTime != nil {
+						if leadTime, escalate := shouldEscalateGovVoteWL(chainName, proposal.Id, valoperAddr, *proposal.VotingEndTime, govVoteEscalationLeadTimes, now); escalate {
+							sendToWatchers = validator.WatchersIdentity
+							if leadTime == govVoteEscalationLeadTimes[len(govVoteEscalationLeadTimes)-1] {
+								severity = tptypes.SeverityCritical
 							}
 						}
 					}
+					var sb strings.Builder
+					sb.WriteString(fmt.Sprintf("Proposal %d", proposal.Id))
+					if proposal.Title != "" {
+						sb.WriteString(fmt.Sprintf(" (%s)", proposal.Title))
+					}
+					sb.WriteString(" is on Voting period")
+					if proposal.VotingEndTime != nil {
+						sb.WriteString(fmt.Sprintf(", ends in %s", proposal.VotingEndTime.Sub(now).Truncate(time.Minute)))
+					}
+					sb.WriteString(fmt.Sprintf(", validator %s has NOT voted", valoperAddr))
+
+					enqueueTelegramMessageByIdentity(
+						valoperAddr,
+						conditionalMessage{
+							alertType: tptemplates.AlertTypeGovernance,
+							message:   sb.String(),
+						},
+						severity,
+						sendToWatchers...,
+					)
 				}
+
+				govSnapshot.Proposals = append(govSnapshot.Proposals, proposalSnapshot)
 			}
-		}(registeredChainConfig)
+
+			putCacheGovSnapshotWL(chainName, govSnapshot)
+			pruneCacheGovVotesWL(chainName, proposalIds)
+			pruneGovVoteEscalationStatesWL(now)
+		}
+	}
+}
+
+// getAccountAddressOfValoper returns the account address of the validator operator, used as voter of proposals
+func getAccountAddressOfValoper(valoperAddr string) string {
+	addr, found := valaddreg.GetAddressByValoperRL(valoperAddr)
+	if found {
+		return addr
+	}
+
+	addrHrp, success := utils.GetAddrHrpFromValoperHrp(valoperAddr)
+	if !success {
+		panic(fmt.Sprintf("failed to get account address hrp from valoper hrp, weird! valoper: %s", valoperAddr))
+	}
+
+	_, bzAddr, err := bech32.DecodeAndConvert(valoperAddr)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to decode valoper address, weird! valoper: %s", valoperAddr))
 	}
+
+	addr, err = bech32.ConvertAndEncode(addrHrp, bzAddr)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to convert and encode address, weird! valoper: %s, next HRP: %s", valoperAddr, addrHrp))
+	}
+
+	valaddreg.RegisterPairValAddressToAddressWL(valoperAddr, addr)
+	return addr
 }
 
 func (w Worker) reloadMappingValAddressIfNeeded(registeredChainConfig chainreg.RegisteredChainConfig, stakingValidators []stakingtypes.Validator) {
@@ -739,7 +947,7 @@ func (w Worker) reloadMappingValAddressIfNeeded(registeredChainConfig chainreg.R
 	}
 }
 
-func getAllValidators(rpcClient rpcreg.RpcClient) ([]stakingtypes.Validator, error) {
+func getAllValidators(ctx context.Context, querier rpcreg.RpcQuerier) ([]stakingtypes.Validator, error) {
 	const limit uint64 = 200 // luckily, this endpoint support large page size. 500 is no problem.
 
 	var stakingValidators []stakingtypes.Validator
@@ -760,8 +968,11 @@ func getAllValidators(rpcClient rpcreg.RpcClient) ([]stakingtypes.Validator, err
 			panic(errors.Wrap(err, "failed to marshal request, weird!"))
 		}
 
-		queryValidatorsResponse, err := utils.Retry[*stakingtypes.QueryValidatorsResponse](func() (*stakingtypes.QueryValidatorsResponse, error) {
-			resultABCIQuery, err := rpcClient.GetWebsocketClient().ABCIQuery(context.Background(), "/cosmos.staking.v1beta1.Query/Validators", bz)
+		queryValidatorsResponse, err := utils.RetryWithContext[*stakingtypes.QueryValidatorsResponse](ctx, func() (*stakingtypes.QueryValidatorsResponse, error) {
+			queryCtx, cancel := newQueryContext(ctx)
+			defer cancel()
+
+			resultABCIQuery, err := querier.ABCIQuery(queryCtx, "/cosmos.staking.v1beta1.Query/Validators", bz)
 			if err != nil {
 				return nil, err
 			}
@@ -792,247 +1003,70 @@ func getAllValidators(rpcClient rpcreg.RpcClient) ([]stakingtypes.Validator, err
 	return stakingValidators, nil
 }
 
-func getAllSigningInfos(rpcClient rpcreg.RpcClient) ([]slashingtypes.ValidatorSigningInfo, error) {
-	const limit uint64 = 200 // luckily, this endpoint support large page size. 500 is no problem.
-
-	var validatorSigningInfos []slashingtypes.ValidatorSigningInfo
-	var nextKey []byte
-	var stop = false
-	page := 1
-
-	for !stop {
-		req := slashingtypes.QuerySigningInfosRequest{
-			Pagination: &query.PageRequest{
-				Limit: limit,
-				Key:   nextKey,
-			},
-		}
-
-		bz, err := req.Marshal()
-		if err != nil {
-			panic(errors.Wrap(err, "failed to marshal request, weird!"))
-		}
-
-		querySigningInfosResponse, err := utils.Retry[*slashingtypes.QuerySigningInfosResponse](func() (*slashingtypes.QuerySigningInfosResponse, error) {
-			resultABCIQuery, err := rpcClient.GetWebsocketClient().ABCIQuery(context.Background(), "/cosmos.slashing.v1beta1.Query/SigningInfos", bz)
-			if err != nil {
-				return nil, err
-			}
-
-			if len(resultABCIQuery.Response.Value) == 0 {
-				return nil, fmt.Errorf("empty response value, weird")
-			}
-
-			querySigningInfosResponse := &slashingtypes.QuerySigningInfosResponse{}
-			err = querySigningInfosResponse.Unmarshal(resultABCIQuery.Response.Value)
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to unmarshal response, weird!")
-			}
-
-			return querySigningInfosResponse, nil
-		})
-
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to query validators signing infos")
-		}
-
-		nextKey = querySigningInfosResponse.Pagination.NextKey
-		stop = len(querySigningInfosResponse.Pagination.NextKey) == 0
-		validatorSigningInfos = append(validatorSigningInfos, querySigningInfosResponse.Info...)
-		page++
+func getSigningInfo(ctx context.Context, querier rpcreg.RpcQuerier, valcons string) (*slashingtypes.ValidatorSigningInfo, error) {
+	req := slashingtypes.QuerySigningInfoRequest{
+		ConsAddress: valcons,
 	}
 
-	return validatorSigningInfos, nil
-}
-
-func getSlashingParams(rpcClient rpcreg.RpcClient) (*slashingtypes.Params, error) {
-	req := slashingtypes.QueryParamsRequest{}
-
 	bz, err := req.Marshal()
 	if err != nil {
 		panic(errors.Wrap(err, "failed to marshal request, weird!"))
 	}
 
-	querySigningInfosResponse, err := utils.Retry[*slashingtypes.QueryParamsResponse](func() (*slashingtypes.QueryParamsResponse, error) {
-		resultABCIQuery, err := rpcClient.GetWebsocketClient().ABCIQuery(context.Background(), "/cosmos.slashing.v1beta1.Query/Params", bz)
+	querySigningInfoResponse, err := utils.RetryWithContext[*slashingtypes.QuerySigningInfoResponse](ctx, func() (*slashingtypes.QuerySigningInfoResponse, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		resultABCIQuery, err := querier.ABCIQuery(queryCtx, "/cosmos.slashing.v1beta1.Query/SigningInfo", bz)
 		if err != nil {
 			return nil, err
 		}
 
+		if resultABCIQuery.Response.Code != 0 {
+			if strings.Contains(resultABCIQuery.Response.Log, "not found") {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("query failed with code %d: %s", resultABCIQuery.Response.Code, resultABCIQuery.Response.Log)
+		}
+
 		if len(resultABCIQuery.Response.Value) == 0 {
 			return nil, fmt.Errorf("empty response value, weird")
 		}
 
-		queryParamResponse := &slashingtypes.QueryParamsResponse{}
-		err = queryParamResponse.Unmarshal(resultABCIQuery.Response.Value)
+		querySigningInfoResponse := &slashingtypes.QuerySigningInfoResponse{}
+		err = querySigningInfoResponse.Unmarshal(resultABCIQuery.Response.Value)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to unmarshal response, weird!")
 		}
 
-		return queryParamResponse, nil
-	})
-
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to query slashing params")
-	}
-
-	if querySigningInfosResponse == nil {
-		return nil, errors.New("empty response, weird!")
-	}
-
-	return &querySigningInfosResponse.Params, nil
-}
-
-func getMostHealthyRpc(rpc []string, chainId string, logger logging.Logger) (rpcreg.RpcClient, string, time.Time, error) {
-	if len(rpc) == 0 {
-		panic("no rpc to health-check")
-	}
-	type scoredRPC struct {
-		latestBlock     int64
-		latestBlockTime time.Time
-		endpoint        string
-		err             error
-	}
-	chanScoredRPC := make(chan scoredRPC, len(rpc))
-	for _, r := range rpc {
-		go func(r string) {
-			scoredRPC := scoredRPC{
-				endpoint: r,
-			}
-			defer func() {
-				r := recover()
-				if r != nil {
-					scoredRPC.err = fmt.Errorf("panic: %v", r)
-				}
-
-				chanScoredRPC <- scoredRPC
-			}()
-
-			rpcClient, err := rpcreg.GetRpcClientByEndpointWL(r, logger)
-			if err != nil {
-				scoredRPC.err = errors.Wrap(err, "failed to get RPC client")
-				return
-			}
-
-			wsClient := rpcClient.GetWebsocketClient()
-			if wsClient == nil {
-				scoredRPC.err = errors.New("websocket client is nil")
-				return
-			}
-
-			resultStatus, err := utils.Retry(func() (*coretypes.ResultStatus, error) {
-				return wsClient.Status(context.Background())
-			})
-
-			if err != nil {
-				scoredRPC.err = errors.Wrap(err, "failed to get status")
-				return
-			}
-
-			if resultStatus.NodeInfo.Network != chainId {
-				scoredRPC.err = fmt.Errorf("network mismatch, expected %s, got %s", chainId, resultStatus.NodeInfo.Network)
-				return
-			}
-
-			scoredRPC.latestBlock = resultStatus.SyncInfo.LatestBlockHeight
-			scoredRPC.latestBlockTime = resultStatus.SyncInfo.LatestBlockTime
-		}(r)
-	}
-
-	var scoredRPCs = make([]scoredRPC, len(rpc))
-	for i := 0; i < len(rpc); i++ {
-		scoredRPC := <-chanScoredRPC
-		scoredRPCs[i] = scoredRPC
-	}
-
-	sort.Slice(scoredRPCs, func(i, j int) bool {
-		left := scoredRPCs[i]
-		right := scoredRPCs[j]
-
-		if left.err != nil && right.err != nil {
-			return left.latestBlock > right.latestBlock
-		}
-
-		if left.err != nil {
-			return false
-		}
-
-		if right.err != nil {
-			return true
-		}
-
-		return left.latestBlock > right.latestBlock
+		return querySigningInfoResponse, nil
 	})
 
-	mostHealthyRPC := scoredRPCs[0]
-	if mostHealthyRPC.err != nil {
-		return nil, "", time.Time{}, mostHealthyRPC.err
-	}
-
-	rpcClient, err := rpcreg.GetRpcClientByEndpointWL(mostHealthyRPC.endpoint, logger)
-	if err != nil {
-		return nil, "", time.Time{}, errors.Wrap(err, "failed to get RPC client of the most healthy RPC")
-	}
-
-	return rpcClient, mostHealthyRPC.endpoint, mostHealthyRPC.latestBlockTime, nil
-}
-
-func getLatestGovV1ProposalOnVotingPeriod(rpcClient rpcreg.RpcClient) (*uint64, error) {
-	reqV1 := govv1.QueryProposalsRequest{
-		ProposalStatus: govv1.ProposalStatus_PROPOSAL_STATUS_VOTING_PERIOD,
-		Pagination: &query.PageRequest{
-			Limit:   1,
-			Reverse: true,
-		},
-	}
-
-	proposals, err := getGovV1Proposal(rpcClient, reqV1)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "failed to query signing info of %s", valcons)
 	}
 
-	if len(proposals) == 0 {
+	if querySigningInfoResponse == nil {
+		// validator has no signing info
 		return nil, nil
 	}
 
-	proposalId := proposals[0].Id
-	return &proposalId, nil
+	return &querySigningInfoResponse.ValSigningInfo, nil
 }
 
-func getLatestVotedGovV1ProposalOnVotingPeriod(rpcClient rpcreg.RpcClient, voter string) (*uint64, error) {
-	reqV1 := govv1.QueryProposalsRequest{
-		ProposalStatus: govv1.ProposalStatus_PROPOSAL_STATUS_VOTING_PERIOD,
-		Voter:          voter,
-		Pagination: &query.PageRequest{
-			Limit:   1,
-			Reverse: true,
-		},
-	}
-
-	proposals, err := getGovV1Proposal(rpcClient, reqV1)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(proposals) == 0 {
-		return nil, nil
-	}
-
-	proposalId := proposals[0].Id
-	return &proposalId, nil
-}
+func getSlashingParams(ctx context.Context, querier rpcreg.RpcQuerier) (*slashingtypes.Params, error) {
+	req := slashingtypes.QueryParamsRequest{}
 
-func getGovV1Proposal(
-	rpcClient rpcreg.RpcClient,
-	reqV1 govv1.QueryProposalsRequest,
-) ([]*govv1.Proposal, error) {
-	bz, err := reqV1.Marshal()
+	bz, err := req.Marshal()
 	if err != nil {
 		panic(errors.Wrap(err, "failed to marshal request, weird!"))
 	}
 
-	queryGovV1ProposalsResponse, err := utils.Retry[*govv1.QueryProposalsResponse](func() (*govv1.QueryProposalsResponse, error) {
-		resultABCIQuery, err := rpcClient.GetWebsocketClient().ABCIQuery(context.Background(), "/cosmos.gov.v1.Query/Proposals", bz)
+	querySigningInfosResponse, err := utils.RetryWithContext[*slashingtypes.QueryParamsResponse](ctx, func() (*slashingtypes.QueryParamsResponse, error) {
+		queryCtx, cancel := newQueryContext(ctx)
+		defer cancel()
+
+		resultABCIQuery, err := querier.ABCIQuery(queryCtx, "/cosmos.slashing.v1beta1.Query/Params", bz)
 		if err != nil {
 			return nil, err
 		}
@@ -1041,7 +1075,7 @@ func getGovV1Proposal(
 			return nil, fmt.Errorf("empty response value, weird")
 		}
 
-		queryParamResponse := &govv1.QueryProposalsResponse{}
+		queryParamResponse := &slashingtypes.QueryParamsResponse{}
 		err = queryParamResponse.Unmarshal(resultABCIQuery.Response.Value)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to unmarshal response, weird!")
@@ -1051,14 +1085,14 @@ func getGovV1Proposal(
 	})
 
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to query gov v1 proposals")
+		return nil, errors.Wrap(err, "failed to query slashing params")
 	}
 
-	if queryGovV1ProposalsResponse == nil {
+	if querySigningInfosResponse == nil {
 		return nil, errors.New("empty response, weird!")
 	}
 
-	return queryGovV1ProposalsResponse.Proposals, nil
+	return &querySigningInfosResponse.Params, nil
 }
 
 func explainDuration(d time.Duration) string {